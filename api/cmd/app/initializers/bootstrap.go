@@ -1,32 +1,65 @@
 package initializers
 
 import (
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/joho/godotenv"
-
-	"melodiapp/cmd/app/routes"
 )
 
-func Run() {
+func loadEnv() {
 	if os.Getenv("GIN_MODE") != "release" {
 		if err := godotenv.Load(); err != nil {
 			log.Printf("No .env file loaded (godotenv): %v. Continuing with existing environment variables.", err)
 		}
 	}
+}
+
+func Run() {
+	loadEnv()
+
+	app := NewContainer(InitDatabase())
 
-	InitDatabase()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	router := routes.NewRouter()
+	StartScheduler(ctx, app)
+	StartLive(ctx, app)
+	workersDone := StartWorkers(ctx, app)
+
+	router := app.Router()
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = ":8080"
 	}
+	server := &http.Server{Addr: port, Handler: router}
+
+	go func() {
+		log.Printf("Server running on port %s", port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Failed to start server:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown: %v", err)
+	}
 
-	log.Printf("Server running on port %s", port)
-	if err := router.Run(port); err != nil {
-		log.Fatal("Failed to start server:", err)
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		log.Println("Timed out waiting for job workers")
 	}
 }
@@ -0,0 +1,258 @@
+package initializers
+
+import (
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"melodiapp/cmd/app/routes"
+
+	attendanceapi "melodiapp/internal/adapters/api/attendance"
+	auditapi "melodiapp/internal/adapters/api/audit"
+	authapi "melodiapp/internal/adapters/api/auth"
+	calendarapi "melodiapp/internal/adapters/api/calendar"
+	dresscodeapi "melodiapp/internal/adapters/api/dresscode"
+	impactapi "melodiapp/internal/adapters/api/impact"
+	jobapi "melodiapp/internal/adapters/api/job"
+	liveapi "melodiapp/internal/adapters/api/live"
+	notificationapi "melodiapp/internal/adapters/api/notification"
+	outfitapi "melodiapp/internal/adapters/api/outfit"
+	positionapi "melodiapp/internal/adapters/api/position"
+	rehearsalapi "melodiapp/internal/adapters/api/rehearsal"
+	revisionapi "melodiapp/internal/adapters/api/revision"
+	rosterapi "melodiapp/internal/adapters/api/roster"
+	runsheetapi "melodiapp/internal/adapters/api/runsheet"
+	serviceapi "melodiapp/internal/adapters/api/service"
+	serviceoutfitapi "melodiapp/internal/adapters/api/serviceoutfit"
+	servicesongapi "melodiapp/internal/adapters/api/servicesong"
+	serviceuserapi "melodiapp/internal/adapters/api/serviceuser"
+	songapi "melodiapp/internal/adapters/api/song"
+	swapapi "melodiapp/internal/adapters/api/swap"
+	trashapi "melodiapp/internal/adapters/api/trash"
+	userapi "melodiapp/internal/adapters/api/user"
+	userblackoutapi "melodiapp/internal/adapters/api/userblackout"
+
+	dbattendance "melodiapp/internal/adapters/database/attendance"
+	dbaudit "melodiapp/internal/adapters/database/audit"
+	dbcalendar "melodiapp/internal/adapters/database/calendar"
+	dbdresscode "melodiapp/internal/adapters/database/dresscode"
+	dbimpact "melodiapp/internal/adapters/database/impact"
+	dbjob "melodiapp/internal/adapters/database/job"
+	dblive "melodiapp/internal/adapters/database/live"
+	dblock "melodiapp/internal/adapters/database/lock"
+	dbnotification "melodiapp/internal/adapters/database/notification"
+	dboutfit "melodiapp/internal/adapters/database/outfit"
+	dbposition "melodiapp/internal/adapters/database/position"
+	dbrehearsal "melodiapp/internal/adapters/database/rehearsal"
+	dbreminder "melodiapp/internal/adapters/database/reminder"
+	dbrevision "melodiapp/internal/adapters/database/revision"
+	dbrunsheet "melodiapp/internal/adapters/database/runsheet"
+	dbservice "melodiapp/internal/adapters/database/service"
+	dbserviceoutfit "melodiapp/internal/adapters/database/serviceoutfit"
+	dbservicesong "melodiapp/internal/adapters/database/servicesong"
+	dbserviceuser "melodiapp/internal/adapters/database/serviceuser"
+	dbsong "melodiapp/internal/adapters/database/song"
+	dbswap "melodiapp/internal/adapters/database/swap"
+	dbtransaction "melodiapp/internal/adapters/database/transaction"
+	dbuser "melodiapp/internal/adapters/database/user"
+	dbuserblackout "melodiapp/internal/adapters/database/userblackout"
+
+	emailchannel "melodiapp/internal/adapters/notification/email"
+	webhookchannel "melodiapp/internal/adapters/notification/webhook"
+
+	coreattendance "melodiapp/internal/core/attendance"
+	coreaudit "melodiapp/internal/core/audit"
+	coreauth "melodiapp/internal/core/auth"
+	corecalendar "melodiapp/internal/core/calendar"
+	coredresscode "melodiapp/internal/core/dresscode"
+	coreimpact "melodiapp/internal/core/impact"
+	corejob "melodiapp/internal/core/job"
+	corelive "melodiapp/internal/core/live"
+	corenotification "melodiapp/internal/core/notification"
+	coreoutfit "melodiapp/internal/core/outfit"
+	coreposition "melodiapp/internal/core/position"
+	corerehearsal "melodiapp/internal/core/rehearsal"
+	corereminder "melodiapp/internal/core/reminder"
+	corerevision "melodiapp/internal/core/revision"
+	coreroster "melodiapp/internal/core/roster"
+	corerunsheet "melodiapp/internal/core/runsheet"
+	coreservice "melodiapp/internal/core/service"
+	coreserviceoutfit "melodiapp/internal/core/serviceoutfit"
+	coreservicesong "melodiapp/internal/core/servicesong"
+	coreserviceuser "melodiapp/internal/core/serviceuser"
+	coresong "melodiapp/internal/core/song"
+	coreswap "melodiapp/internal/core/swap"
+	coretrash "melodiapp/internal/core/trash"
+	coreuser "melodiapp/internal/core/user"
+	coreuserblackout "melodiapp/internal/core/userblackout"
+)
+
+// Container es la raíz de composición: arma repositorios, casos de uso y
+// handlers una sola vez, todos sobre la misma conexión.
+type Container struct {
+	DB *gorm.DB
+
+	Users         *coreuser.Service
+	Notifications *corenotification.Service
+	Jobs          *corejob.Service
+	JobWorker     *corejob.Worker
+	Reminders     *corereminder.Service
+	Trash         *coretrash.Service
+	Live          *corelive.Service
+
+	deps routes.Dependencies
+}
+
+func NewContainer(db *gorm.DB) *Container {
+	userRepo := dbuser.NewGormUserRepository(db)
+	blackoutRepo := dbuserblackout.NewGormUserBlackoutRepository(db)
+	serviceRepo := dbservice.NewGormServiceRepository(db)
+	serviceUserRepo := dbserviceuser.NewGormServiceUserRepository(db)
+	serviceSongRepo := dbservicesong.NewGormServiceSongRepository(db)
+	serviceOutfitRepo := dbserviceoutfit.NewGormServiceOutfitRepository(db)
+	songRepo := dbsong.NewGormSongRepository(db)
+	positionRepo := dbposition.NewGormPositionRepository(db)
+	outfitRepo := dboutfit.NewGormOutfitRepository(db)
+	rehearsalRepo := dbrehearsal.NewGormRehearsalRepository(db)
+	attendanceRepo := dbattendance.NewGormAttendanceRepository(db)
+	runSheetRepo := dbrunsheet.NewGormRunSheetRepository(db)
+	jobRepo := dbjob.NewGormJobRepository(db)
+
+	jobs := corejob.NewService(jobRepo)
+	notifications := corenotification.NewService(
+		dbnotification.NewGormNotificationRepository(db),
+		userRepo,
+		jobs,
+		emailchannel.NewEmailChannel(),
+		webhookchannel.NewWebhookChannel(),
+	)
+
+	worker := corejob.NewWorker(corejob.WorkerConfigFromEnv(), jobRepo)
+	worker.Register(corenotification.DeliverJob, notifications.Deliver)
+
+	audit := coreaudit.NewService(dbaudit.NewGormAuditRepository(db))
+	revisionRepo := dbrevision.NewGormRevisionRepository(db)
+	// recorder audita y además versiona canciones y servicios.
+	recorder := corerevision.NewTracker(audit, revisionRepo, songRepo, serviceRepo, serviceSongRepo, serviceUserRepo, serviceOutfitRepo, runSheetRepo)
+
+	txManager := dbtransaction.NewGormTxManager(db)
+	users := coreuser.NewService(userRepo, audit)
+	services := coreservice.NewServiceUsecase(
+		serviceRepo,
+		serviceUserRepo,
+		serviceSongRepo,
+		serviceOutfitRepo,
+		songRepo,
+		userRepo,
+		positionRepo,
+		txManager,
+		notifications,
+		recorder,
+	)
+	serviceUsers := coreserviceuser.NewService(serviceUserRepo, serviceRepo, userRepo, blackoutRepo, positionRepo, notifications, recorder)
+	songs := coresong.NewService(songRepo, recorder)
+	positions := coreposition.NewService(positionRepo, serviceUserRepo)
+	runSheets := corerunsheet.NewService(runSheetRepo, serviceRepo, serviceSongRepo, userRepo)
+	live := corelive.NewService(dblive.NewGormLiveSessionRepository(db), dblive.NewPgLiveBus(db), runSheets, serviceUserRepo)
+
+	// SWAP_AUTO_APPROVE=true ejecuta el intercambio apenas alguien lo reclama.
+	swapPolicy := coreswap.Policy{AutoApprove: os.Getenv("SWAP_AUTO_APPROVE") == "true"}
+
+	locker := dblock.NewAdvisoryLocker(db)
+	trash := coretrash.NewService(coretrash.ConfigFromEnv(), locker, songRepo, serviceRepo, userRepo, recorder)
+
+	c := &Container{
+		DB:            db,
+		Users:         users,
+		Notifications: notifications,
+		Jobs:          jobs,
+		JobWorker:     worker,
+		Reminders: corereminder.NewService(
+			corereminder.ConfigFromEnv(),
+			dbreminder.NewGormReminderLogRepository(db),
+			locker,
+			serviceRepo,
+			serviceUserRepo,
+			rehearsalRepo,
+			positionRepo,
+			userRepo,
+			notifications,
+		),
+		Trash: trash,
+		Live:  live,
+	}
+
+	c.deps = routes.Dependencies{
+		LoadUser: userRepo.GetUserByUintID,
+		Ping:     c.ping,
+
+		User:          userapi.NewUserHandlers(users),
+		UserBlackout:  userblackoutapi.NewUserBlackoutHandlers(coreuserblackout.NewService(blackoutRepo)),
+		Auth:          authapi.NewAuthHandlers(coreauth.NewService(userRepo)),
+		Service:       serviceapi.NewServiceHandlers(services),
+		ServiceUser:   serviceuserapi.NewServiceUserHandlers(serviceUsers),
+		ServiceSong:   servicesongapi.NewServiceSongHandlers(coreservicesong.NewService(serviceSongRepo, songRepo, serviceRepo, serviceUserRepo, notifications, recorder)),
+		ServiceOutfit: serviceoutfitapi.NewServiceOutfitHandlers(coreserviceoutfit.NewService(serviceOutfitRepo, outfitRepo, serviceRepo, recorder)),
+		Song:          songapi.NewSongHandlers(songs),
+		Roster: rosterapi.NewRosterHandlers(coreroster.NewService(
+			serviceRepo,
+			serviceUserRepo,
+			userRepo,
+			blackoutRepo,
+			positionRepo,
+			attendanceRepo,
+			txManager,
+			notifications,
+			recorder,
+		)),
+		Position: positionapi.NewPositionHandlers(positions),
+		Swap: swapapi.NewSwapHandlers(coreswap.NewService(
+			dbswap.NewGormSwapRepository(db),
+			serviceUserRepo,
+			positionRepo,
+			serviceUsers,
+			userRepo,
+			notifications,
+			swapPolicy,
+		)),
+		Outfit: outfitapi.NewOutfitHandlers(coreoutfit.NewService(outfitRepo)),
+		DressCode: dresscodeapi.NewDressCodeHandlers(coredresscode.NewService(
+			dbdresscode.NewGormDressCodeRepository(db),
+			serviceRepo,
+			serviceOutfitRepo,
+			positionRepo,
+		)),
+		RunSheet: runsheetapi.NewRunSheetHandlers(runSheets),
+		Live:     liveapi.NewLiveHandlers(live),
+		Rehearsal: rehearsalapi.NewRehearsalHandlers(corerehearsal.NewService(
+			rehearsalRepo,
+			serviceRepo,
+			serviceUserRepo,
+			serviceSongRepo,
+		)),
+		Calendar: calendarapi.NewCalendarHandlers(corecalendar.NewService(serviceRepo, serviceUserRepo, rehearsalRepo, dbcalendar.NewGormCalendarTokenRepository(db), userRepo)),
+		Attendance: attendanceapi.NewAttendanceHandlers(coreattendance.NewService(
+			attendanceRepo,
+			serviceRepo,
+			serviceUserRepo,
+			rehearsalRepo,
+			userRepo,
+		)),
+		Notification: notificationapi.NewNotificationHandlers(notifications),
+		Job:          jobapi.NewJobHandlers(jobs),
+		Trash:        trashapi.NewTrashHandlers(trash),
+		Audit:        auditapi.NewAuditHandlers(audit),
+		Revision:     revisionapi.NewRevisionHandlers(corerevision.NewService(revisionRepo, songs, services)),
+		Impact:       impactapi.NewImpactHandlers(coreimpact.NewService(dbimpact.NewGormImpactRepository(db), serviceRepo)),
+	}
+	return c
+}
+
+func (c *Container) Router() *gin.Engine {
+	return routes.NewRouter(c.deps)
+}
+
+func (c *Container) ping() error {
+	return c.DB.Exec("SELECT 1").Error
+}
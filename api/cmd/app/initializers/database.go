@@ -1,18 +1,34 @@
 package initializers
 
 import (
+	"context"
 	"log"
+	"os"
+
+	"gorm.io/gorm"
 
 	"melodiapp/database"
-	"melodiapp/models"
+	"melodiapp/database/migrations"
 )
 
-func InitDatabase() {
+// InitDatabase conecta y aplica las migraciones pendientes.
+// Con MIGRATE_ON_START=false se omiten y quedan a cargo de `app migrate up`.
+func InitDatabase() *gorm.DB {
 	log.Println("Initializing database connection...")
 	database.CreateDbConnection()
 
-	if err := database.DBConn.AutoMigrate(&models.User{}, &models.Song{}, &models.ServiceSong{}); err != nil {
+	if os.Getenv("MIGRATE_ON_START") == "false" {
+		log.Println("Database initialized (migrations skipped)")
+		return database.DBConn
+	}
+
+	migrator, err := migrations.New(database.DBConn)
+	if err != nil {
+		log.Fatalf("failed to load database migrations: %v", err)
+	}
+	if _, err := migrator.Up(context.Background()); err != nil {
 		log.Fatalf("failed to run database migrations: %v", err)
 	}
 	log.Println("Database initialized and migrations applied")
+	return database.DBConn
 }
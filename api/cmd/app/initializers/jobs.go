@@ -0,0 +1,18 @@
+package initializers
+
+import (
+	"context"
+	"log"
+)
+
+// StartWorkers arranca los workers de la cola. El canal devuelto se cierra
+// cuando terminan los trabajos en curso tras cancelar ctx.
+func StartWorkers(ctx context.Context, app *Container) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		app.JobWorker.Run(ctx)
+	}()
+	log.Println("Job workers started")
+	return done
+}
@@ -0,0 +1,13 @@
+package initializers
+
+import (
+	"context"
+	"log"
+)
+
+// StartLive escucha los cambios en vivo hechos en otras instancias para
+// reenviarlos a los clientes conectados a esta.
+func StartLive(ctx context.Context, app *Container) {
+	go app.Live.Listen(ctx)
+	log.Println("Live listener started")
+}
@@ -0,0 +1,66 @@
+package initializers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	"melodiapp/database"
+	"melodiapp/database/migrations"
+)
+
+const migrateUsage = "usage: app migrate [up | down [N] | status]"
+
+// RunMigrate atiende el subcomando `migrate` sin levantar el servidor.
+func RunMigrate(args []string) {
+	loadEnv()
+	database.CreateDbConnection()
+
+	migrator, err := migrations.New(database.DBConn)
+	if err != nil {
+		log.Fatalf("failed to load database migrations: %v", err)
+	}
+
+	ctx := context.Background()
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("Database is up to date")
+		}
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil {
+				log.Fatal(migrateUsage)
+			}
+		}
+		if _, err := migrator.Down(ctx, steps); err != nil {
+			log.Fatal(err)
+		}
+	case "status":
+		status, err := migrator.Status(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, s := range status {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d  %-30s  %s\n", s.Version, s.Name, applied)
+		}
+	default:
+		log.Fatal(migrateUsage)
+	}
+}
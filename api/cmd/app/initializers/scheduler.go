@@ -0,0 +1,20 @@
+package initializers
+
+import (
+	"context"
+	"log"
+	"os"
+)
+
+// StartScheduler arranca los recordatorios y la purga de la papelera en segundo plano. SCHEDULER_ENABLED=false
+// lo apaga en una instancia (los advisory locks ya evitan trabajo duplicado).
+func StartScheduler(ctx context.Context, app *Container) {
+	if os.Getenv("SCHEDULER_ENABLED") == "false" {
+		log.Println("Scheduler disabled")
+		return
+	}
+
+	go app.Reminders.Start(ctx)
+	go app.Trash.Start(ctx)
+	log.Println("Scheduler started")
+}
@@ -1,7 +1,15 @@
 package main
 
-import "melodiapp/cmd/app/initializers"
+import (
+	"os"
+
+	"melodiapp/cmd/app/initializers"
+)
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		initializers.RunMigrate(os.Args[2:])
+		return
+	}
 	initializers.Run()
 }
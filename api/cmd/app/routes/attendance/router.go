@@ -0,0 +1,25 @@
+package attendance
+
+import (
+	"github.com/gin-gonic/gin"
+
+	attendanceapi "melodiapp/internal/adapters/api/attendance"
+	"melodiapp/models"
+)
+
+func AddAttendanceRoutes(r *gin.Engine, handlers *attendanceapi.AttendanceHandlers) {
+	services := r.Group("/services")
+	services.POST(":id/check-in", handlers.CheckIn(models.EventService))
+	services.GET(":id/attendance", handlers.ListByEvent(models.EventService))
+	services.POST(":id/attendance/close", handlers.Close(models.EventService))
+	services.PUT(":id/attendance/:userId", handlers.Mark(models.EventService))
+
+	rehearsals := r.Group("/rehearsals")
+	rehearsals.POST("/:id/check-in", handlers.CheckIn(models.EventRehearsal))
+	rehearsals.GET("/:id/attendance", handlers.ListByEvent(models.EventRehearsal))
+	rehearsals.POST("/:id/attendance/close", handlers.Close(models.EventRehearsal))
+	rehearsals.PUT("/:id/attendance/:userId", handlers.Mark(models.EventRehearsal))
+
+	r.GET("/attendance/reliability", handlers.Reliability)
+	r.GET("/users/:id/reliability", handlers.UserReliability)
+}
@@ -0,0 +1,12 @@
+package audit
+
+import (
+	"github.com/gin-gonic/gin"
+
+	auditapi "melodiapp/internal/adapters/api/audit"
+)
+
+func AddAuditRoutes(r *gin.Engine, handlers *auditapi.AuditHandlers) {
+	r.GET("/audit", handlers.List)
+	r.GET("/services/:id/activity", handlers.ServiceActivity)
+}
@@ -0,0 +1,16 @@
+package calendar
+
+import (
+	"github.com/gin-gonic/gin"
+
+	calendarapi "melodiapp/internal/adapters/api/calendar"
+)
+
+func AddCalendarRoutes(r *gin.Engine, handlers *calendarapi.CalendarHandlers) {
+	group := r.Group("/calendar")
+
+	group.GET("/me", handlers.Events)
+	group.POST("/me/token", handlers.IssueToken)
+	group.DELETE("/me/token", handlers.RevokeToken)
+	group.GET("/feed/:token", handlers.Feed)
+}
@@ -0,0 +1,15 @@
+package dresscode
+
+import (
+	"github.com/gin-gonic/gin"
+
+	dresscodeapi "melodiapp/internal/adapters/api/dresscode"
+)
+
+func AddDressCodeRoutes(r *gin.Engine, handlers *dresscodeapi.DressCodeHandlers) {
+	services := r.Group("/services")
+	services.GET(":id/dress-codes", handlers.ListByService)
+	services.PUT(":id/dress-codes", handlers.SetForService)
+
+	r.GET("/palettes/suggest", handlers.SuggestPalettes)
+}
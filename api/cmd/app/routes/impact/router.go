@@ -0,0 +1,17 @@
+package impact
+
+import (
+	"github.com/gin-gonic/gin"
+
+	impactapi "melodiapp/internal/adapters/api/impact"
+	"melodiapp/models"
+)
+
+// AddImpactRoutes registra la previsualización de borrado junto a cada recurso.
+func AddImpactRoutes(r *gin.Engine, handlers *impactapi.ImpactHandlers) {
+	r.GET("/songs/:id/delete-impact", handlers.Preview(models.ResourceSong))
+	r.GET("/users/:id/delete-impact", handlers.Preview(models.ResourceUser))
+	r.GET("/outfits/:id/delete-impact", handlers.Preview(models.ResourceOutfit))
+	r.GET("/positions/:id/delete-impact", handlers.Preview(models.ResourcePosition))
+	r.GET("/services/:id/delete-impact", handlers.Preview(models.ResourceService))
+}
@@ -0,0 +1,16 @@
+package job
+
+import (
+	"github.com/gin-gonic/gin"
+
+	jobapi "melodiapp/internal/adapters/api/job"
+)
+
+func AddJobRoutes(r *gin.Engine, handlers *jobapi.JobHandlers) {
+	group := r.Group("/jobs")
+
+	group.GET("", handlers.List)
+	group.GET("/stats", handlers.Stats)
+	group.GET("/:id", handlers.GetByID)
+	group.POST("/:id/retry", handlers.Retry)
+}
@@ -0,0 +1,18 @@
+package live
+
+import (
+	"github.com/gin-gonic/gin"
+
+	liveapi "melodiapp/internal/adapters/api/live"
+)
+
+func AddLiveRoutes(r *gin.Engine, handlers *liveapi.LiveHandlers) {
+	group := r.Group("/services")
+	group.GET(":id/live", handlers.State)
+	group.GET(":id/live/stream", handlers.Stream)
+	group.POST(":id/live/start", handlers.Start)
+	group.POST(":id/live/next", handlers.Advance)
+	group.POST(":id/live/previous", handlers.Rewind)
+	group.POST(":id/live/goto", handlers.GoTo)
+	group.POST(":id/live/end", handlers.End)
+}
@@ -0,0 +1,17 @@
+package notification
+
+import (
+	"github.com/gin-gonic/gin"
+
+	notificationapi "melodiapp/internal/adapters/api/notification"
+)
+
+func AddNotificationRoutes(r *gin.Engine, handlers *notificationapi.NotificationHandlers) {
+	group := r.Group("/notifications")
+
+	group.GET("", handlers.List)
+	group.POST("/read", handlers.MarkRead)
+	group.POST("/:id/read", handlers.MarkOneRead)
+	group.GET("/preferences", handlers.Preferences)
+	group.PUT("/preferences", handlers.UpdatePreferences)
+}
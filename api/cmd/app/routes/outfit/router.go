@@ -0,0 +1,18 @@
+package outfit
+
+import (
+	"github.com/gin-gonic/gin"
+
+	outfitapi "melodiapp/internal/adapters/api/outfit"
+)
+
+func AddOutfitRoutes(r *gin.Engine, handlers *outfitapi.OutfitHandlers) {
+	group := r.Group("/outfits")
+
+	group.GET("", handlers.GetAll)
+	group.GET(":id", handlers.GetByID)
+	group.POST("", handlers.Create)
+	group.PUT(":id", handlers.Update)
+	group.POST(":id/image", handlers.UploadImage)
+	group.DELETE(":id", handlers.Delete)
+}
@@ -0,0 +1,17 @@
+package position
+
+import (
+	"github.com/gin-gonic/gin"
+
+	positionapi "melodiapp/internal/adapters/api/position"
+)
+
+func AddPositionRoutes(r *gin.Engine, handlers *positionapi.PositionHandlers) {
+	group := r.Group("/positions")
+
+	group.GET("", handlers.GetAll)
+	group.GET(":id", handlers.GetByID)
+	group.POST("", handlers.Create)
+	group.PUT(":id", handlers.Update)
+	group.DELETE(":id", handlers.Delete)
+}
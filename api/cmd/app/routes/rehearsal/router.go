@@ -0,0 +1,20 @@
+package rehearsal
+
+import (
+	"github.com/gin-gonic/gin"
+
+	rehearsalapi "melodiapp/internal/adapters/api/rehearsal"
+)
+
+func AddRehearsalRoutes(r *gin.Engine, handlers *rehearsalapi.RehearsalHandlers) {
+	group := r.Group("/rehearsals")
+
+	group.GET("", handlers.List)
+	group.GET("/mine", handlers.Mine)
+	group.GET("/:id", handlers.GetByID)
+	group.POST("", handlers.Create)
+	group.PUT("/:id", handlers.Update)
+	group.DELETE("/:id", handlers.Delete)
+	group.POST("/:id/attendees/sync", handlers.SyncAttendees)
+	group.PUT("/:id/rsvp", handlers.RSVP)
+}
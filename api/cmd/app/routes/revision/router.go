@@ -0,0 +1,19 @@
+package revision
+
+import (
+	"github.com/gin-gonic/gin"
+
+	revisionapi "melodiapp/internal/adapters/api/revision"
+	"melodiapp/models"
+)
+
+// AddRevisionRoutes registra el historial de revisiones junto a canciones y servicios.
+func AddRevisionRoutes(r *gin.Engine, handlers *revisionapi.RevisionHandlers) {
+	r.GET("/songs/:id/revisions", handlers.List(models.RevisionSong))
+	r.GET("/songs/:id/revisions/diff", handlers.Diff(models.RevisionSong))
+	r.POST("/songs/:id/revisions/:version/revert", handlers.RevertSong)
+
+	r.GET("/services/:id/revisions", handlers.List(models.RevisionService))
+	r.GET("/services/:id/revisions/diff", handlers.Diff(models.RevisionService))
+	r.POST("/services/:id/revisions/:version/revert", handlers.RevertService)
+}
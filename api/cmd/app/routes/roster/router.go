@@ -0,0 +1,14 @@
+package roster
+
+import (
+	"github.com/gin-gonic/gin"
+
+	rosterapi "melodiapp/internal/adapters/api/roster"
+)
+
+func AddRosterRoutes(r *gin.Engine, handlers *rosterapi.RosterHandlers) {
+	group := r.Group("/rosters")
+
+	group.POST("/generate", handlers.Generate)
+	group.POST("/commit", handlers.Commit)
+}
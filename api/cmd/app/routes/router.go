@@ -6,29 +6,116 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	attendanceroutes "melodiapp/cmd/app/routes/attendance"
+	auditroutes "melodiapp/cmd/app/routes/audit"
 	authroutes "melodiapp/cmd/app/routes/auth"
+	calendarroutes "melodiapp/cmd/app/routes/calendar"
+	dresscoderoutes "melodiapp/cmd/app/routes/dresscode"
+	impactroutes "melodiapp/cmd/app/routes/impact"
+	jobroutes "melodiapp/cmd/app/routes/job"
+	liveroutes "melodiapp/cmd/app/routes/live"
+	notificationroutes "melodiapp/cmd/app/routes/notification"
+	outfitroutes "melodiapp/cmd/app/routes/outfit"
+	positionroutes "melodiapp/cmd/app/routes/position"
+	rehearsalroutes "melodiapp/cmd/app/routes/rehearsal"
+	revisionroutes "melodiapp/cmd/app/routes/revision"
+	rosterroutes "melodiapp/cmd/app/routes/roster"
+	runsheetroutes "melodiapp/cmd/app/routes/runsheet"
 	serviceroutes "melodiapp/cmd/app/routes/service"
 	songroutes "melodiapp/cmd/app/routes/song"
+	swaproutes "melodiapp/cmd/app/routes/swap"
+	trashroutes "melodiapp/cmd/app/routes/trash"
 	userroutes "melodiapp/cmd/app/routes/user"
-	"melodiapp/database"
+	attendanceapi "melodiapp/internal/adapters/api/attendance"
+	auditapi "melodiapp/internal/adapters/api/audit"
+	authapi "melodiapp/internal/adapters/api/auth"
+	calendarapi "melodiapp/internal/adapters/api/calendar"
+	dresscodeapi "melodiapp/internal/adapters/api/dresscode"
+	impactapi "melodiapp/internal/adapters/api/impact"
+	jobapi "melodiapp/internal/adapters/api/job"
+	liveapi "melodiapp/internal/adapters/api/live"
+	notificationapi "melodiapp/internal/adapters/api/notification"
+	outfitapi "melodiapp/internal/adapters/api/outfit"
+	positionapi "melodiapp/internal/adapters/api/position"
+	rehearsalapi "melodiapp/internal/adapters/api/rehearsal"
+	revisionapi "melodiapp/internal/adapters/api/revision"
+	rosterapi "melodiapp/internal/adapters/api/roster"
+	runsheetapi "melodiapp/internal/adapters/api/runsheet"
+	serviceapi "melodiapp/internal/adapters/api/service"
+	serviceoutfitapi "melodiapp/internal/adapters/api/serviceoutfit"
+	servicesongapi "melodiapp/internal/adapters/api/servicesong"
+	serviceuserapi "melodiapp/internal/adapters/api/serviceuser"
+	songapi "melodiapp/internal/adapters/api/song"
+	swapapi "melodiapp/internal/adapters/api/swap"
+	trashapi "melodiapp/internal/adapters/api/trash"
+	userapi "melodiapp/internal/adapters/api/user"
+	userblackoutapi "melodiapp/internal/adapters/api/userblackout"
 	"melodiapp/shared"
 )
 
-func NewRouter() *gin.Engine {
+// Dependencies son los handlers ya armados por la composición en initializers.
+type Dependencies struct {
+	LoadUser shared.UserLoader
+	Ping     func() error
+
+	User          *userapi.UserHandlers
+	UserBlackout  *userblackoutapi.UserBlackoutHandlers
+	Auth          *authapi.AuthHandlers
+	Service       *serviceapi.ServiceHandlers
+	ServiceUser   *serviceuserapi.ServiceUserHandlers
+	ServiceSong   *servicesongapi.ServiceSongHandlers
+	ServiceOutfit *serviceoutfitapi.ServiceOutfitHandlers
+	Song          *songapi.SongHandlers
+	Roster        *rosterapi.RosterHandlers
+	Position      *positionapi.PositionHandlers
+	Swap          *swapapi.SwapHandlers
+	Outfit        *outfitapi.OutfitHandlers
+	DressCode     *dresscodeapi.DressCodeHandlers
+	RunSheet      *runsheetapi.RunSheetHandlers
+	Live          *liveapi.LiveHandlers
+	Rehearsal     *rehearsalapi.RehearsalHandlers
+	Calendar      *calendarapi.CalendarHandlers
+	Attendance    *attendanceapi.AttendanceHandlers
+	Notification  *notificationapi.NotificationHandlers
+	Job           *jobapi.JobHandlers
+	Impact        *impactapi.ImpactHandlers
+	Trash         *trashapi.TrashHandlers
+	Audit         *auditapi.AuditHandlers
+	Revision      *revisionapi.RevisionHandlers
+}
+
+func NewRouter(deps Dependencies) *gin.Engine {
 	r := gin.Default()
 	r.Use(shared.Cors())
+	r.Use(shared.Errors())
+	r.Use(shared.WithUserLoader(deps.LoadUser))
 
 	r.Static("/files", "./public")
 
-	userroutes.AddUserRoutes(r)
-	authroutes.AddAuthRoutes(r)
-	serviceroutes.AddServiceRoutes(r)
-	songroutes.AddSongRoutes(r)
+	userroutes.AddUserRoutes(r, deps.User, deps.UserBlackout)
+	authroutes.AddAuthRoutes(r, deps.Auth)
+	serviceroutes.AddServiceRoutes(r, deps.Service, deps.ServiceUser, deps.Position, deps.ServiceSong, deps.ServiceOutfit)
+	songroutes.AddSongRoutes(r, deps.Song)
+	rosterroutes.AddRosterRoutes(r, deps.Roster)
+	positionroutes.AddPositionRoutes(r, deps.Position)
+	swaproutes.AddSwapRoutes(r, deps.Swap)
+	outfitroutes.AddOutfitRoutes(r, deps.Outfit)
+	dresscoderoutes.AddDressCodeRoutes(r, deps.DressCode)
+	runsheetroutes.AddRunSheetRoutes(r, deps.RunSheet)
+	liveroutes.AddLiveRoutes(r, deps.Live)
+	rehearsalroutes.AddRehearsalRoutes(r, deps.Rehearsal)
+	calendarroutes.AddCalendarRoutes(r, deps.Calendar)
+	attendanceroutes.AddAttendanceRoutes(r, deps.Attendance)
+	notificationroutes.AddNotificationRoutes(r, deps.Notification)
+	jobroutes.AddJobRoutes(r, deps.Job)
+	impactroutes.AddImpactRoutes(r, deps.Impact)
+	trashroutes.AddTrashRoutes(r, deps.Trash)
+	auditroutes.AddAuditRoutes(r, deps.Audit)
+	revisionroutes.AddRevisionRoutes(r, deps.Revision)
 
 	r.GET("/", func(c *gin.Context) {
-		tx := database.DBConn.Exec("SELECT 1")
-		if tx.Error != nil {
-			log.Printf("Error al ejecutar consulta: %v", tx.Error)
+		if err := deps.Ping(); err != nil {
+			log.Printf("Error al ejecutar consulta: %v", err)
 			c.JSON(http.StatusInternalServerError, gin.H{"Success": false})
 			return
 		}
@@ -0,0 +1,17 @@
+package runsheet
+
+import (
+	"github.com/gin-gonic/gin"
+
+	runsheetapi "melodiapp/internal/adapters/api/runsheet"
+)
+
+func AddRunSheetRoutes(r *gin.Engine, handlers *runsheetapi.RunSheetHandlers) {
+	group := r.Group("/services")
+	group.GET(":id/run-sheet", handlers.Get)
+	group.PUT(":id/run-sheet", handlers.Replace)
+	group.PUT(":id/run-sheet/order", handlers.Reorder)
+	group.POST(":id/run-sheet/items", handlers.AddItem)
+	group.PUT(":id/run-sheet/items/:itemId", handlers.UpdateItem)
+	group.DELETE(":id/run-sheet/items/:itemId", handlers.RemoveItem)
+}
@@ -3,54 +3,48 @@ package service
 import (
 	"github.com/gin-gonic/gin"
 
+	positionapi "melodiapp/internal/adapters/api/position"
 	serviceapi "melodiapp/internal/adapters/api/service"
 	serviceoutfitapi "melodiapp/internal/adapters/api/serviceoutfit"
 	servicesongapi "melodiapp/internal/adapters/api/servicesong"
 	serviceuserapi "melodiapp/internal/adapters/api/serviceuser"
-
-	dbadapter "melodiapp/internal/adapters/database/service"
-	dbserviceoutfit "melodiapp/internal/adapters/database/serviceoutfit"
-	dbservicesong "melodiapp/internal/adapters/database/servicesong"
-	dbserviceuser "melodiapp/internal/adapters/database/serviceuser"
-
-	coreservice "melodiapp/internal/core/service"
-	coreservicesong "melodiapp/internal/core/servicesong"
-	coreserviceuser "melodiapp/internal/core/serviceuser"
-
-	// 3. Import del Core
-	coreserviceoutfit "melodiapp/internal/core/serviceoutfit"
 )
 
-func AddServiceRoutes(r *gin.Engine) {
+func AddServiceRoutes(
+	r *gin.Engine,
+	serviceHandlers *serviceapi.ServiceHandlers,
+	serviceUserHandlers *serviceuserapi.ServiceUserHandlers,
+	positionHandlers *positionapi.PositionHandlers,
+	serviceSongHandlers *servicesongapi.ServiceSongHandlers,
+	serviceOutfitHandlers *serviceoutfitapi.ServiceOutfitHandlers,
+) {
 	group := r.Group("/services")
 
-	serviceRepo := dbadapter.NewGormServiceRepository()
-	serviceUsecase := coreservice.NewServiceUsecase(serviceRepo)
-	serviceHandlers := serviceapi.NewServiceHandlers(serviceUsecase)
-
-	serviceUserRepo := dbserviceuser.NewGormServiceUserRepository()
-	serviceUserUsecase := coreserviceuser.NewService(serviceUserRepo)
-	serviceUserHandlers := serviceuserapi.NewServiceUserHandlers(serviceUserUsecase)
-
-	serviceSongRepo := dbservicesong.NewGormServiceSongRepository()
-	serviceSongUsecase := coreservicesong.NewService(serviceSongRepo)
-	serviceSongHandlers := servicesongapi.NewServiceSongHandlers(serviceSongUsecase)
-
-	serviceOutfitRepo := dbserviceoutfit.NewGormServiceOutfitRepository()
-	serviceOutfitUsecase := coreserviceoutfit.NewService(serviceOutfitRepo)
-	serviceOutfitHandlers := serviceoutfitapi.NewServiceOutfitHandlers(serviceOutfitUsecase)
-
 	group.GET("", serviceHandlers.GetAll)
 	group.GET(":id", serviceHandlers.GetByID)
 	group.POST("", serviceHandlers.Create)
 	group.PUT(":id", serviceHandlers.Update)
+	group.PATCH(":id", serviceHandlers.Patch)
 	group.DELETE(":id", serviceHandlers.Delete)
 
 	group.POST(":id/users", serviceUserHandlers.AssignUsers)
+	group.PUT(":id/users", serviceUserHandlers.AssignUsers)
+	group.PATCH(":id/users", serviceUserHandlers.UpdateTeam)
+	group.POST(":id/users/:userId", serviceUserHandlers.AddUser)
+	group.DELETE(":id/users/:userId", serviceUserHandlers.RemoveUser)
+	group.POST(":id/users/conflicts", serviceUserHandlers.CheckConflicts)
 	group.GET(":id/users", serviceUserHandlers.ListByService)
+	group.GET(":id/users/history", serviceUserHandlers.StatusHistory)
 	group.PATCH(":id/users/:userId/status", serviceUserHandlers.ChangeStatus)
+	group.PUT(":id/users/:userId/position", serviceUserHandlers.ChangePosition)
+
+	group.GET(":id/positions", positionHandlers.ListServiceSlots)
+	group.PUT(":id/positions", positionHandlers.SetServiceSlots)
 
 	group.POST(":id/songs", serviceSongHandlers.AssignSongs)
+	group.PUT(":id/songs", serviceSongHandlers.AssignSongs)
+	group.PATCH(":id/songs", serviceSongHandlers.UpdateSongs)
+	group.POST(":id/songs/:songId", serviceSongHandlers.AddSong)
 	group.GET(":id/songs", serviceSongHandlers.ListByService)
 	group.DELETE(":id/songs/:songId", serviceSongHandlers.Remove)
 
@@ -0,0 +1,21 @@
+package swap
+
+import (
+	"github.com/gin-gonic/gin"
+
+	swapapi "melodiapp/internal/adapters/api/swap"
+)
+
+func AddSwapRoutes(r *gin.Engine, handlers *swapapi.SwapHandlers) {
+	services := r.Group("/services")
+	services.GET(":id/swaps", handlers.ListByService)
+	services.POST(":id/swaps", handlers.Request)
+
+	group := r.Group("/swaps")
+	group.GET("/available", handlers.ListAvailable)
+	group.GET("/:swapId", handlers.GetByID)
+	group.POST("/:swapId/claim", handlers.Claim)
+	group.POST("/:swapId/approve", handlers.Approve)
+	group.POST("/:swapId/reject", handlers.Reject)
+	group.POST("/:swapId/cancel", handlers.Cancel)
+}
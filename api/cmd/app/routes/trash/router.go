@@ -0,0 +1,15 @@
+package trash
+
+import (
+	"github.com/gin-gonic/gin"
+
+	trashapi "melodiapp/internal/adapters/api/trash"
+)
+
+func AddTrashRoutes(r *gin.Engine, handlers *trashapi.TrashHandlers) {
+	group := r.Group("/trash")
+
+	group.GET("", handlers.List)
+	group.POST("/:resource/:id/restore", handlers.Restore)
+	group.DELETE("/:resource/:id", handlers.Purge)
+}
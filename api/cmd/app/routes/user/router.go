@@ -1,24 +1,24 @@
-package users
+package user
 
 import (
 	"github.com/gin-gonic/gin"
 
 	userapi "melodiapp/internal/adapters/api/user"
-	dbadapter "melodiapp/internal/adapters/database/user"
-	coreuser "melodiapp/internal/core/user"
+	userblackoutapi "melodiapp/internal/adapters/api/userblackout"
 )
 
-func AddUserRoutes(r *gin.Engine) {
+func AddUserRoutes(r *gin.Engine, handlers *userapi.UserHandlers, blackoutHandlers *userblackoutapi.UserBlackoutHandlers) {
 	group := r.Group("/users")
 
-	repo := dbadapter.NewGormUserRepository()
-	service := coreuser.NewService(repo)
-	handlers := userapi.NewUserHandlers(service)
-
 	group.GET("", handlers.GetAllUsers)
 	group.GET("/me", handlers.GetMe)
 	group.POST("", handlers.CreateUser)
 	group.GET("/:id", handlers.GetUserById)
 	group.DELETE("/:id", handlers.DeleteUser)
 	group.PUT("/:id", handlers.EditUser)
+	group.PATCH("/:id", handlers.PatchUser)
+
+	group.GET("/:id/blackouts", blackoutHandlers.ListByUser)
+	group.POST("/:id/blackouts", blackoutHandlers.Create)
+	group.DELETE("/:id/blackouts/:blackoutId", blackoutHandlers.Delete)
 }
@@ -0,0 +1,25 @@
+DROP TABLE IF EXISTS "jobs";
+DROP TABLE IF EXISTS "reminder_logs";
+DROP TABLE IF EXISTS "notification_preferences";
+DROP TABLE IF EXISTS "notifications";
+DROP TABLE IF EXISTS "attendances";
+DROP TABLE IF EXISTS "rehearsal_attendees";
+DROP TABLE IF EXISTS "rehearsal_services";
+DROP TABLE IF EXISTS "rehearsals";
+DROP TABLE IF EXISTS "live_sessions";
+DROP TABLE IF EXISTS "run_sheet_items";
+DROP TABLE IF EXISTS "service_dress_codes";
+DROP TABLE IF EXISTS "service_outfits";
+DROP TABLE IF EXISTS "outfits";
+DROP TABLE IF EXISTS "swap_events";
+DROP TABLE IF EXISTS "swap_request_targets";
+DROP TABLE IF EXISTS "swap_requests";
+DROP TABLE IF EXISTS "service_positions";
+DROP TABLE IF EXISTS "positions";
+DROP TABLE IF EXISTS "user_blackouts";
+DROP TABLE IF EXISTS "service_user_status_changes";
+DROP TABLE IF EXISTS "service_users";
+DROP TABLE IF EXISTS "service_songs";
+DROP TABLE IF EXISTS "services";
+DROP TABLE IF EXISTS "songs";
+DROP TABLE IF EXISTS "users";
@@ -0,0 +1,306 @@
+-- Esquema base: todas las tablas que hasta ahora creaba AutoMigrate (más
+-- services y service_outfits, que nunca se creaban). Usa IF NOT EXISTS para
+-- poder aplicarse sobre bases que ya tenían las tablas.
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id" bigserial,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "username" text,
+    "email" text,
+    "password" text,
+    "celphone" text,
+    "role" text,
+    "lastname" text,
+    "profile_picture_url" text,
+    "secondary_role" text,
+    "max_services_per_month" bigint DEFAULT 0,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "songs" (
+    "id" bigserial,
+    "name" text,
+    "author" text,
+    "song_key" text,
+    "bpm" bigint,
+    "time_signature" text,
+    "duration" text,
+    "structure" text,
+    "has_sequence" text,
+    "has_chart" text,
+    "has_score" text,
+    "youtube_url" text,
+    "voice_url" text,
+    "guitar_url" text,
+    "piano_url" text,
+    "drums_url" text,
+    "bass_url" text,
+    "wind_url" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "services" (
+    "id" bigserial,
+    "start_time" text,
+    "end_time" text,
+    "name" text,
+    "created_by" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "service_songs" (
+    "service_id" bigint,
+    "song_id" bigint,
+    PRIMARY KEY ("service_id","song_id")
+);
+
+CREATE TABLE IF NOT EXISTS "service_users" (
+    "service_id" bigint,
+    "user_id" bigint,
+    "status" text,
+    "position_id" bigint DEFAULT 0,
+    "decline_reason" text,
+    "status_changed_at" timestamptz,
+    PRIMARY KEY ("service_id","user_id")
+);
+
+CREATE TABLE IF NOT EXISTS "service_user_status_changes" (
+    "id" bigserial,
+    "service_id" bigint,
+    "user_id" bigint,
+    "from_status" text,
+    "to_status" text,
+    "reason" text,
+    "changed_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_service_user_status_changes_service_id" ON "service_user_status_changes" ("service_id");
+
+CREATE TABLE IF NOT EXISTS "user_blackouts" (
+    "id" bigserial,
+    "user_id" bigint,
+    "start_date" timestamptz,
+    "end_date" timestamptz,
+    "reason" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_user_blackouts_user_id" ON "user_blackouts" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "positions" (
+    "id" bigserial,
+    "name" text,
+    "description" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_positions_name" ON "positions" ("name");
+
+CREATE TABLE IF NOT EXISTS "service_positions" (
+    "service_id" bigint,
+    "position_id" bigint,
+    "required" bigint,
+    PRIMARY KEY ("service_id","position_id")
+);
+
+CREATE TABLE IF NOT EXISTS "swap_requests" (
+    "id" bigserial,
+    "service_id" bigint,
+    "requester_id" bigint,
+    "position_id" bigint,
+    "scope" text,
+    "role" text,
+    "note" text,
+    "status" text,
+    "claimed_by" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_swap_requests_service_id" ON "swap_requests" ("service_id");
+
+CREATE TABLE IF NOT EXISTS "swap_request_targets" (
+    "swap_request_id" bigint,
+    "user_id" bigint,
+    PRIMARY KEY ("swap_request_id","user_id"),
+    CONSTRAINT "fk_swap_requests_targets" FOREIGN KEY ("swap_request_id") REFERENCES "swap_requests"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "swap_events" (
+    "id" bigserial,
+    "swap_request_id" bigint,
+    "action" text,
+    "actor_id" bigint,
+    "note" text,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_swap_requests_events" FOREIGN KEY ("swap_request_id") REFERENCES "swap_requests"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_swap_events_swap_request_id" ON "swap_events" ("swap_request_id");
+
+CREATE TABLE IF NOT EXISTS "outfits" (
+    "id" bigserial,
+    "name" text,
+    "colors" text,
+    "description" text,
+    "image_url" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "service_outfits" (
+    "service_id" bigint,
+    "outfit_id" bigint,
+    PRIMARY KEY ("service_id","outfit_id"),
+    CONSTRAINT "fk_service_outfits_outfit" FOREIGN KEY ("outfit_id") REFERENCES "outfits"("id") ON DELETE RESTRICT ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS "service_dress_codes" (
+    "id" bigserial,
+    "service_id" bigint,
+    "group_name" text,
+    "position_id" bigint,
+    "outfit_id" bigint,
+    "colors" text,
+    "notes" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_service_dress_codes_service_id" ON "service_dress_codes" ("service_id");
+
+CREATE TABLE IF NOT EXISTS "run_sheet_items" (
+    "id" bigserial,
+    "service_id" bigint,
+    "sort_order" bigint,
+    "type" text,
+    "title" text,
+    "duration_seconds" bigint,
+    "responsible_id" bigint,
+    "song_id" bigint,
+    "notes" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_run_sheet_items_service_id" ON "run_sheet_items" ("service_id");
+
+CREATE TABLE IF NOT EXISTS "live_sessions" (
+    "service_id" bigint,
+    "current_index" bigint,
+    "started_at" timestamptz,
+    "item_started_at" timestamptz,
+    "operator_id" bigint,
+    "ended_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("service_id")
+);
+
+CREATE TABLE IF NOT EXISTS "rehearsals" (
+    "id" bigserial,
+    "name" text,
+    "start_time" timestamptz,
+    "end_time" timestamptz,
+    "location" text,
+    "notes" text,
+    "created_by" bigint,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+
+CREATE TABLE IF NOT EXISTS "rehearsal_services" (
+    "rehearsal_id" bigint,
+    "service_id" bigint,
+    PRIMARY KEY ("rehearsal_id","service_id"),
+    CONSTRAINT "fk_rehearsals_services" FOREIGN KEY ("rehearsal_id") REFERENCES "rehearsals"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "rehearsal_attendees" (
+    "rehearsal_id" bigint,
+    "user_id" bigint,
+    "rsvp" text DEFAULT 'pending',
+    "rsvp_note" text,
+    "responded_at" timestamptz,
+    PRIMARY KEY ("rehearsal_id","user_id"),
+    CONSTRAINT "fk_rehearsals_attendees" FOREIGN KEY ("rehearsal_id") REFERENCES "rehearsals"("id")
+);
+
+CREATE TABLE IF NOT EXISTS "attendances" (
+    "id" bigserial,
+    "event_type" text,
+    "event_id" bigint,
+    "user_id" bigint,
+    "status" text,
+    "checked_in_at" timestamptz,
+    "late_minutes" bigint,
+    "source" text,
+    "marked_by" bigint,
+    "note" text,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_attendances_user_id" ON "attendances" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_attendance_event_user" ON "attendances" ("event_type","event_id","user_id");
+
+CREATE TABLE IF NOT EXISTS "notifications" (
+    "id" bigserial,
+    "user_id" bigint,
+    "event" text,
+    "title" text,
+    "body" text,
+    "service_id" bigint,
+    "link" text,
+    "read_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_notifications_user_id" ON "notifications" ("user_id");
+
+CREATE TABLE IF NOT EXISTS "notification_preferences" (
+    "user_id" bigint,
+    "event" text,
+    "in_app" boolean,
+    "email" boolean,
+    "webhook" boolean,
+    PRIMARY KEY ("user_id","event")
+);
+
+CREATE TABLE IF NOT EXISTS "reminder_logs" (
+    "id" bigserial,
+    "kind" text,
+    "event_type" text,
+    "event_id" bigint,
+    "user_id" bigint,
+    "sent_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_reminder_once" ON "reminder_logs" ("kind","event_type","event_id","user_id");
+
+CREATE TABLE IF NOT EXISTS "jobs" (
+    "id" bigserial,
+    "type" text,
+    "payload" jsonb,
+    "status" text DEFAULT 'queued',
+    "run_at" timestamptz,
+    "attempts" bigint,
+    "max_attempts" bigint,
+    "last_error" text,
+    "locked_by" text,
+    "locked_at" timestamptz,
+    "finished_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_jobs_pending" ON "jobs" ("status","run_at");
+CREATE INDEX IF NOT EXISTS "idx_jobs_type" ON "jobs" ("type");
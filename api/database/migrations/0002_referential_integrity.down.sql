@@ -0,0 +1,35 @@
+ALTER TABLE "rehearsal_attendees" DROP CONSTRAINT IF EXISTS "fk_rehearsals_attendees";
+ALTER TABLE "rehearsal_services" DROP CONSTRAINT IF EXISTS "fk_rehearsals_services";
+ALTER TABLE "swap_events" DROP CONSTRAINT IF EXISTS "fk_swap_requests_events";
+ALTER TABLE "swap_request_targets" DROP CONSTRAINT IF EXISTS "fk_swap_requests_targets";
+
+ALTER TABLE "service_positions" DROP CONSTRAINT IF EXISTS "fk_service_positions_position";
+
+ALTER TABLE "notification_preferences" DROP CONSTRAINT IF EXISTS "fk_notification_preferences_user";
+ALTER TABLE "notifications" DROP CONSTRAINT IF EXISTS "fk_notifications_user";
+ALTER TABLE "attendances" DROP CONSTRAINT IF EXISTS "fk_attendances_user";
+ALTER TABLE "rehearsal_attendees" DROP CONSTRAINT IF EXISTS "fk_rehearsal_attendees_user";
+ALTER TABLE "user_blackouts" DROP CONSTRAINT IF EXISTS "fk_user_blackouts_user";
+ALTER TABLE "swap_request_targets" DROP CONSTRAINT IF EXISTS "fk_swap_request_targets_user";
+ALTER TABLE "swap_requests" DROP CONSTRAINT IF EXISTS "fk_swap_requests_requester";
+ALTER TABLE "service_user_status_changes" DROP CONSTRAINT IF EXISTS "fk_service_user_status_changes_user";
+ALTER TABLE "service_users" DROP CONSTRAINT IF EXISTS "fk_service_users_user";
+
+ALTER TABLE "service_songs" DROP CONSTRAINT IF EXISTS "fk_service_songs_song";
+
+ALTER TABLE "rehearsal_services" DROP CONSTRAINT IF EXISTS "fk_rehearsal_services_service";
+ALTER TABLE "swap_requests" DROP CONSTRAINT IF EXISTS "fk_swap_requests_service";
+ALTER TABLE "live_sessions" DROP CONSTRAINT IF EXISTS "fk_live_sessions_service";
+ALTER TABLE "run_sheet_items" DROP CONSTRAINT IF EXISTS "fk_run_sheet_items_service";
+ALTER TABLE "service_dress_codes" DROP CONSTRAINT IF EXISTS "fk_service_dress_codes_service";
+ALTER TABLE "service_positions" DROP CONSTRAINT IF EXISTS "fk_service_positions_service";
+ALTER TABLE "service_outfits" DROP CONSTRAINT IF EXISTS "fk_service_outfits_service";
+ALTER TABLE "service_user_status_changes" DROP CONSTRAINT IF EXISTS "fk_service_user_status_changes_service";
+ALTER TABLE "service_users" DROP CONSTRAINT IF EXISTS "fk_service_users_service";
+ALTER TABLE "service_songs" DROP CONSTRAINT IF EXISTS "fk_service_songs_service";
+
+-- Se restauran las foreign keys originales, sin cascada.
+ALTER TABLE "swap_request_targets" ADD CONSTRAINT "fk_swap_requests_targets" FOREIGN KEY ("swap_request_id") REFERENCES "swap_requests"("id");
+ALTER TABLE "swap_events" ADD CONSTRAINT "fk_swap_requests_events" FOREIGN KEY ("swap_request_id") REFERENCES "swap_requests"("id");
+ALTER TABLE "rehearsal_services" ADD CONSTRAINT "fk_rehearsals_services" FOREIGN KEY ("rehearsal_id") REFERENCES "rehearsals"("id");
+ALTER TABLE "rehearsal_attendees" ADD CONSTRAINT "fk_rehearsals_attendees" FOREIGN KEY ("rehearsal_id") REFERENCES "rehearsals"("id");
@@ -0,0 +1,79 @@
+-- Foreign keys entre las tablas de unión y sus recursos. Las reglas están
+-- descritas también en models.References, que usa la previsualización de borrado.
+-- Antes de crearlas se limpian las filas que ya apuntaban a registros borrados.
+
+-- Filas huérfanas.
+DELETE FROM "service_songs" t WHERE NOT EXISTS (SELECT 1 FROM "services" p WHERE p."id" = t."service_id");
+DELETE FROM "service_songs" t WHERE NOT EXISTS (SELECT 1 FROM "songs" p WHERE p."id" = t."song_id");
+DELETE FROM "service_users" t WHERE NOT EXISTS (SELECT 1 FROM "services" p WHERE p."id" = t."service_id");
+DELETE FROM "service_users" t WHERE NOT EXISTS (SELECT 1 FROM "users" p WHERE p."id" = t."user_id");
+DELETE FROM "service_user_status_changes" t WHERE NOT EXISTS (SELECT 1 FROM "services" p WHERE p."id" = t."service_id");
+DELETE FROM "service_user_status_changes" t WHERE NOT EXISTS (SELECT 1 FROM "users" p WHERE p."id" = t."user_id");
+DELETE FROM "service_outfits" t WHERE NOT EXISTS (SELECT 1 FROM "services" p WHERE p."id" = t."service_id");
+DELETE FROM "service_outfits" t WHERE NOT EXISTS (SELECT 1 FROM "outfits" p WHERE p."id" = t."outfit_id");
+DELETE FROM "service_positions" t WHERE NOT EXISTS (SELECT 1 FROM "services" p WHERE p."id" = t."service_id");
+DELETE FROM "service_positions" t WHERE NOT EXISTS (SELECT 1 FROM "positions" p WHERE p."id" = t."position_id");
+DELETE FROM "service_dress_codes" t WHERE NOT EXISTS (SELECT 1 FROM "services" p WHERE p."id" = t."service_id");
+DELETE FROM "run_sheet_items" t WHERE NOT EXISTS (SELECT 1 FROM "services" p WHERE p."id" = t."service_id");
+DELETE FROM "live_sessions" t WHERE NOT EXISTS (SELECT 1 FROM "services" p WHERE p."id" = t."service_id");
+DELETE FROM "swap_requests" t WHERE NOT EXISTS (SELECT 1 FROM "services" p WHERE p."id" = t."service_id");
+DELETE FROM "swap_requests" t WHERE NOT EXISTS (SELECT 1 FROM "users" p WHERE p."id" = t."requester_id");
+DELETE FROM "swap_request_targets" t WHERE NOT EXISTS (SELECT 1 FROM "swap_requests" p WHERE p."id" = t."swap_request_id");
+DELETE FROM "swap_request_targets" t WHERE NOT EXISTS (SELECT 1 FROM "users" p WHERE p."id" = t."user_id");
+DELETE FROM "swap_events" t WHERE NOT EXISTS (SELECT 1 FROM "swap_requests" p WHERE p."id" = t."swap_request_id");
+DELETE FROM "rehearsal_services" t WHERE NOT EXISTS (SELECT 1 FROM "rehearsals" p WHERE p."id" = t."rehearsal_id");
+DELETE FROM "rehearsal_services" t WHERE NOT EXISTS (SELECT 1 FROM "services" p WHERE p."id" = t."service_id");
+DELETE FROM "rehearsal_attendees" t WHERE NOT EXISTS (SELECT 1 FROM "rehearsals" p WHERE p."id" = t."rehearsal_id");
+DELETE FROM "rehearsal_attendees" t WHERE NOT EXISTS (SELECT 1 FROM "users" p WHERE p."id" = t."user_id");
+DELETE FROM "user_blackouts" t WHERE NOT EXISTS (SELECT 1 FROM "users" p WHERE p."id" = t."user_id");
+DELETE FROM "attendances" t WHERE NOT EXISTS (SELECT 1 FROM "users" p WHERE p."id" = t."user_id");
+DELETE FROM "notifications" t WHERE NOT EXISTS (SELECT 1 FROM "users" p WHERE p."id" = t."user_id");
+DELETE FROM "notification_preferences" t WHERE NOT EXISTS (SELECT 1 FROM "users" p WHERE p."id" = t."user_id");
+
+-- Referencias opcionales (0 = sin asignar) que apuntaban a registros borrados.
+UPDATE "run_sheet_items" t SET "song_id" = 0 WHERE "song_id" <> 0 AND NOT EXISTS (SELECT 1 FROM "songs" p WHERE p."id" = t."song_id");
+UPDATE "run_sheet_items" t SET "responsible_id" = 0 WHERE "responsible_id" <> 0 AND NOT EXISTS (SELECT 1 FROM "users" p WHERE p."id" = t."responsible_id");
+UPDATE "swap_requests" t SET "claimed_by" = 0 WHERE "claimed_by" <> 0 AND NOT EXISTS (SELECT 1 FROM "users" p WHERE p."id" = t."claimed_by");
+UPDATE "service_users" t SET "position_id" = 0 WHERE "position_id" <> 0 AND NOT EXISTS (SELECT 1 FROM "positions" p WHERE p."id" = t."position_id");
+UPDATE "swap_requests" t SET "position_id" = 0 WHERE "position_id" <> 0 AND NOT EXISTS (SELECT 1 FROM "positions" p WHERE p."id" = t."position_id");
+
+-- Las que ya existían pasan a borrar en cascada.
+ALTER TABLE "swap_request_targets" DROP CONSTRAINT IF EXISTS "fk_swap_requests_targets";
+ALTER TABLE "swap_events" DROP CONSTRAINT IF EXISTS "fk_swap_requests_events";
+ALTER TABLE "rehearsal_services" DROP CONSTRAINT IF EXISTS "fk_rehearsals_services";
+ALTER TABLE "rehearsal_attendees" DROP CONSTRAINT IF EXISTS "fk_rehearsals_attendees";
+
+-- Servicio: borrarlo borra todo lo que cuelga de él.
+ALTER TABLE "service_songs" ADD CONSTRAINT "fk_service_songs_service" FOREIGN KEY ("service_id") REFERENCES "services"("id") ON DELETE CASCADE;
+ALTER TABLE "service_users" ADD CONSTRAINT "fk_service_users_service" FOREIGN KEY ("service_id") REFERENCES "services"("id") ON DELETE CASCADE;
+ALTER TABLE "service_user_status_changes" ADD CONSTRAINT "fk_service_user_status_changes_service" FOREIGN KEY ("service_id") REFERENCES "services"("id") ON DELETE CASCADE;
+ALTER TABLE "service_outfits" ADD CONSTRAINT "fk_service_outfits_service" FOREIGN KEY ("service_id") REFERENCES "services"("id") ON DELETE CASCADE;
+ALTER TABLE "service_positions" ADD CONSTRAINT "fk_service_positions_service" FOREIGN KEY ("service_id") REFERENCES "services"("id") ON DELETE CASCADE;
+ALTER TABLE "service_dress_codes" ADD CONSTRAINT "fk_service_dress_codes_service" FOREIGN KEY ("service_id") REFERENCES "services"("id") ON DELETE CASCADE;
+ALTER TABLE "run_sheet_items" ADD CONSTRAINT "fk_run_sheet_items_service" FOREIGN KEY ("service_id") REFERENCES "services"("id") ON DELETE CASCADE;
+ALTER TABLE "live_sessions" ADD CONSTRAINT "fk_live_sessions_service" FOREIGN KEY ("service_id") REFERENCES "services"("id") ON DELETE CASCADE;
+ALTER TABLE "swap_requests" ADD CONSTRAINT "fk_swap_requests_service" FOREIGN KEY ("service_id") REFERENCES "services"("id") ON DELETE CASCADE;
+ALTER TABLE "rehearsal_services" ADD CONSTRAINT "fk_rehearsal_services_service" FOREIGN KEY ("service_id") REFERENCES "services"("id") ON DELETE CASCADE;
+
+-- Canción: sale de los repertorios.
+ALTER TABLE "service_songs" ADD CONSTRAINT "fk_service_songs_song" FOREIGN KEY ("song_id") REFERENCES "songs"("id") ON DELETE CASCADE;
+
+-- Usuario: se borran sus asignaciones y datos personales.
+ALTER TABLE "service_users" ADD CONSTRAINT "fk_service_users_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE "service_user_status_changes" ADD CONSTRAINT "fk_service_user_status_changes_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE "swap_requests" ADD CONSTRAINT "fk_swap_requests_requester" FOREIGN KEY ("requester_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE "swap_request_targets" ADD CONSTRAINT "fk_swap_request_targets_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE "user_blackouts" ADD CONSTRAINT "fk_user_blackouts_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE "rehearsal_attendees" ADD CONSTRAINT "fk_rehearsal_attendees_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE "attendances" ADD CONSTRAINT "fk_attendances_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE "notifications" ADD CONSTRAINT "fk_notifications_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+ALTER TABLE "notification_preferences" ADD CONSTRAINT "fk_notification_preferences_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE;
+
+-- Puesto: no se puede borrar mientras un servicio tenga cupos para él.
+ALTER TABLE "service_positions" ADD CONSTRAINT "fk_service_positions_position" FOREIGN KEY ("position_id") REFERENCES "positions"("id") ON DELETE RESTRICT;
+
+-- Hijos de intercambios y ensayos.
+ALTER TABLE "swap_request_targets" ADD CONSTRAINT "fk_swap_requests_targets" FOREIGN KEY ("swap_request_id") REFERENCES "swap_requests"("id") ON DELETE CASCADE;
+ALTER TABLE "swap_events" ADD CONSTRAINT "fk_swap_requests_events" FOREIGN KEY ("swap_request_id") REFERENCES "swap_requests"("id") ON DELETE CASCADE;
+ALTER TABLE "rehearsal_services" ADD CONSTRAINT "fk_rehearsals_services" FOREIGN KEY ("rehearsal_id") REFERENCES "rehearsals"("id") ON DELETE CASCADE;
+ALTER TABLE "rehearsal_attendees" ADD CONSTRAINT "fk_rehearsals_attendees" FOREIGN KEY ("rehearsal_id") REFERENCES "rehearsals"("id") ON DELETE CASCADE;
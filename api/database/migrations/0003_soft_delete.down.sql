@@ -0,0 +1,8 @@
+-- Al bajar, lo que estaba en la papelera vuelve a quedar visible.
+DROP INDEX IF EXISTS "idx_users_deleted_at";
+DROP INDEX IF EXISTS "idx_services_deleted_at";
+DROP INDEX IF EXISTS "idx_songs_deleted_at";
+
+ALTER TABLE "users" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "services" DROP COLUMN IF EXISTS "deleted_at";
+ALTER TABLE "songs" DROP COLUMN IF EXISTS "deleted_at";
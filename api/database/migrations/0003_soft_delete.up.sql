@@ -0,0 +1,9 @@
+-- Papelera: canciones, servicios y usuarios borrados quedan con deleted_at
+-- hasta que se restauran o se purgan.
+ALTER TABLE "songs" ADD COLUMN IF NOT EXISTS "deleted_at" timestamptz;
+ALTER TABLE "services" ADD COLUMN IF NOT EXISTS "deleted_at" timestamptz;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "deleted_at" timestamptz;
+
+CREATE INDEX IF NOT EXISTS "idx_songs_deleted_at" ON "songs" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_services_deleted_at" ON "services" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
@@ -0,0 +1,19 @@
+-- Auditoría: quién cambió qué y cuándo, con el antes y el después.
+CREATE TABLE IF NOT EXISTS "audit_logs" (
+	"id" bigserial PRIMARY KEY,
+	"actor_id" bigint NOT NULL DEFAULT 0,
+	"action" text NOT NULL,
+	"entity_type" text NOT NULL,
+	"entity_id" bigint NOT NULL DEFAULT 0,
+	"service_id" bigint NOT NULL DEFAULT 0,
+	"before" jsonb,
+	"after" jsonb,
+	"changes" jsonb,
+	"ip" text NOT NULL DEFAULT '',
+	"created_at" timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_actor_id" ON "audit_logs" ("actor_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_entity" ON "audit_logs" ("entity_type", "entity_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_service_id" ON "audit_logs" ("service_id");
+CREATE INDEX IF NOT EXISTS "idx_audit_logs_created_at" ON "audit_logs" ("created_at");
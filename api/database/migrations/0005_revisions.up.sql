@@ -0,0 +1,63 @@
+-- Revisiones: fotos versionadas de canciones y de la composición de cada servicio.
+CREATE TABLE IF NOT EXISTS "revisions" (
+    "id" bigserial PRIMARY KEY,
+    "entity_type" text NOT NULL,
+    "entity_id" bigint NOT NULL,
+    "version" bigint NOT NULL,
+    "snapshot" jsonb NOT NULL,
+    "actor_id" bigint NOT NULL DEFAULT 0,
+    "created_at" timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_revision_version" ON "revisions" ("entity_type", "entity_id", "version");
+
+-- Revisión 1 de lo que ya existe, para poder volver al estado previo al primer cambio.
+INSERT INTO "revisions" ("entity_type", "entity_id", "version", "snapshot")
+SELECT 'song', s."id", 1, to_jsonb(s) - 'id' - 'created_at' - 'updated_at' - 'deleted_at'
+FROM "songs" s
+WHERE s."deleted_at" IS NULL
+ON CONFLICT DO NOTHING;
+
+INSERT INTO "revisions" ("entity_type", "entity_id", "version", "snapshot")
+SELECT 'service', sv."id", 1, jsonb_build_object(
+    'name', sv."name",
+    'start_time', sv."start_time",
+    'end_time', sv."end_time",
+    'songs', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object(
+            'song_id', so."id",
+            'name', so."name",
+            'order', COALESCE(ord."position", 0),
+            'key', so."song_key"
+        ) ORDER BY ord."position" IS NULL, ord."position", so."id")
+        FROM "service_songs" ss
+        JOIN "songs" so ON so."id" = ss."song_id" AND so."deleted_at" IS NULL
+        LEFT JOIN (
+            SELECT first."song_id", row_number() OVER (ORDER BY first."sort_order", first."id") AS "position"
+            FROM (
+                SELECT DISTINCT ON (ri."song_id") ri."song_id", ri."sort_order", ri."id"
+                FROM "run_sheet_items" ri
+                WHERE ri."service_id" = sv."id" AND ri."type" = 'song' AND ri."song_id" <> 0
+                ORDER BY ri."song_id", ri."sort_order", ri."id"
+            ) first
+        ) ord ON ord."song_id" = ss."song_id"
+        WHERE ss."service_id" = sv."id"
+    ), '[]'::jsonb),
+    'team', COALESCE((
+        SELECT jsonb_agg(jsonb_build_object(
+            'user_id', su."user_id",
+            'position_id', su."position_id",
+            'status', su."status"
+        ) ORDER BY su."user_id")
+        FROM "service_users" su
+        WHERE su."service_id" = sv."id"
+    ), '[]'::jsonb),
+    'outfit_ids', COALESCE((
+        SELECT jsonb_agg(so."outfit_id" ORDER BY so."outfit_id")
+        FROM "service_outfits" so
+        WHERE so."service_id" = sv."id"
+    ), '[]'::jsonb)
+)
+FROM "services" sv
+WHERE sv."deleted_at" IS NULL
+ON CONFLICT DO NOTHING;
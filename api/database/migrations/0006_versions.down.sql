@@ -0,0 +1,2 @@
+ALTER TABLE "services" DROP COLUMN IF EXISTS "version";
+ALTER TABLE "songs" DROP COLUMN IF EXISTS "version";
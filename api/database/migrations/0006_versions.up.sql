@@ -0,0 +1,3 @@
+-- Versión para el control de concurrencia optimista (ETag / If-Match).
+ALTER TABLE "songs" ADD COLUMN IF NOT EXISTS "version" bigint NOT NULL DEFAULT 1;
+ALTER TABLE "services" ADD COLUMN IF NOT EXISTS "version" bigint NOT NULL DEFAULT 1;
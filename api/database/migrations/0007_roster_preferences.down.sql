@@ -0,0 +1,2 @@
+ALTER TABLE "users" DROP COLUMN IF EXISTS "preferred_services_per_month";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "preferred_weekdays";
@@ -0,0 +1,3 @@
+-- Preferencias que usa el generador de turnos.
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "preferred_weekdays" text DEFAULT '[]';
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "preferred_services_per_month" bigint DEFAULT 0;
@@ -0,0 +1,9 @@
+-- Tokens de los feeds .ics: uno por usuario, se guarda solo el hash.
+CREATE TABLE IF NOT EXISTS "calendar_tokens" (
+    "user_id" bigint PRIMARY KEY,
+    "token_hash" text NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT "fk_calendar_tokens_user" FOREIGN KEY ("user_id") REFERENCES "users"("id") ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_calendar_tokens_token_hash" ON "calendar_tokens" ("token_hash");
@@ -0,0 +1,2 @@
+-- No se puede saber qué filas tenían 'rejected' o un estado vacío: no se revierte.
+SELECT 1;
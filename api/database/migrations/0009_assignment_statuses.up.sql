@@ -0,0 +1,6 @@
+-- Estados que guardaba la versión anterior: la app declinaba con 'rejected' y
+-- dejaba el estado vacío para los pendientes.
+UPDATE "service_users" SET "status" = 'declined' WHERE "status" = 'rejected';
+UPDATE "service_users" SET "status" = 'pending' WHERE "status" IS NULL OR "status" = '';
+UPDATE "service_user_status_changes" SET "from_status" = 'declined' WHERE "from_status" = 'rejected';
+UPDATE "service_user_status_changes" SET "to_status" = 'declined' WHERE "to_status" = 'rejected';
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS "trg_service_positions_version" ON "service_positions";
+DROP TRIGGER IF EXISTS "trg_service_outfits_version" ON "service_outfits";
+DROP TRIGGER IF EXISTS "trg_service_songs_version" ON "service_songs";
+DROP TRIGGER IF EXISTS "trg_service_users_version" ON "service_users";
+DROP FUNCTION IF EXISTS "bump_service_version"();
@@ -0,0 +1,29 @@
+-- El ETag de un servicio es services.version, pero GET /services/:id también
+-- devuelve equipo, repertorio, outfits y puestos. Cualquier cambio en esas
+-- tablas sube la versión del servicio en la misma transacción.
+CREATE OR REPLACE FUNCTION "bump_service_version"() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        UPDATE "services" SET "version" = "version" + 1 WHERE "id" = OLD."service_id";
+        RETURN OLD;
+    END IF;
+    UPDATE "services" SET "version" = "version" + 1 WHERE "id" = NEW."service_id";
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS "trg_service_users_version" ON "service_users";
+CREATE TRIGGER "trg_service_users_version" AFTER INSERT OR UPDATE OR DELETE ON "service_users"
+    FOR EACH ROW EXECUTE FUNCTION "bump_service_version"();
+
+DROP TRIGGER IF EXISTS "trg_service_songs_version" ON "service_songs";
+CREATE TRIGGER "trg_service_songs_version" AFTER INSERT OR UPDATE OR DELETE ON "service_songs"
+    FOR EACH ROW EXECUTE FUNCTION "bump_service_version"();
+
+DROP TRIGGER IF EXISTS "trg_service_outfits_version" ON "service_outfits";
+CREATE TRIGGER "trg_service_outfits_version" AFTER INSERT OR UPDATE OR DELETE ON "service_outfits"
+    FOR EACH ROW EXECUTE FUNCTION "bump_service_version"();
+
+DROP TRIGGER IF EXISTS "trg_service_positions_version" ON "service_positions";
+CREATE TRIGGER "trg_service_positions_version" AFTER INSERT OR UPDATE OR DELETE ON "service_positions"
+    FOR EACH ROW EXECUTE FUNCTION "bump_service_version"();
@@ -0,0 +1 @@
+ALTER TABLE "live_sessions" DROP COLUMN IF EXISTS "version";
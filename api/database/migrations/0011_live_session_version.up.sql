@@ -0,0 +1,3 @@
+-- Versión de la sesión en vivo: los movimientos de operadores en distintas
+-- instancias solo se guardan si nadie cambió la sesión desde que la leyeron.
+ALTER TABLE "live_sessions" ADD COLUMN IF NOT EXISTS "version" bigint NOT NULL DEFAULT 1;
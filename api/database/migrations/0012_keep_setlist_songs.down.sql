@@ -0,0 +1,2 @@
+ALTER TABLE "service_songs" DROP CONSTRAINT IF EXISTS "fk_service_songs_song";
+ALTER TABLE "service_songs" ADD CONSTRAINT "fk_service_songs_song" FOREIGN KEY ("song_id") REFERENCES "songs"("id") ON DELETE CASCADE;
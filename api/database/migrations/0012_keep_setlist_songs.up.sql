@@ -0,0 +1,4 @@
+-- Purgar una canción ya no la saca de los repertorios (incluidos los de
+-- servicios pasados): mientras esté en alguno no se puede borrar.
+ALTER TABLE "service_songs" DROP CONSTRAINT IF EXISTS "fk_service_songs_song";
+ALTER TABLE "service_songs" ADD CONSTRAINT "fk_service_songs_song" FOREIGN KEY ("song_id") REFERENCES "songs"("id") ON DELETE RESTRICT;
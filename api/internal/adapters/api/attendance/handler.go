@@ -0,0 +1,184 @@
+package attendanceapi
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	attendanceports "melodiapp/internal/ports/attendance"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+type AttendanceHandlers struct {
+	service attendanceports.AttendanceService
+}
+
+type markRequest struct {
+	Status string `json:"status"`
+	Note   string `json:"note"`
+}
+
+func NewAttendanceHandlers(s attendanceports.AttendanceService) *AttendanceHandlers {
+	return &AttendanceHandlers{service: s}
+}
+
+func parseID(c *gin.Context, param string, resource string) (uint, bool) {
+	id64, err := strconv.ParseUint(c.Param(param), 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID(resource))
+		return 0, false
+	}
+	return uint(id64), true
+}
+
+// CheckIn registra la llegada del usuario actual a un servicio o ensayo.
+func (h *AttendanceHandlers) CheckIn(eventType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := shared.CurrentUser(c)
+		if err != nil {
+			shared.Fail(c, err)
+			return
+		}
+
+		eventID, ok := parseID(c, "id", eventType)
+		if !ok {
+			return
+		}
+
+		record, err := h.service.CheckIn(eventType, eventID, user)
+		if err != nil {
+			shared.Fail(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, record)
+	}
+}
+
+func (h *AttendanceHandlers) ListByEvent(eventType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, err := shared.CurrentUser(c); err != nil {
+			shared.Fail(c, err)
+			return
+		}
+
+		eventID, ok := parseID(c, "id", eventType)
+		if !ok {
+			return
+		}
+
+		records, err := h.service.ListByEvent(eventType, eventID)
+		if err != nil {
+			shared.Fail(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, records)
+	}
+}
+
+// Mark permite a un admin marcar present, late, no_show o excused.
+func (h *AttendanceHandlers) Mark(eventType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, ok := shared.RequireAdmin(c)
+		if !ok {
+			return
+		}
+
+		eventID, ok := parseID(c, "id", eventType)
+		if !ok {
+			return
+		}
+		userID, ok := parseID(c, "userId", "user")
+		if !ok {
+			return
+		}
+
+		var input markRequest
+		if err := c.ShouldBindJSON(&input); err != nil {
+			shared.Fail(c, shared.ErrInvalidData)
+			return
+		}
+
+		record, err := h.service.Mark(eventType, eventID, userID, input.Status, input.Note, admin)
+		if err != nil {
+			shared.Fail(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, record)
+	}
+}
+
+// Close marca como no_show a quienes no registraron asistencia.
+func (h *AttendanceHandlers) Close(eventType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		admin, ok := shared.RequireAdmin(c)
+		if !ok {
+			return
+		}
+
+		eventID, ok := parseID(c, "id", eventType)
+		if !ok {
+			return
+		}
+
+		records, err := h.service.Close(eventType, eventID, admin)
+		if err != nil {
+			shared.Fail(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, records)
+	}
+}
+
+// Reliability devuelve las estadísticas de todos o de ?user_ids=1,2,3 (solo admins).
+func (h *AttendanceHandlers) Reliability(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	var userIDs []uint
+	if raw := c.Query("user_ids"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			id64, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+			if err != nil {
+				shared.Fail(c, shared.InvalidID("user"))
+				return
+			}
+			userIDs = append(userIDs, uint(id64))
+		}
+	}
+
+	stats, err := h.service.Reliability(userIDs)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// UserReliability devuelve las estadísticas de un usuario; él mismo o un admin.
+func (h *AttendanceHandlers) UserReliability(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	userID, ok := parseID(c, "id", "user")
+	if !ok {
+		return
+	}
+	if user.ID != userID && user.Role != "admin" {
+		shared.Fail(c, models.ErrForbidden)
+		return
+	}
+
+	stats, err := h.service.Reliability([]uint{userID})
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stats[0])
+}
@@ -0,0 +1,132 @@
+package auditapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	auditports "melodiapp/internal/ports/audit"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+var errInvalidFilter = models.Invalid("invalid_filter", "Invalid filter")
+
+type AuditHandlers struct {
+	service auditports.AuditService
+}
+
+func NewAuditHandlers(s auditports.AuditService) *AuditHandlers {
+	return &AuditHandlers{service: s}
+}
+
+func queryUint(c *gin.Context, name string) (uint, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, errInvalidFilter
+	}
+	return uint(n), nil
+}
+
+func queryTime(c *gin.Context, name string) (*time.Time, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, errInvalidFilter
+	}
+	return &t, nil
+}
+
+// parsePage lee ?page= y ?page_size=; el caso de uso completa los valores por defecto.
+func parsePage(c *gin.Context, filter *models.AuditFilter) error {
+	page, err := queryUint(c, "page")
+	if err != nil {
+		return err
+	}
+	size, err := queryUint(c, "page_size")
+	if err != nil {
+		return err
+	}
+	filter.Page = int(page)
+	filter.PageSize = int(size)
+	return nil
+}
+
+// parseFilter lee los filtros de la auditoría completa.
+func parseFilter(c *gin.Context) (models.AuditFilter, error) {
+	filter := models.AuditFilter{Action: c.Query("action"), EntityType: c.Query("entity_type")}
+	var err error
+	if filter.ActorID, err = queryUint(c, "actor_id"); err != nil {
+		return filter, err
+	}
+	if filter.EntityID, err = queryUint(c, "entity_id"); err != nil {
+		return filter, err
+	}
+	if filter.ServiceID, err = queryUint(c, "service_id"); err != nil {
+		return filter, err
+	}
+	if filter.From, err = queryTime(c, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = queryTime(c, "to"); err != nil {
+		return filter, err
+	}
+	return filter, parsePage(c, &filter)
+}
+
+// List es la auditoría completa para administradores. Filtra por actor_id,
+// action, entity_type, entity_id, service_id y rango from/to (RFC3339).
+func (h *AuditHandlers) List(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	filter, err := parseFilter(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	page, err := h.service.List(filter)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// ServiceActivity es el historial de cambios de un servicio, visible para
+// cualquier usuario autenticado.
+func (h *AuditHandlers) ServiceActivity(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	serviceID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("service"))
+		return
+	}
+	filter := models.AuditFilter{ServiceID: uint(serviceID)}
+	if err := parsePage(c, &filter); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	page, err := h.service.List(filter)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, page)
+}
@@ -21,22 +21,13 @@ func NewAuthHandlers(service authports.AuthService) *AuthHandlers {
 func (h *AuthHandlers) Register(c *gin.Context) {
 	var input models.UserInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, err.Error())
+		shared.Fail(c, shared.ErrInvalidData)
 		return
 	}
 
 	token, err := h.service.Register(input)
 	if err != nil {
-		switch err.Error() {
-		case "Incomplete fields":
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		case "Invalid email format":
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		case "Email already exists":
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		shared.Fail(c, err)
 		return
 	}
 
@@ -46,16 +37,12 @@ func (h *AuthHandlers) Register(c *gin.Context) {
 func (h *AuthHandlers) Logout(c *gin.Context) {
 	tokenStr := shared.GetTokenFromRequest(c)
 	if tokenStr == "" {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		shared.Fail(c, models.ErrInvalidToken)
 		return
 	}
 
 	if err := h.service.Logout(tokenStr); err != nil {
-		if err.Error() == "You don't have permission" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			return
-		}
-		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 
@@ -65,22 +52,13 @@ func (h *AuthHandlers) Logout(c *gin.Context) {
 func (h *AuthHandlers) Login(c *gin.Context) {
 	var input models.UserInput
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		shared.Fail(c, shared.ErrInvalidData)
 		return
 	}
 
 	token, err := h.service.Login(input)
 	if err != nil {
-		switch err.Error() {
-		case "Incomplete fields":
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		case "Invalid email format":
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		case "Invalid credentials":
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		}
+		shared.Fail(c, err)
 		return
 	}
 
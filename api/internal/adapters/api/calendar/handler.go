@@ -0,0 +1,125 @@
+package calendarapi
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	calendarports "melodiapp/internal/ports/calendar"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+type CalendarHandlers struct {
+	service calendarports.CalendarService
+}
+
+func NewCalendarHandlers(s calendarports.CalendarService) *CalendarHandlers {
+	return &CalendarHandlers{service: s}
+}
+
+func (h *CalendarHandlers) Events(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	events, err := h.service.EventsForUser(user.ID)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+// IssueToken genera (o rota) el token del feed .ics del usuario actual. El
+// token solo se muestra en esta respuesta.
+func (h *CalendarHandlers) IssueToken(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	feed, err := h.service.IssueToken(user.ID)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, feed)
+}
+
+func (h *CalendarHandlers) RevokeToken(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	if err := h.service.RevokeToken(user.ID); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// Feed devuelve los mismos eventos en formato iCalendar para suscribirse desde
+// otras apps. Se autentica con el token del feed, no con la sesión.
+func (h *CalendarHandlers) Feed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+	if token == "" {
+		shared.Fail(c, models.ErrInvalidToken)
+		return
+	}
+
+	events, err := h.service.EventsForToken(token)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(renderICS(events)))
+}
+
+const icsTime = "20060102T150405Z"
+
+func renderICS(events []models.CalendarEvent) string {
+	var b strings.Builder
+	line := func(s string) { b.WriteString(s + "\r\n") }
+
+	now := time.Now().UTC().Format(icsTime)
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//melodiapp//calendar//ES")
+	line("CALSCALE:GREGORIAN")
+	for _, e := range events {
+		line("BEGIN:VEVENT")
+		line("UID:" + e.UID)
+		line("DTSTAMP:" + now)
+		line("DTSTART:" + e.Start.UTC().Format(icsTime))
+		line("DTEND:" + e.End.UTC().Format(icsTime))
+		line("SUMMARY:" + escapeICS(e.Title))
+		if e.Location != "" {
+			line("LOCATION:" + escapeICS(e.Location))
+		}
+		if e.Description != "" {
+			line("DESCRIPTION:" + escapeICS(e.Description))
+		}
+		line("CATEGORIES:" + strings.ToUpper(e.Kind))
+		if e.Status == models.StatusPending {
+			line("STATUS:TENTATIVE")
+		} else {
+			line("STATUS:CONFIRMED")
+		}
+		line("END:VEVENT")
+	}
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+func escapeICS(value string) string {
+	r := strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\r\n", "\\n", "\n", "\\n")
+	return r.Replace(value)
+}
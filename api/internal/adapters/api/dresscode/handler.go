@@ -0,0 +1,118 @@
+package dresscodeapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	dresscodeports "melodiapp/internal/ports/dresscode"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+type DressCodeHandlers struct {
+	service dresscodeports.DressCodeService
+}
+
+type setDressCodesRequest struct {
+	DressCodes []models.ServiceDressCode `json:"dress_codes"`
+}
+
+func NewDressCodeHandlers(s dresscodeports.DressCodeService) *DressCodeHandlers {
+	return &DressCodeHandlers{service: s}
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("service"))
+		return 0, false
+	}
+	return uint(id64), true
+}
+
+func (h *DressCodeHandlers) ListByService(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	serviceID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	codes, err := h.service.ListByService(serviceID)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, codes)
+}
+
+// SetForService reemplaza todos los códigos de vestimenta del servicio.
+func (h *DressCodeHandlers) SetForService(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	serviceID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input setDressCodesRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	codes, err := h.service.SetForService(serviceID, input.DressCodes)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, codes)
+}
+
+// SuggestPalettes recibe base, scheme, service_id y recent por query.
+func (h *DressCodeHandlers) SuggestPalettes(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	base := c.Query("base")
+	if base == "" {
+		shared.Fail(c, models.ErrIncompleteFields)
+		return
+	}
+
+	var serviceID uint
+	if raw := c.Query("service_id"); raw != "" {
+		id64, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			shared.Fail(c, shared.InvalidID("service"))
+			return
+		}
+		serviceID = uint(id64)
+	}
+
+	recent := 0
+	if raw := c.Query("recent"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			shared.Fail(c, shared.ErrInvalidData)
+			return
+		}
+		recent = n
+	}
+
+	suggestions, err := h.service.SuggestPalettes(base, c.Query("scheme"), serviceID, recent)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, suggestions)
+}
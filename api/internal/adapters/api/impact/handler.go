@@ -0,0 +1,41 @@
+package impactapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	impactports "melodiapp/internal/ports/impact"
+	"melodiapp/shared"
+)
+
+type ImpactHandlers struct {
+	service impactports.ImpactService
+}
+
+func NewImpactHandlers(s impactports.ImpactService) *ImpactHandlers {
+	return &ImpactHandlers{service: s}
+}
+
+// Preview arma el handler de GET /<recurso>/:id/delete-impact.
+func (h *ImpactHandlers) Preview(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := shared.RequireAdmin(c); !ok {
+			return
+		}
+
+		id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+		if err != nil {
+			shared.Fail(c, shared.InvalidID(resource))
+			return
+		}
+
+		impact, err := h.service.Preview(resource, uint(id64))
+		if err != nil {
+			shared.Fail(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, impact)
+	}
+}
@@ -0,0 +1,105 @@
+package jobapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	jobports "melodiapp/internal/ports/job"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+type JobHandlers struct {
+	service jobports.JobService
+}
+
+func NewJobHandlers(s jobports.JobService) *JobHandlers {
+	return &JobHandlers{service: s}
+}
+
+func parseID(c *gin.Context, param string, resource string) (uint, bool) {
+	id64, err := strconv.ParseUint(c.Param(param), 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID(resource))
+		return 0, false
+	}
+	return uint(id64), true
+}
+
+// List devuelve los trabajos más recientes; filtra por ?status= y ?type=.
+func (h *JobHandlers) List(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	filter := models.JobFilter{Status: c.Query("status"), Type: c.Query("type")}
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			shared.Fail(c, shared.ErrInvalidData)
+			return
+		}
+		filter.Limit = n
+	}
+
+	jobs, err := h.service.List(filter)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, jobs)
+}
+
+func (h *JobHandlers) Stats(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	stats, err := h.service.Stats()
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+func (h *JobHandlers) GetByID(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+	id, ok := parseID(c, "id", "job")
+	if !ok {
+		return
+	}
+
+	job, err := h.service.GetByID(id)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	if job == nil {
+		shared.Fail(c, models.ErrJobNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+// Retry vuelve a encolar un trabajo (típicamente uno dead) para que corra ya.
+func (h *JobHandlers) Retry(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+	id, ok := parseID(c, "id", "job")
+	if !ok {
+		return
+	}
+
+	job, err := h.service.Retry(id)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
@@ -0,0 +1,154 @@
+package liveapi
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	liveports "melodiapp/internal/ports/live"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+// pingInterval es cada cuánto se manda la hora del servidor para mantener viva
+// la conexión. No consulta la base: los clientes calculan los temporizadores
+// con item_started_at y la diferencia con server_time.
+const pingInterval = 15 * time.Second
+
+type LiveHandlers struct {
+	service liveports.LiveService
+}
+
+type goToRequest struct {
+	Index *int `json:"index"`
+}
+
+func NewLiveHandlers(s liveports.LiveService) *LiveHandlers {
+	return &LiveHandlers{service: s}
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("service"))
+		return 0, false
+	}
+	return uint(id64), true
+}
+
+func (h *LiveHandlers) State(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	serviceID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	state, err := h.service.State(serviceID)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
+// operate resuelve usuario y servicio y aplica el movimiento del operador.
+func (h *LiveHandlers) operate(c *gin.Context, action func(serviceID uint, user *models.User) (*models.LiveState, error)) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	serviceID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	state, err := action(serviceID, user)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
+func (h *LiveHandlers) Start(c *gin.Context) {
+	h.operate(c, h.service.Start)
+}
+
+func (h *LiveHandlers) Advance(c *gin.Context) {
+	h.operate(c, h.service.Advance)
+}
+
+func (h *LiveHandlers) Rewind(c *gin.Context) {
+	h.operate(c, h.service.Rewind)
+}
+
+func (h *LiveHandlers) End(c *gin.Context) {
+	h.operate(c, h.service.End)
+}
+
+// GoTo salta a un ítem por su posición en el orden (empezando en 0).
+func (h *LiveHandlers) GoTo(c *gin.Context) {
+	var input goToRequest
+	if err := c.ShouldBindJSON(&input); err != nil || input.Index == nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+	h.operate(c, func(serviceID uint, user *models.User) (*models.LiveState, error) {
+		return h.service.GoTo(serviceID, *input.Index, user)
+	})
+}
+
+// Stream envía el estado por SSE: uno al conectarse y uno por cada cambio, más
+// un ping cada pingInterval. Es el único endpoint que acepta ?token=, porque
+// EventSource no puede mandar headers.
+func (h *LiveHandlers) Stream(c *gin.Context) {
+	if _, err := shared.CurrentUserOrQueryToken(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	serviceID, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	updates, cancel := h.service.Subscribe(serviceID)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	if state, err := h.service.State(serviceID); err == nil {
+		c.SSEvent("state", state)
+		c.Writer.Flush()
+	}
+
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case state, open := <-updates:
+			if !open {
+				return false
+			}
+			c.SSEvent("state", state)
+		case <-ticker.C:
+			c.SSEvent("ping", gin.H{"server_time": time.Now()})
+		}
+		return true
+	})
+}
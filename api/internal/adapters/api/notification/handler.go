@@ -0,0 +1,138 @@
+package notificationapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	notificationports "melodiapp/internal/ports/notification"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+type NotificationHandlers struct {
+	service notificationports.NotificationService
+}
+
+type markReadRequest struct {
+	IDs []uint `json:"ids"`
+}
+
+type preferencesRequest struct {
+	Preferences []models.NotificationPreference `json:"preferences"`
+}
+
+func NewNotificationHandlers(s notificationports.NotificationService) *NotificationHandlers {
+	return &NotificationHandlers{service: s}
+}
+
+// List devuelve el inbox del usuario actual; ?unread=true filtra las no leídas.
+func (h *NotificationHandlers) List(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	limit := 50
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n <= 0 {
+			shared.Fail(c, shared.ErrInvalidData)
+			return
+		}
+		limit = n
+	}
+
+	list, err := h.service.List(user.ID, c.Query("unread") == "true", limit)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	unread, err := h.service.CountUnread(user.ID)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"notifications": list, "unread": unread})
+}
+
+// MarkRead marca como leídas las ids recibidas, o todas si no se envía ninguna.
+func (h *NotificationHandlers) MarkRead(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	var input markReadRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			shared.Fail(c, shared.ErrInvalidData)
+			return
+		}
+	}
+
+	if err := h.service.MarkRead(user.ID, input.IDs); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *NotificationHandlers) MarkOneRead(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("notification"))
+		return
+	}
+
+	if err := h.service.MarkRead(user.ID, []uint{uint(id64)}); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *NotificationHandlers) Preferences(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	prefs, err := h.service.Preferences(user.ID)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
+
+func (h *NotificationHandlers) UpdatePreferences(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	var input preferencesRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	prefs, err := h.service.UpdatePreferences(user.ID, input.Preferences)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
@@ -0,0 +1,167 @@
+package outfitapi
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	outfitports "melodiapp/internal/ports/outfit"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+type OutfitHandlers struct {
+	service outfitports.OutfitService
+}
+
+func NewOutfitHandlers(s outfitports.OutfitService) *OutfitHandlers {
+	return &OutfitHandlers{service: s}
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("outfit"))
+		return 0, false
+	}
+	return uint(id64), true
+}
+
+func (h *OutfitHandlers) GetAll(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	outfits, err := h.service.GetAll()
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, outfits)
+}
+
+func (h *OutfitHandlers) GetByID(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	outfit, err := h.service.GetByID(id)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	if outfit == nil {
+		shared.Fail(c, models.ErrOutfitNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, outfit)
+}
+
+func (h *OutfitHandlers) Create(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	var input models.Outfit
+	if err := c.ShouldBindJSON(&input); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	created, err := h.service.Create(&input)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *OutfitHandlers) Update(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input models.Outfit
+	if err := c.ShouldBindJSON(&input); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	updated, err := h.service.Update(id, &input)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// UploadImage guarda la imagen del outfit (multipart, campo "file").
+func (h *OutfitHandlers) UploadImage(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		shared.Fail(c, models.Validation(map[string]string{"file": "required"}))
+		return
+	}
+
+	uploadDir := "public/outfits"
+	if _, err := os.Stat(uploadDir); os.IsNotExist(err) {
+		os.MkdirAll(uploadDir, 0755)
+	}
+
+	filename := fmt.Sprintf("outfit_%d_%d_%s", id, time.Now().UnixNano(), file.Filename)
+	filepath := fmt.Sprintf("%s/%s", uploadDir, filename)
+	if err := c.SaveUploadedFile(file, filepath); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	updated, err := h.service.SetImage(id, fmt.Sprintf("/files/outfits/%s", filename))
+	if err != nil {
+		os.Remove(filepath)
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *OutfitHandlers) Delete(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{"id": id})
+}
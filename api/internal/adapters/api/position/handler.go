@@ -0,0 +1,182 @@
+package positionapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	positionports "melodiapp/internal/ports/position"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+type PositionHandlers struct {
+	service positionports.PositionService
+}
+
+type serviceSlotsRequest struct {
+	Slots []models.ServicePosition `json:"slots"`
+}
+
+func NewPositionHandlers(s positionports.PositionService) *PositionHandlers {
+	return &PositionHandlers{service: s}
+}
+
+func parseID(c *gin.Context, param string, resource string) (uint, bool) {
+	id64, err := strconv.ParseUint(c.Param(param), 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID(resource))
+		return 0, false
+	}
+	return uint(id64), true
+}
+
+func (h *PositionHandlers) GetAll(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	positions, err := h.service.GetAll()
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, positions)
+}
+
+func (h *PositionHandlers) GetByID(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	id, ok := parseID(c, "id", "position")
+	if !ok {
+		return
+	}
+
+	position, err := h.service.GetByID(id)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	if position == nil {
+		shared.Fail(c, models.ErrPositionNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, position)
+}
+
+func (h *PositionHandlers) Create(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	var input models.Position
+	if err := c.ShouldBindJSON(&input); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	created, err := h.service.Create(&input)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *PositionHandlers) Update(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	id, ok := parseID(c, "id", "position")
+	if !ok {
+		return
+	}
+
+	var input models.Position
+	if err := c.ShouldBindJSON(&input); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	updated, err := h.service.Update(id, &input)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *PositionHandlers) Delete(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	id, ok := parseID(c, "id", "position")
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{"id": id})
+}
+
+// ListServiceSlots muestra los cupos cubiertos y pendientes de un servicio.
+func (h *PositionHandlers) ListServiceSlots(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	serviceID, ok := parseID(c, "id", "service")
+	if !ok {
+		return
+	}
+
+	slots, err := h.service.ServiceBreakdown(serviceID)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, slots)
+}
+
+// SetServiceSlots reemplaza los cupos requeridos por puesto de un servicio.
+func (h *PositionHandlers) SetServiceSlots(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	serviceID, ok := parseID(c, "id", "service")
+	if !ok {
+		return
+	}
+
+	var req serviceSlotsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	if err := h.service.SetServiceSlots(serviceID, req.Slots); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	slots, err := h.service.ServiceBreakdown(serviceID)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, slots)
+}
@@ -0,0 +1,241 @@
+package rehearsalapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	rehearsalports "melodiapp/internal/ports/rehearsal"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+type RehearsalHandlers struct {
+	service rehearsalports.RehearsalService
+}
+
+type rehearsalRequest struct {
+	Name       string    `json:"name"`
+	StartTime  time.Time `json:"start_time"`
+	EndTime    time.Time `json:"end_time"`
+	Location   string    `json:"location"`
+	Notes      string    `json:"notes"`
+	ServiceIDs []uint    `json:"service_ids"`
+}
+
+type rsvpRequest struct {
+	RSVP string `json:"rsvp"`
+	Note string `json:"note"`
+}
+
+func NewRehearsalHandlers(s rehearsalports.RehearsalService) *RehearsalHandlers {
+	return &RehearsalHandlers{service: s}
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("rehearsal"))
+		return 0, false
+	}
+	return uint(id64), true
+}
+
+// parseQueryTime acepta RFC3339 o solo la fecha (YYYY-MM-DD).
+func parseQueryTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+func (h *RehearsalHandlers) List(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	from, err := parseQueryTime(c.Query("from"))
+	if err != nil {
+		shared.Fail(c, models.ErrInvalidDateRange)
+		return
+	}
+	to, err := parseQueryTime(c.Query("to"))
+	if err != nil {
+		shared.Fail(c, models.ErrInvalidDateRange)
+		return
+	}
+
+	list, err := h.service.List(from, to)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// Mine devuelve los ensayos a los que está invitado el usuario actual.
+func (h *RehearsalHandlers) Mine(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	list, err := h.service.ListByUser(user.ID)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+func (h *RehearsalHandlers) GetByID(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	rehearsal, err := h.service.GetByID(id)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	if rehearsal == nil {
+		shared.Fail(c, models.ErrRehearsalNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, rehearsal)
+}
+
+func (h *RehearsalHandlers) Create(c *gin.Context) {
+	user, ok := shared.RequireAdmin(c)
+	if !ok {
+		return
+	}
+
+	var input rehearsalRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	rehearsal := &models.Rehearsal{
+		Name:      input.Name,
+		StartTime: input.StartTime,
+		EndTime:   input.EndTime,
+		Location:  input.Location,
+		Notes:     input.Notes,
+		CreatedBy: user.ID,
+	}
+	created, err := h.service.Create(rehearsal, input.ServiceIDs)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *RehearsalHandlers) Update(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input rehearsalRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	rehearsal := &models.Rehearsal{
+		Name:      input.Name,
+		StartTime: input.StartTime,
+		EndTime:   input.EndTime,
+		Location:  input.Location,
+		Notes:     input.Notes,
+	}
+	updated, err := h.service.Update(id, rehearsal, input.ServiceIDs)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+func (h *RehearsalHandlers) Delete(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// SyncAttendees vuelve a tomar los asistentes del equipo actual de los servicios.
+func (h *RehearsalHandlers) SyncAttendees(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	rehearsal, err := h.service.SyncAttendees(id)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rehearsal)
+}
+
+// RSVP registra la respuesta del usuario actual.
+func (h *RehearsalHandlers) RSVP(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+
+	var input rsvpRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	attendee, err := h.service.RSVP(id, user.ID, input.RSVP, input.Note)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, attendee)
+}
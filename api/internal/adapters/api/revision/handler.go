@@ -0,0 +1,157 @@
+package revisionapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	revisionports "melodiapp/internal/ports/revision"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+type RevisionHandlers struct {
+	service revisionports.RevisionService
+}
+
+func NewRevisionHandlers(s revisionports.RevisionService) *RevisionHandlers {
+	return &RevisionHandlers{service: s}
+}
+
+func parseID(c *gin.Context) (uint, bool) {
+	id64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		shared.Fail(c, models.Invalid("invalid_id", "Invalid id"))
+		return 0, false
+	}
+	return uint(id64), true
+}
+
+func parseVersion(c *gin.Context, raw string) (int, bool) {
+	version, err := strconv.Atoi(raw)
+	if err != nil || version <= 0 {
+		shared.Fail(c, models.Invalid("invalid_version", "Invalid version"))
+		return 0, false
+	}
+	return version, true
+}
+
+// List devuelve las revisiones de la entidad, de la más nueva a la más vieja.
+func (h *RevisionHandlers) List(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, err := shared.CurrentUser(c); err != nil {
+			shared.Fail(c, err)
+			return
+		}
+		id, ok := parseID(c)
+		if !ok {
+			return
+		}
+
+		revisions, err := h.service.List(entityType, id)
+		if err != nil {
+			shared.Fail(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, revisions)
+	}
+}
+
+// Diff compara dos revisiones: ?from=N&to=M.
+func (h *RevisionHandlers) Diff(entityType string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, err := shared.CurrentUser(c); err != nil {
+			shared.Fail(c, err)
+			return
+		}
+		id, ok := parseID(c)
+		if !ok {
+			return
+		}
+		from, ok := parseVersion(c, c.Query("from"))
+		if !ok {
+			return
+		}
+		to, ok := parseVersion(c, c.Query("to"))
+		if !ok {
+			return
+		}
+
+		diff, err := h.service.Diff(entityType, id, from, to)
+		if err != nil {
+			shared.Fail(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, diff)
+	}
+}
+
+func (h *RevisionHandlers) RevertSong(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	version, ok := parseVersion(c, c.Param("version"))
+	if !ok {
+		return
+	}
+	expected, ok := shared.IfMatch(c)
+	if !ok {
+		return
+	}
+
+	song, err := h.service.RevertSong(shared.AuditContext(c), id, version, expected)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	shared.SetETag(c, song.Version)
+	c.JSON(http.StatusOK, song)
+}
+
+type revertServiceRequest struct {
+	// Override revierte el equipo aunque haya conflictos de agenda.
+	Override bool `json:"override"`
+}
+
+type revertServiceResponse struct {
+	*models.ServiceDetail
+	Conflicts []models.AssignmentConflict `json:"conflicts,omitempty"`
+}
+
+func (h *RevisionHandlers) RevertService(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+	id, ok := parseID(c)
+	if !ok {
+		return
+	}
+	version, ok := parseVersion(c, c.Param("version"))
+	if !ok {
+		return
+	}
+	expected, ok := shared.IfMatch(c)
+	if !ok {
+		return
+	}
+	var req revertServiceRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			shared.Fail(c, shared.ErrInvalidData)
+			return
+		}
+	}
+
+	detail, conflicts, err := h.service.RevertService(shared.AuditContext(c), id, version, expected, req.Override)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	shared.SetETag(c, detail.Version)
+	c.JSON(http.StatusOK, revertServiceResponse{ServiceDetail: detail, Conflicts: conflicts})
+}
@@ -0,0 +1,60 @@
+package rosterapi
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	rosterports "melodiapp/internal/ports/roster"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+type RosterHandlers struct {
+	service rosterports.RosterService
+}
+
+func NewRosterHandlers(s rosterports.RosterService) *RosterHandlers {
+	return &RosterHandlers{service: s}
+}
+
+// Generate devuelve un borrador de turnos; no modifica ningún servicio.
+func (h *RosterHandlers) Generate(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	var options models.RosterOptions
+	if err := c.ShouldBindJSON(&options); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	draft, err := h.service.Generate(options)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, draft)
+}
+
+// Commit guarda el borrador (posiblemente editado por el admin).
+func (h *RosterHandlers) Commit(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	var draft models.RosterDraft
+	if err := c.ShouldBindJSON(&draft); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	if err := h.service.Commit(shared.AuditContext(c), &draft); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"services": len(draft.Services)})
+}
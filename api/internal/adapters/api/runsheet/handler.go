@@ -0,0 +1,180 @@
+package runsheetapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	runsheetports "melodiapp/internal/ports/runsheet"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+type RunSheetHandlers struct {
+	service runsheetports.RunSheetService
+}
+
+type replaceRunSheetRequest struct {
+	Items []models.RunSheetItem `json:"items"`
+}
+
+type reorderRunSheetRequest struct {
+	ItemIDs []uint `json:"item_ids"`
+}
+
+func NewRunSheetHandlers(s runsheetports.RunSheetService) *RunSheetHandlers {
+	return &RunSheetHandlers{service: s}
+}
+
+func parseID(c *gin.Context, param string, resource string) (uint, bool) {
+	id64, err := strconv.ParseUint(c.Param(param), 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID(resource))
+		return 0, false
+	}
+	return uint(id64), true
+}
+
+func (h *RunSheetHandlers) Get(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	serviceID, ok := parseID(c, "id", "service")
+	if !ok {
+		return
+	}
+
+	sheet, err := h.service.Get(serviceID)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sheet)
+}
+
+// Replace arma el orden completo con los ítems recibidos.
+func (h *RunSheetHandlers) Replace(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	serviceID, ok := parseID(c, "id", "service")
+	if !ok {
+		return
+	}
+
+	var input replaceRunSheetRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	sheet, err := h.service.Replace(serviceID, input.Items)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sheet)
+}
+
+func (h *RunSheetHandlers) AddItem(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	serviceID, ok := parseID(c, "id", "service")
+	if !ok {
+		return
+	}
+
+	var input models.RunSheetItem
+	if err := c.ShouldBindJSON(&input); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	sheet, err := h.service.AddItem(serviceID, &input)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, sheet)
+}
+
+func (h *RunSheetHandlers) UpdateItem(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	serviceID, ok := parseID(c, "id", "service")
+	if !ok {
+		return
+	}
+	itemID, ok := parseID(c, "itemId", "item")
+	if !ok {
+		return
+	}
+
+	var input models.RunSheetItem
+	if err := c.ShouldBindJSON(&input); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	sheet, err := h.service.UpdateItem(serviceID, itemID, &input)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sheet)
+}
+
+func (h *RunSheetHandlers) RemoveItem(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	serviceID, ok := parseID(c, "id", "service")
+	if !ok {
+		return
+	}
+	itemID, ok := parseID(c, "itemId", "item")
+	if !ok {
+		return
+	}
+
+	sheet, err := h.service.RemoveItem(serviceID, itemID)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sheet)
+}
+
+// Reorder recibe todos los ids de los ítems en el nuevo orden.
+func (h *RunSheetHandlers) Reorder(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	serviceID, ok := parseID(c, "id", "service")
+	if !ok {
+		return
+	}
+
+	var input reorderRunSheetRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	sheet, err := h.service.Reorder(serviceID, input.ItemIDs)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sheet)
+}
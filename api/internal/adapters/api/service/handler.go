@@ -1,15 +1,11 @@
 package serviceapi
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
-	"os"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 
-	"melodiapp/database"
 	serviceports "melodiapp/internal/ports/service"
 	"melodiapp/models"
 	"melodiapp/shared"
@@ -23,264 +19,197 @@ func NewServiceHandlers(s serviceports.ServiceService) *ServiceHandlers {
 	return &ServiceHandlers{service: s}
 }
 
-func getCurrentUser(c *gin.Context) (*models.User, error) {
-	tokenStr := shared.GetTokenFromRequest(c)
-	if tokenStr == "" {
-		return nil, fmt.Errorf("invalid token")
-	}
-
-	token, err := jwt.ParseWithClaims(tokenStr, &shared.Payload{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("invalid token")
-		}
-		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	claims, _ := token.Claims.(*shared.Payload)
-	session, exists := shared.Sessions[claims.Session]
-	if !exists || session.ExpiryTime.Before(time.Now()) {
-		return nil, fmt.Errorf("You don't have permission")
-	}
-
-	var user models.User
-	if err := database.DBConn.First(&user, session.Uid).Error; err != nil {
-		return nil, err
-	}
-
-	return &user, nil
-}
-
-// --- FUNCIÓN AUXILIAR PARA ENRIQUECER SERVICIOS ---
-// Esta función hace el trabajo pesado de buscar canciones, usuarios Y OUTFITS
-func getServiceDetails(service *models.Service) (gin.H, error) {
-
-	// 1. Obtener canciones asociadas
-	var serviceSongs []models.ServiceSong
-	if err := database.DBConn.Where("service_id = ?", service.ID).Find(&serviceSongs).Error; err != nil {
-		return nil, err
-	}
-
-	songIDs := make([]uint, 0, len(serviceSongs))
-	for _, ss := range serviceSongs {
-		songIDs = append(songIDs, ss.SongID)
-	}
-
-	var songs []models.Song
-	if len(songIDs) > 0 {
-		if err := database.DBConn.Where("id IN ?", songIDs).Find(&songs).Error; err != nil {
-			return nil, err
-		}
-	} else {
-		songs = []models.Song{}
-	}
-
-	// 2. Obtener usuarios asociados con su status
-	var serviceUsers []models.ServiceUser
-	if err := database.DBConn.Where("service_id = ?", service.ID).Find(&serviceUsers).Error; err != nil {
-		return nil, err
-	}
-
-	userIDs := make([]uint, 0, len(serviceUsers))
-	statusByUser := make(map[uint]string)
-	for _, su := range serviceUsers {
-		userIDs = append(userIDs, su.UserID)
-		statusByUser[su.UserID] = su.Status
-	}
-
-	var users []models.User
-	if len(userIDs) > 0 {
-		if err := database.DBConn.Where("id IN ?", userIDs).Find(&users).Error; err != nil {
-			return nil, err
-		}
-	}
-
-	// Estructura para el detalle del usuario en la respuesta
-	type ServiceUserDetail struct {
-		ID            uint   `json:"id"`
-		Username      string `json:"username"`
-		Email         string `json:"email"`
-		Role          string `json:"role"`
-		SecondaryRole string `json:"secondary_role"`
-		Status        string `json:"status"`
-	}
-
-	detailedUsers := make([]ServiceUserDetail, 0, len(users))
-	for _, u := range users {
-		detailedUsers = append(detailedUsers, ServiceUserDetail{
-			ID:            u.ID,
-			Username:      u.Username,
-			Email:         u.Email,
-			Role:          u.Role,
-			SecondaryRole: u.SecondaryRole,
-			Status:        statusByUser[u.ID],
-		})
-	}
-
-	// --- 3. NUEVO: Obtener OUTFITS (Paleta de Colores) asociados ---
-	var serviceOutfits []models.ServiceOutfit
-	if err := database.DBConn.Where("service_id = ?", service.ID).Find(&serviceOutfits).Error; err != nil {
-		return nil, err
-	}
-	// Inicializar como array vacío si es nil para evitar problemas en frontend
-	if serviceOutfits == nil {
-		serviceOutfits = []models.ServiceOutfit{}
-	}
-
-	// Construir el objeto de respuesta unificado
-	return gin.H{
-		"id":         service.ID,
-		"name":       service.Name,
-		"start_time": service.StartTime,
-		"end_time":   service.EndTime,
-		"created_by": service.CreatedBy,
-		"created_at": service.CreatedAt,
-		"updated_at": service.UpdatedAt,
-		"songs":      songs,
-		"users":      detailedUsers,
-		"outfits":    serviceOutfits, // <--- CAMPO AGREGADO
-	}, nil
+// bundleResponse agrega los conflictos que se aceptaron con override.
+type bundleResponse struct {
+	*models.ServiceDetail
+	Conflicts []models.AssignmentConflict `json:"conflicts,omitempty"`
 }
 
 // --- HANDLERS ---
 
 func (h *ServiceHandlers) GetAll(c *gin.Context) {
-	if _, err := getCurrentUser(c); err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
 	services, err := h.service.GetAll()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 
-	var fullServices []gin.H
-	for _, s := range services {
-		details, err := getServiceDetails(&s)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error loading details: " + err.Error()})
-			return
-		}
-		fullServices = append(fullServices, details)
-	}
-
-	if fullServices == nil {
-		fullServices = []gin.H{}
+	fullServices, err := h.service.DetailsList(services)
+	if err != nil {
+		shared.Fail(c, err)
+		return
 	}
 
 	c.JSON(http.StatusOK, fullServices)
 }
 
 func (h *ServiceHandlers) GetByID(c *gin.Context) {
-	if _, err := getCurrentUser(c); err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
 	id := c.Param("id")
 	service, err := h.service.GetByID(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 	if service == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+		shared.Fail(c, models.ErrServiceNotFound)
 		return
 	}
 
-	response, err := getServiceDetails(service)
+	response, err := h.nested(service)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 
-	nestedResponse := gin.H{
-		"service": service,
-		"songs":   response["songs"],
-		"users":   response["users"],
-		"outfits": response["outfits"], // <--- CAMPO AGREGADO
-	}
+	shared.SetETag(c, service.Version)
+	c.JSON(http.StatusOK, response)
+}
 
-	c.JSON(http.StatusOK, nestedResponse)
+// nested es la forma en que GET /services/:id devuelve el servicio.
+func (h *ServiceHandlers) nested(service *models.Service) (gin.H, error) {
+	details, err := h.service.Details(service)
+	if err != nil {
+		return nil, err
+	}
+	return gin.H{
+		"service":   service,
+		"songs":     details.Songs,
+		"users":     details.Users,
+		"outfits":   details.Outfits,
+		"positions": details.Positions,
+	}, nil
 }
 
-func (h *ServiceHandlers) Create(c *gin.Context) {
-	user, err := getCurrentUser(c)
+// writeStale responde 412 con el servicio tal como está ahora, para que el
+// cliente vuelva a aplicar sus cambios sobre la versión actual.
+func (h *ServiceHandlers) writeStale(c *gin.Context, id string) {
+	service, err := h.service.GetByID(id)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	if service == nil {
+		shared.Fail(c, models.ErrServiceNotFound)
+		return
+	}
+	current, err := h.nested(service)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
-	if user.Role != "admin" {
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You don't have permission"})
+	shared.SetETag(c, service.Version)
+	shared.Fail(c, models.ErrVersionMismatch.With("current", current))
+}
+
+// Create crea el servicio; si trae songs, users u outfits los guarda en la
+// misma transacción.
+func (h *ServiceHandlers) Create(c *gin.Context) {
+	user, ok := shared.RequireAdmin(c)
+	if !ok {
 		return
 	}
 
-	var input models.Service
+	var input models.ServiceBundle
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		shared.Fail(c, shared.ErrInvalidData)
 		return
 	}
 
 	input.CreatedBy = user.ID
 
-	created, err := h.service.Create(&input)
+	created, conflicts, err := h.service.CreateBundle(shared.AuditContext(c), &input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, created)
+	shared.SetETag(c, created.Version)
+	c.JSON(http.StatusCreated, bundleResponse{ServiceDetail: created, Conflicts: conflicts})
 }
 
+// Update guarda el servicio completo: las listas que vengan reemplazan a las
+// actuales y las que no vengan quedan como están.
 func (h *ServiceHandlers) Update(c *gin.Context) {
-	user, err := getCurrentUser(c)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	if _, ok := shared.RequireAdmin(c); !ok {
 		return
 	}
-	if user.Role != "admin" {
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You don't have permission"})
+
+	version, ok := shared.IfMatch(c)
+	if !ok {
 		return
 	}
 
 	id := c.Param("id")
-	var input models.Service
+	var input models.ServiceBundle
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		shared.Fail(c, shared.ErrInvalidData)
 		return
 	}
+	input.Version = version
 
-	updated, err := h.service.Update(id, &input)
+	updated, conflicts, err := h.service.SaveBundle(shared.AuditContext(c), id, &input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		if errors.Is(err, models.ErrVersionMismatch) {
+			h.writeStale(c, id)
+			return
+		}
+		shared.Fail(c, err)
 		return
 	}
-	if updated == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+
+	shared.SetETag(c, updated.Version)
+	c.JSON(http.StatusOK, bundleResponse{ServiceDetail: updated, Conflicts: conflicts})
+}
+
+// Patch cambia solo lo que viene en el cuerpo (JSON Merge Patch); las listas
+// que vengan reemplazan a las actuales.
+func (h *ServiceHandlers) Patch(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, updated)
-}
+	version, ok := shared.IfMatch(c)
+	if !ok {
+		return
+	}
+	patch, ok := shared.MergePatchBody(c)
+	if !ok {
+		return
+	}
 
-func (h *ServiceHandlers) Delete(c *gin.Context) {
-	user, err := getCurrentUser(c)
+	id := c.Param("id")
+	updated, conflicts, err := h.service.Patch(shared.AuditContext(c), id, patch, version)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		if errors.Is(err, models.ErrVersionMismatch) {
+			h.writeStale(c, id)
+			return
+		}
+		shared.Fail(c, err)
 		return
 	}
-	if user.Role != "admin" {
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You don't have permission"})
+
+	shared.SetETag(c, updated.Version)
+	c.JSON(http.StatusOK, bundleResponse{ServiceDetail: updated, Conflicts: conflicts})
+}
+
+func (h *ServiceHandlers) Delete(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
 		return
 	}
 
 	id := c.Param("id")
-	if err := h.service.Delete(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.Delete(shared.AuditContext(c), id); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 	c.JSON(http.StatusNoContent, gin.H{"id": id})
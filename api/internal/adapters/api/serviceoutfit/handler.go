@@ -8,6 +8,8 @@ import (
 
 	// Asumo que crearás este puerto/interface similar al de songs
 	serviceoutfitports "melodiapp/internal/ports/serviceoutfit"
+	"melodiapp/models"
+	"melodiapp/shared"
 )
 
 type ServiceOutfitHandlers struct {
@@ -25,23 +27,27 @@ func NewServiceOutfitHandlers(s serviceoutfitports.ServiceOutfitService) *Servic
 
 // AssignOutfits vincula uno o más outfits a un servicio
 func (h *ServiceOutfitHandlers) AssignOutfits(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
 	serviceIDParam := c.Param("id")
 	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service id"})
+		shared.Fail(c, shared.InvalidID("service"))
 		return
 	}
 
 	var req assignOutfitsRequest
 	// Valida que el JSON sea correcto y que el array no esté vacío
 	if err := c.ShouldBindJSON(&req); err != nil || len(req.OutfitIDs) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data, outfit_ids required"})
+		shared.Fail(c, models.Validation(map[string]string{"outfit_ids": "required"}))
 		return
 	}
 
 	// Llama al servicio para guardar la relación en la tabla service_outfit
-	if err := h.service.AssignOutfits(uint(serviceID64), req.OutfitIDs); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.AssignOutfits(shared.AuditContext(c), uint(serviceID64), req.OutfitIDs); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
@@ -53,13 +59,13 @@ func (h *ServiceOutfitHandlers) ListByService(c *gin.Context) {
 	serviceIDParam := c.Param("id")
 	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service id"})
+		shared.Fail(c, shared.InvalidID("service"))
 		return
 	}
 
 	items, err := h.service.ListByService(uint(serviceID64))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 
@@ -68,22 +74,26 @@ func (h *ServiceOutfitHandlers) ListByService(c *gin.Context) {
 
 // Remove elimina la relación entre un servicio y un outfit específico
 func (h *ServiceOutfitHandlers) Remove(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
 	serviceIDParam := c.Param("id")
 	outfitIDParam := c.Param("outfitId") // Asegúrate de usar este param en la ruta de Gin
 
 	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service id"})
+		shared.Fail(c, shared.InvalidID("service"))
 		return
 	}
 	outfitID64, err := strconv.ParseUint(outfitIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid outfit id"})
+		shared.Fail(c, shared.InvalidID("outfit"))
 		return
 	}
 
-	if err := h.service.Remove(uint(serviceID64), uint(outfitID64)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.Remove(shared.AuditContext(c), uint(serviceID64), uint(outfitID64)); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 
 	servicesongports "melodiapp/internal/ports/servicesong"
+	"melodiapp/shared"
 )
 
 type ServiceSongHandlers struct {
@@ -17,43 +18,113 @@ type assignSongsRequest struct {
 	SongIDs []uint `json:"song_ids"`
 }
 
+type updateSongsRequest struct {
+	Add    []uint `json:"add"`
+	Remove []uint `json:"remove"`
+}
+
 func NewServiceSongHandlers(s servicesongports.ServiceSongService) *ServiceSongHandlers {
 	return &ServiceSongHandlers{service: s}
 }
 
 func (h *ServiceSongHandlers) AssignSongs(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
 	serviceIDParam := c.Param("id")
 	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service id"})
+		shared.Fail(c, shared.InvalidID("service"))
 		return
 	}
 
 	var req assignSongsRequest
 	if err := c.ShouldBindJSON(&req); err != nil || len(req.SongIDs) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		shared.Fail(c, shared.ErrInvalidData)
 		return
 	}
 
-	if err := h.service.AssignSongs(uint(serviceID64), req.SongIDs); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.AssignSongs(shared.AuditContext(c), uint(serviceID64), req.SongIDs); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, gin.H{"service_id": serviceIDParam, "song_ids": req.SongIDs})
 }
 
+// UpdateSongs agrega y quita canciones sin reemplazar el resto del repertorio.
+func (h *ServiceSongHandlers) UpdateSongs(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	serviceIDParam := c.Param("id")
+	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("service"))
+		return
+	}
+
+	var req updateSongsRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (len(req.Add) == 0 && len(req.Remove) == 0) {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	if err := h.service.UpdateSongs(shared.AuditContext(c), uint(serviceID64), req.Add, req.Remove); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	items, err := h.service.ListByService(uint(serviceID64))
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// AddSong agrega una canción al repertorio. Repetir la llamada no cambia nada.
+func (h *ServiceSongHandlers) AddSong(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	serviceIDParam := c.Param("id")
+	songIDParam := c.Param("songId")
+
+	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("service"))
+		return
+	}
+	songID64, err := strconv.ParseUint(songIDParam, 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("song"))
+		return
+	}
+
+	if err := h.service.AddSong(shared.AuditContext(c), uint(serviceID64), uint(songID64)); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"service_id": serviceIDParam, "song_id": songIDParam})
+}
+
 func (h *ServiceSongHandlers) ListByService(c *gin.Context) {
 	serviceIDParam := c.Param("id")
 	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service id"})
+		shared.Fail(c, shared.InvalidID("service"))
 		return
 	}
 
 	items, err := h.service.ListByService(uint(serviceID64))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 
@@ -61,22 +132,26 @@ func (h *ServiceSongHandlers) ListByService(c *gin.Context) {
 }
 
 func (h *ServiceSongHandlers) Remove(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
 	serviceIDParam := c.Param("id")
 	songIDParam := c.Param("songId")
 
 	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service id"})
+		shared.Fail(c, shared.InvalidID("service"))
 		return
 	}
 	songID64, err := strconv.ParseUint(songIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid song id"})
+		shared.Fail(c, shared.InvalidID("song"))
 		return
 	}
 
-	if err := h.service.Remove(uint(serviceID64), uint(songID64)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.Remove(shared.AuditContext(c), uint(serviceID64), uint(songID64)); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
@@ -1,16 +1,11 @@
 package serviceuserapi
 
 import (
-	"fmt"
 	"net/http"
-	"os"
 	"strconv"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 
-	"melodiapp/database"
 	serviceuserports "melodiapp/internal/ports/serviceuser"
 	"melodiapp/models"
 	"melodiapp/shared"
@@ -20,96 +15,229 @@ type ServiceUserHandlers struct {
 	service serviceuserports.ServiceUserService
 }
 
+// assignUsersRequest acepta una lista simple de user_ids o, para indicar el
+// puesto de cada uno, una lista de assignments.
 type assignUsersRequest struct {
-	UserIDs []uint `json:"user_ids"`
+	UserIDs     []uint                  `json:"user_ids"`
+	Assignments []models.UserAssignment `json:"assignments"`
+	Override    bool                    `json:"override"`
+}
+
+func (r *assignUsersRequest) toAssignments() []models.UserAssignment {
+	if len(r.Assignments) > 0 {
+		return r.Assignments
+	}
+	assignments := make([]models.UserAssignment, 0, len(r.UserIDs))
+	for _, uid := range r.UserIDs {
+		assignments = append(assignments, models.UserAssignment{UserID: uid})
+	}
+	return assignments
+}
+
+// updateTeamRequest es el diff de PATCH /services/:id/users.
+type updateTeamRequest struct {
+	Add      []models.UserAssignment `json:"add"`
+	Remove   []uint                  `json:"remove"`
+	Override bool                    `json:"override"`
+}
+
+type addUserRequest struct {
+	PositionID uint `json:"position_id"`
+	Override   bool `json:"override"`
 }
 
 type changeStatusRequest struct {
 	Status string `json:"status"`
+	Reason string `json:"reason"`
+}
+
+type changePositionRequest struct {
+	PositionID uint `json:"position_id"`
 }
 
 func NewServiceUserHandlers(s serviceuserports.ServiceUserService) *ServiceUserHandlers {
 	return &ServiceUserHandlers{service: s}
 }
 
-func getCurrentUser(c *gin.Context) (*models.User, error) {
-	tokenStr := shared.GetTokenFromRequest(c)
-	if tokenStr == "" {
-		return nil, fmt.Errorf("invalid token")
+// AssignUsers reemplaza el equipo completo (POST y PUT /services/:id/users).
+func (h *ServiceUserHandlers) AssignUsers(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
 	}
 
-	token, err := jwt.ParseWithClaims(tokenStr, &shared.Payload{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("invalid token")
-		}
-		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
-	})
+	serviceIDParam := c.Param("id")
+	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
 	if err != nil {
-		return nil, err
+		shared.Fail(c, shared.InvalidID("service"))
+		return
 	}
 
-	claims, _ := token.Claims.(*shared.Payload)
-	session, exists := shared.Sessions[claims.Session]
-	if !exists || session.ExpiryTime.Before(time.Now()) {
-		return nil, fmt.Errorf("You don't have permission")
+	var req assignUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (len(req.UserIDs) == 0 && len(req.Assignments) == 0) {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
 	}
 
-	var user models.User
-	if err := database.DBConn.First(&user, session.Uid).Error; err != nil {
-		return nil, err
+	assignments := req.toAssignments()
+	conflicts, err := h.service.AssignUsers(shared.AuditContext(c), uint(serviceID64), assignments, req.Override)
+	if err != nil {
+		shared.Fail(c, err)
+		return
 	}
 
-	return &user, nil
+	c.JSON(http.StatusCreated, gin.H{"service_id": serviceIDParam, "assignments": assignments, "conflicts": conflicts})
 }
 
-func (h *ServiceUserHandlers) AssignUsers(c *gin.Context) {
-	user, err := getCurrentUser(c)
+// CheckConflicts permite previsualizar los conflictos sin modificar el equipo.
+func (h *ServiceUserHandlers) CheckConflicts(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	serviceIDParam := c.Param("id")
+	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		shared.Fail(c, shared.InvalidID("service"))
 		return
 	}
-	if user.Role != "admin" {
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You don't have permission"})
+
+	var req assignUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (len(req.UserIDs) == 0 && len(req.Assignments) == 0) {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	userIDs := make([]uint, 0)
+	for _, a := range req.toAssignments() {
+		userIDs = append(userIDs, a.UserID)
+	}
+
+	conflicts, err := h.service.CheckConflicts(uint(serviceID64), userIDs)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"service_id": serviceIDParam, "conflicts": conflicts})
+}
+
+// UpdateTeam agrega y quita miembros sin reemplazar al resto del equipo.
+func (h *ServiceUserHandlers) UpdateTeam(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
 		return
 	}
 
 	serviceIDParam := c.Param("id")
 	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service id"})
+		shared.Fail(c, shared.InvalidID("service"))
 		return
 	}
 
-	var req assignUsersRequest
-	if err := c.ShouldBindJSON(&req); err != nil || len(req.UserIDs) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+	var req updateTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (len(req.Add) == 0 && len(req.Remove) == 0) {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	conflicts, err := h.service.UpdateTeam(shared.AuditContext(c), uint(serviceID64), req.Add, req.Remove, req.Override)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	items, err := h.service.ListByService(uint(serviceID64))
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"service_id": serviceIDParam, "users": items, "conflicts": conflicts})
+}
+
+// AddUser agrega un usuario al equipo. Repetir la llamada no cambia nada.
+func (h *ServiceUserHandlers) AddUser(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	serviceIDParam := c.Param("id")
+	userIDParam := c.Param("userId")
+
+	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("service"))
+		return
+	}
+	userID64, err := strconv.ParseUint(userIDParam, 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("user"))
 		return
 	}
 
-	if err := h.service.AssignUsers(uint(serviceID64), req.UserIDs); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// El body es opcional
+	var req addUserRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			shared.Fail(c, shared.ErrInvalidData)
+			return
+		}
+	}
+
+	assignment := models.UserAssignment{UserID: uint(userID64), PositionID: req.PositionID}
+	conflicts, err := h.service.AddUser(shared.AuditContext(c), uint(serviceID64), assignment, req.Override)
+	if err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"service_id": serviceIDParam, "user_ids": req.UserIDs})
+	c.JSON(http.StatusOK, gin.H{"service_id": serviceIDParam, "user_id": userIDParam, "conflicts": conflicts})
+}
+
+// RemoveUser quita a un usuario del equipo. Si no estaba, no hace nada.
+func (h *ServiceUserHandlers) RemoveUser(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	serviceIDParam := c.Param("id")
+	userIDParam := c.Param("userId")
+
+	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("service"))
+		return
+	}
+	userID64, err := strconv.ParseUint(userIDParam, 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("user"))
+		return
+	}
+
+	if err := h.service.RemoveUser(shared.AuditContext(c), uint(serviceID64), uint(userID64)); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, gin.H{"service_id": serviceIDParam, "user_id": userIDParam})
 }
 
 func (h *ServiceUserHandlers) ListByService(c *gin.Context) {
-	if _, err := getCurrentUser(c); err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
 	serviceIDParam := c.Param("id")
 	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service id"})
+		shared.Fail(c, shared.InvalidID("service"))
 		return
 	}
 
 	items, err := h.service.ListByService(uint(serviceID64))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 
@@ -117,9 +245,9 @@ func (h *ServiceUserHandlers) ListByService(c *gin.Context) {
 }
 
 func (h *ServiceUserHandlers) ChangeStatus(c *gin.Context) {
-	user, err := getCurrentUser(c)
+	user, err := shared.CurrentUser(c)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 
@@ -128,30 +256,88 @@ func (h *ServiceUserHandlers) ChangeStatus(c *gin.Context) {
 
 	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service id"})
+		shared.Fail(c, shared.InvalidID("service"))
 		return
 	}
 	userID64, err := strconv.ParseUint(userIDParam, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user id"})
+		shared.Fail(c, shared.InvalidID("user"))
 		return
 	}
 
 	if uint(userID64) != user.ID {
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You don't have permission"})
+		shared.Fail(c, models.ErrForbidden)
 		return
 	}
 
 	var req changeStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil || req.Status == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	updated, err := h.service.ChangeStatus(shared.AuditContext(c), uint(serviceID64), uint(userID64), req.Status, req.Reason)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// StatusHistory lista todos los cambios de estado del equipo del servicio.
+func (h *ServiceUserHandlers) StatusHistory(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	serviceIDParam := c.Param("id")
+	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("service"))
+		return
+	}
+
+	items, err := h.service.StatusHistory(uint(serviceID64))
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// ChangePosition cambia el puesto que un usuario cubre en el servicio.
+func (h *ServiceUserHandlers) ChangePosition(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	serviceIDParam := c.Param("id")
+	userIDParam := c.Param("userId")
+
+	serviceID64, err := strconv.ParseUint(serviceIDParam, 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("service"))
+		return
+	}
+	userID64, err := strconv.ParseUint(userIDParam, 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("user"))
+		return
+	}
+
+	var req changePositionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.PositionID == 0 {
+		shared.Fail(c, shared.ErrInvalidData)
 		return
 	}
 
-	if err := h.service.ChangeStatus(uint(serviceID64), uint(userID64), req.Status); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.ChangePosition(shared.AuditContext(c), uint(serviceID64), uint(userID64), req.PositionID); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"service_id": serviceIDParam, "user_id": userIDParam, "status": req.Status})
+	c.JSON(http.StatusOK, gin.H{"service_id": serviceIDParam, "user_id": userIDParam, "position_id": req.PositionID})
 }
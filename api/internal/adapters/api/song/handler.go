@@ -1,15 +1,11 @@
 package songapi
 
 import (
-	"fmt"
+	"errors"
 	"net/http"
-	"os"
-	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 
-	"melodiapp/database"
 	songports "melodiapp/internal/ports/song"
 	"melodiapp/models"
 	"melodiapp/shared"
@@ -23,140 +19,141 @@ func NewSongHandlers(s songports.SongService) *SongHandlers {
 	return &SongHandlers{service: s}
 }
 
-func getCurrentUser(c *gin.Context) (*models.User, error) {
-	tokenStr := shared.GetTokenFromRequest(c)
-	if tokenStr == "" {
-		return nil, fmt.Errorf("invalid token")
-	}
-
-	token, err := jwt.ParseWithClaims(tokenStr, &shared.Payload{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("invalid token")
-		}
-		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	claims, _ := token.Claims.(*shared.Payload)
-	session, exists := shared.Sessions[claims.Session]
-	if !exists || session.ExpiryTime.Before(time.Now()) {
-		return nil, fmt.Errorf("You don't have permission")
-	}
-
-	var user models.User
-	if err := database.DBConn.First(&user, session.Uid).Error; err != nil {
-		return nil, err
-	}
-
-	return &user, nil
-}
-
 func (h *SongHandlers) GetAll(c *gin.Context) {
-	if _, err := getCurrentUser(c); err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
 	songs, err := h.service.GetAll()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 	c.JSON(http.StatusOK, songs)
 }
 
 func (h *SongHandlers) GetByID(c *gin.Context) {
-	if _, err := getCurrentUser(c); err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
 	id := c.Param("id")
 	song, err := h.service.GetByID(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 	if song == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Song not found"})
+		shared.Fail(c, models.ErrSongNotFound)
 		return
 	}
+	shared.SetETag(c, song.Version)
 	c.JSON(http.StatusOK, song)
 }
 
 func (h *SongHandlers) Create(c *gin.Context) {
-	user, err := getCurrentUser(c)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	}
-	if user.Role != "admin" {
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You don't have permission"})
+	if _, ok := shared.RequireAdmin(c); !ok {
 		return
 	}
 
 	var input models.Song
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		shared.Fail(c, shared.ErrInvalidData)
 		return
 	}
 
-	created, err := h.service.Create(&input)
+	created, err := h.service.Create(shared.AuditContext(c), &input)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 
+	shared.SetETag(c, created.Version)
 	c.JSON(http.StatusCreated, created)
 }
 
 func (h *SongHandlers) Update(c *gin.Context) {
-	user, err := getCurrentUser(c)
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	if _, ok := shared.RequireAdmin(c); !ok {
 		return
 	}
-	if user.Role != "admin" {
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You don't have permission"})
+
+	version, ok := shared.IfMatch(c)
+	if !ok {
 		return
 	}
 
 	id := c.Param("id")
 	var input models.Song
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		shared.Fail(c, shared.ErrInvalidData)
 		return
 	}
+	input.Version = version
 
-	updated, err := h.service.Update(id, &input)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	updated, err := h.service.Update(shared.AuditContext(c), id, &input)
+	h.writeSaved(c, id, updated, err)
+}
+
+// Patch cambia solo los campos que vienen en el cuerpo (JSON Merge Patch).
+func (h *SongHandlers) Patch(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
 		return
 	}
-	if updated == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Song not found"})
+
+	version, ok := shared.IfMatch(c)
+	if !ok {
+		return
+	}
+	patch, ok := shared.MergePatchBody(c)
+	if !ok {
 		return
 	}
 
-	c.JSON(http.StatusOK, updated)
+	id := c.Param("id")
+	updated, err := h.service.Patch(shared.AuditContext(c), id, patch, version)
+	h.writeSaved(c, id, updated, err)
 }
 
-func (h *SongHandlers) Delete(c *gin.Context) {
-	user, err := getCurrentUser(c)
+func (h *SongHandlers) writeSaved(c *gin.Context, id string, saved *models.Song, err error) {
+	if err != nil {
+		if errors.Is(err, models.ErrVersionMismatch) {
+			h.writeStale(c, id)
+			return
+		}
+		shared.Fail(c, err)
+		return
+	}
+
+	shared.SetETag(c, saved.Version)
+	c.JSON(http.StatusOK, saved)
+}
+
+// writeStale responde 412 con la canción tal como está ahora, para que el
+// cliente vuelva a aplicar sus cambios sobre la versión actual.
+func (h *SongHandlers) writeStale(c *gin.Context, id string) {
+	current, err := h.service.GetByID(id)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
-	if user.Role != "admin" {
-		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "You don't have permission"})
+	if current == nil {
+		shared.Fail(c, models.ErrSongNotFound)
+		return
+	}
+	shared.SetETag(c, current.Version)
+	shared.Fail(c, models.ErrVersionMismatch.With("current", current))
+}
+
+func (h *SongHandlers) Delete(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
 		return
 	}
 
 	id := c.Param("id")
-	if err := h.service.Delete(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.Delete(shared.AuditContext(c), id); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 	c.JSON(http.StatusNoContent, gin.H{"id": id})
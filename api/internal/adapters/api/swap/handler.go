@@ -0,0 +1,212 @@
+package swapapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	swapports "melodiapp/internal/ports/swap"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+type SwapHandlers struct {
+	service swapports.SwapService
+}
+
+type requestSwapRequest struct {
+	UserIDs []uint `json:"user_ids"`
+	Note    string `json:"note"`
+}
+
+type rejectSwapRequest struct {
+	Note string `json:"note"`
+}
+
+func NewSwapHandlers(s swapports.SwapService) *SwapHandlers {
+	return &SwapHandlers{service: s}
+}
+
+func parseID(c *gin.Context, param string, resource string) (uint, bool) {
+	id64, err := strconv.ParseUint(c.Param(param), 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID(resource))
+		return 0, false
+	}
+	return uint(id64), true
+}
+
+// Request ofrece el lugar del usuario actual en el servicio.
+func (h *SwapHandlers) Request(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	serviceID, ok := parseID(c, "id", "service")
+	if !ok {
+		return
+	}
+
+	var req requestSwapRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			shared.Fail(c, shared.ErrInvalidData)
+			return
+		}
+	}
+
+	swap, err := h.service.Request(serviceID, user, req.UserIDs, req.Note)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, swap)
+}
+
+func (h *SwapHandlers) ListByService(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	serviceID, ok := parseID(c, "id", "service")
+	if !ok {
+		return
+	}
+
+	items, err := h.service.ListByService(serviceID)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// ListAvailable lista las ofertas abiertas que el usuario actual puede tomar.
+func (h *SwapHandlers) ListAvailable(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	items, err := h.service.ListAvailable(user)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+// GetByID devuelve el intercambio con todo su historial.
+func (h *SwapHandlers) GetByID(c *gin.Context) {
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	id, ok := parseID(c, "swapId", "swap")
+	if !ok {
+		return
+	}
+
+	swap, err := h.service.GetByID(id)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	if swap == nil {
+		shared.Fail(c, models.ErrSwapNotFound)
+		return
+	}
+	c.JSON(http.StatusOK, swap)
+}
+
+func (h *SwapHandlers) Claim(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	id, ok := parseID(c, "swapId", "swap")
+	if !ok {
+		return
+	}
+
+	swap, err := h.service.Claim(id, user)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, swap)
+}
+
+func (h *SwapHandlers) Approve(c *gin.Context) {
+	user, ok := shared.RequireAdmin(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseID(c, "swapId", "swap")
+	if !ok {
+		return
+	}
+
+	swap, err := h.service.Approve(id, user)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, swap)
+}
+
+func (h *SwapHandlers) Reject(c *gin.Context) {
+	user, ok := shared.RequireAdmin(c)
+	if !ok {
+		return
+	}
+
+	id, ok := parseID(c, "swapId", "swap")
+	if !ok {
+		return
+	}
+
+	var req rejectSwapRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			shared.Fail(c, shared.ErrInvalidData)
+			return
+		}
+	}
+
+	swap, err := h.service.Reject(id, user, req.Note)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, swap)
+}
+
+func (h *SwapHandlers) Cancel(c *gin.Context) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	id, ok := parseID(c, "swapId", "swap")
+	if !ok {
+		return
+	}
+
+	swap, err := h.service.Cancel(id, user)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, swap)
+}
@@ -0,0 +1,77 @@
+package trashapi
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	trashports "melodiapp/internal/ports/trash"
+	"melodiapp/shared"
+)
+
+type TrashHandlers struct {
+	service trashports.TrashService
+}
+
+func NewTrashHandlers(s trashports.TrashService) *TrashHandlers {
+	return &TrashHandlers{service: s}
+}
+
+func parseID(c *gin.Context, param string, resource string) (uint, bool) {
+	id64, err := strconv.ParseUint(c.Param(param), 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID(resource))
+		return 0, false
+	}
+	return uint(id64), true
+}
+
+// List muestra la papelera; ?resource=song|service|user filtra por tipo.
+func (h *TrashHandlers) List(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	items, err := h.service.List(c.Query("resource"))
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *TrashHandlers) Restore(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	id, ok := parseID(c, "id", "item")
+	if !ok {
+		return
+	}
+	resource := c.Param("resource")
+	if err := h.service.Restore(shared.AuditContext(c), resource, id); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"resource": resource, "id": id})
+}
+
+// Purge borra definitivamente un elemento de la papelera.
+func (h *TrashHandlers) Purge(c *gin.Context) {
+	if _, ok := shared.RequireAdmin(c); !ok {
+		return
+	}
+
+	id, ok := parseID(c, "id", "item")
+	if !ok {
+		return
+	}
+	resource := c.Param("resource")
+	if err := h.service.Purge(shared.AuditContext(c), resource, id); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusNoContent, gin.H{"resource": resource, "id": id})
+}
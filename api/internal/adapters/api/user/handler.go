@@ -1,15 +1,15 @@
 package userapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
 
-	"melodiapp/database"
 	userports "melodiapp/internal/ports/user"
 	"melodiapp/models"
 	"melodiapp/shared"
@@ -24,32 +24,14 @@ func NewUserHandlers(service userports.UserService) *UserHandlers {
 }
 
 func (h *UserHandlers) GetAllUsers(c *gin.Context) {
-	tokenStr := shared.GetTokenFromRequest(c)
-	token, err := jwt.ParseWithClaims(tokenStr, &shared.Payload{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("invalid token")
-		}
-		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
-	})
-
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
-	claims, _ := token.Claims.(*shared.Payload)
-	userData, exists := shared.Sessions[claims.Session]
-
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "You don't have permission"})
-		return
-	}
-
-	_ = userData // authentication already validated, we don't use userData directly here
-
 	users, err := h.service.GetAllUsers()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 
@@ -57,39 +39,9 @@ func (h *UserHandlers) GetAllUsers(c *gin.Context) {
 }
 
 func (h *UserHandlers) GetMe(c *gin.Context) {
-	tokenStr := shared.GetTokenFromRequest(c)
-	token, err := jwt.ParseWithClaims(tokenStr, &shared.Payload{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("invalid token")
-		}
-		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
-	})
-
+	user, err := shared.CurrentUser(c)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	}
-
-	claims, _ := token.Claims.(*shared.Payload)
-	userData, exists := shared.Sessions[claims.Session]
-
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "You don't have permission"})
-		return
-	}
-
-	if userData.ExpiryTime.Before(time.Now()) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "You don't have permission"})
-		return
-	}
-
-	user, err := h.service.GetUserByUintID(userData.Uid)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "You don't have permission"})
-		return
-	}
-	if user == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		shared.Fail(c, err)
 		return
 	}
 
@@ -97,46 +49,19 @@ func (h *UserHandlers) GetMe(c *gin.Context) {
 }
 
 func (h *UserHandlers) GetUserById(c *gin.Context) {
-	tokenStr := shared.GetTokenFromRequest(c)
-	token, err := jwt.ParseWithClaims(tokenStr, &shared.Payload{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("invalid token")
-		}
-		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
-	})
-
-	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		return
-	}
-
-	claims, _ := token.Claims.(*shared.Payload)
-	userData, exists := shared.Sessions[claims.Session]
-
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "You don't have permission"})
-		return
-	}
-
-	if userData.ExpiryTime.Before(time.Now()) {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "You don't have permission"})
-		return
-	}
-
-	// ensure the session user still exists
-	if _, err := h.service.GetUserByUintID(userData.Uid); err != nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "You don't have permission"})
+	if _, err := shared.CurrentUser(c); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
 	id := c.Param("id")
 	user, err := h.service.GetUserByID(id)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 	if user == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		shared.Fail(c, models.ErrUserNotFound)
 		return
 	}
 
@@ -154,7 +79,7 @@ func (h *UserHandlers) CreateUser(c *gin.Context) {
 	password := c.PostForm("password")
 
 	if username == "" || email == "" || password == "" || role == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "username, email, password y role son obligatorios"})
+		shared.Fail(c, models.ErrIncompleteFields)
 		return
 	}
 
@@ -180,99 +105,64 @@ func (h *UserHandlers) CreateUser(c *gin.Context) {
 		filepath := fmt.Sprintf("%s/%s", uploadDir, filename)
 
 		if err := c.SaveUploadedFile(file, filepath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save image"})
+			shared.Fail(c, err)
 			return
 		}
 
 		user.ProfilePictureUrl = fmt.Sprintf("/files/profiles/%s", filename)
 	}
 
-	created, err := h.service.CreateUser(&user)
+	created, err := h.service.CreateUser(shared.AuditContext(c), &user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
 
 	c.JSON(http.StatusCreated, created)
 }
 
+// EditUser es el PUT con formulario (multipart/form-data) de la app. Los campos
+// vacíos no se tocan; el resto pasa por las mismas reglas que PatchUser.
 func (h *UserHandlers) EditUser(c *gin.Context) {
-	// 1. VALIDACIÓN DE TOKEN (Tu lógica actual)
-	tokenStr := shared.GetTokenFromRequest(c)
-	token, err := jwt.ParseWithClaims(tokenStr, &shared.Payload{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("invalid token")
-		}
-		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
-	})
-
+	// 1. VALIDACIÓN DE SESIÓN Y PERMISOS
+	current, err := shared.CurrentUser(c)
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		shared.Fail(c, err)
 		return
 	}
-
-	claims, _ := token.Claims.(*shared.Payload)
-	_, exists := shared.Sessions[claims.Session]
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "You don't have permission"})
-		return
-	}
-
-	// 2. BUSCAR EL USUARIO A EDITAR
 	id := c.Param("id")
-	var user models.User
-	tx := database.DBConn.First(&user, id)
-	if tx.RowsAffected == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	isAdmin := current.Role == "admin"
+	if !isAdmin && id != strconv.FormatUint(uint64(current.ID), 10) {
+		shared.Fail(c, models.ErrForbidden)
 		return
 	}
 
-	// 3. OBTENER DATOS DEL FORMULARIO (MULTIPART/FORM-DATA)
-	// Nota: Ya no usamos BindJSON porque vienen archivos
-
-	// --- Campos de Texto ---
-	username := c.PostForm("username")
-	lastname := c.PostForm("lastname")
-	email := c.PostForm("email")
-	celphone := c.PostForm("celphone")
-	role := c.PostForm("role")
-	secondaryRole := c.PostForm("secondary_role")
-	password := c.PostForm("password")
-
-	// Actualizar campos si vienen datos
-	if username != "" {
-		user.Username = username
-	}
-	if lastname != "" {
-		user.Lastname = lastname
-	}
-	if email != "" {
-		user.Email = email
-	}
-	if celphone != "" {
-		user.Celphone = celphone
-	}
-	if role != "" {
-		user.Role = role
+	// 2. ARMAR EL PATCH CON LOS CAMPOS DEL FORMULARIO
+	fields := make(map[string]any)
+	for _, name := range []string{"username", "lastname", "email", "celphone", "role", "secondary_role", "password"} {
+		if value := c.PostForm(name); value != "" {
+			fields[name] = value
+		}
 	}
-	if secondaryRole != "" {
-		user.SecondaryRole = secondaryRole
+	if maxServices := c.PostForm("max_services_per_month"); maxServices != "" {
+		value, err := strconv.Atoi(maxServices)
+		if err != nil || value < 0 {
+			shared.Fail(c, models.Validation(map[string]string{"max_services_per_month": "must be a non-negative integer"}))
+			return
+		}
+		fields["max_services_per_month"] = value
 	}
-
-	// --- Contraseña (Solo si se envía) ---
-	// Asignamos la nueva contraseña en texto plano y dejamos que los hooks de GORM
-	// (BeforeUpdate) se encarguen de hashearla si realmente cambió.
-	if password != "" {
-		user.Password = password
+	patch, err := json.Marshal(fields)
+	if err != nil {
+		shared.Fail(c, err)
+		return
 	}
 
-	// --- 4. MANEJO DEL ARCHIVO (FOTO) ---
+	// 3. MANEJO DEL ARCHIVO (FOTO)
 	// El frontend envía el archivo en el campo "file"
+	pictureURL := ""
 	file, err := c.FormFile("file")
 	if err == nil {
-		// Si hay archivo, lo guardamos
-
-		// Asegurar que el directorio existe
 		uploadDir := "public/profiles"
 		if _, err := os.Stat(uploadDir); os.IsNotExist(err) {
 			os.MkdirAll(uploadDir, 0755)
@@ -282,20 +172,19 @@ func (h *UserHandlers) EditUser(c *gin.Context) {
 		filename := fmt.Sprintf("profile_%s_%s", id, file.Filename)
 		filepath := fmt.Sprintf("%s/%s", uploadDir, filename)
 
-		// Guardar en disco
 		if err := c.SaveUploadedFile(file, filepath); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save image"})
+			shared.Fail(c, err)
 			return
 		}
 
-		// Actualizar la URL en la base de datos
-		// La ruta web será /files/profiles/... (dependiendo de cómo sirvas los estáticos)
-		user.ProfilePictureUrl = fmt.Sprintf("/files/profiles/%s", filename)
+		// La ruta web será /files/profiles/...
+		pictureURL = fmt.Sprintf("/files/profiles/%s", filename)
 	}
 
-	// 5. GUARDAR CAMBIOS EN BD
-	if err := database.DBConn.Save(&user).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update user"})
+	// 4. GUARDAR CAMBIOS
+	user, err := h.service.EditUser(shared.AuditContext(c), id, patch, pictureURL, isAdmin)
+	if err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
@@ -305,10 +194,38 @@ func (h *UserHandlers) EditUser(c *gin.Context) {
 	})
 }
 
+// PatchUser cambia solo los campos que vienen en el cuerpo (JSON Merge Patch).
+// Cada usuario puede editar sus datos; rol y límite de servicios solo un admin.
+func (h *UserHandlers) PatchUser(c *gin.Context) {
+	current, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	id := c.Param("id")
+	isAdmin := current.Role == "admin"
+	if !isAdmin && id != strconv.FormatUint(uint64(current.ID), 10) {
+		shared.Fail(c, models.ErrForbidden)
+		return
+	}
+
+	patch, ok := shared.MergePatchBody(c)
+	if !ok {
+		return
+	}
+
+	user, err := h.service.PatchUser(shared.AuditContext(c), id, patch, isAdmin)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, user)
+}
+
 func (h *UserHandlers) DeleteUser(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.service.DeleteUser(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.DeleteUser(shared.AuditContext(c), id); err != nil {
+		shared.Fail(c, err)
 		return
 	}
 
@@ -0,0 +1,111 @@
+package userblackoutapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	userblackoutports "melodiapp/internal/ports/userblackout"
+	"melodiapp/models"
+	"melodiapp/shared"
+)
+
+type UserBlackoutHandlers struct {
+	service userblackoutports.UserBlackoutService
+}
+
+type createBlackoutRequest struct {
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	Reason    string    `json:"reason"`
+}
+
+func NewUserBlackoutHandlers(s userblackoutports.UserBlackoutService) *UserBlackoutHandlers {
+	return &UserBlackoutHandlers{service: s}
+}
+
+// authorizeOwner valida que quien llama sea el dueño de las fechas o un admin.
+func authorizeOwner(c *gin.Context) (uint, bool) {
+	user, err := shared.CurrentUser(c)
+	if err != nil {
+		shared.Fail(c, err)
+		return 0, false
+	}
+
+	userID64, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("user"))
+		return 0, false
+	}
+
+	if uint(userID64) != user.ID && user.Role != "admin" {
+		shared.Fail(c, models.ErrForbidden)
+		return 0, false
+	}
+
+	return uint(userID64), true
+}
+
+func (h *UserBlackoutHandlers) ListByUser(c *gin.Context) {
+	userID, ok := authorizeOwner(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.service.ListByUser(userID)
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *UserBlackoutHandlers) Create(c *gin.Context) {
+	userID, ok := authorizeOwner(c)
+	if !ok {
+		return
+	}
+
+	var req createBlackoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		shared.Fail(c, shared.ErrInvalidData)
+		return
+	}
+
+	created, err := h.service.Create(&models.UserBlackout{
+		UserID:    userID,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+func (h *UserBlackoutHandlers) Delete(c *gin.Context) {
+	userID, ok := authorizeOwner(c)
+	if !ok {
+		return
+	}
+
+	blackoutIDParam := c.Param("blackoutId")
+	blackoutID64, err := strconv.ParseUint(blackoutIDParam, 10, 64)
+	if err != nil {
+		shared.Fail(c, shared.InvalidID("blackout"))
+		return
+	}
+
+	if err := h.service.Delete(userID, uint(blackoutID64)); err != nil {
+		shared.Fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusNoContent, gin.H{"id": blackoutIDParam})
+}
@@ -0,0 +1,44 @@
+package databaseadapter
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"melodiapp/models"
+)
+
+type GormAttendanceRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAttendanceRepository(db *gorm.DB) *GormAttendanceRepository {
+	return &GormAttendanceRepository{db: db}
+}
+
+func (r *GormAttendanceRepository) Get(eventType string, eventID uint, userID uint) (*models.Attendance, error) {
+	var record models.Attendance
+	result := r.db.Where("event_type = ? AND event_id = ? AND user_id = ?", eventType, eventID, userID).First(&record)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &record, result.Error
+}
+
+func (r *GormAttendanceRepository) Save(record *models.Attendance) error {
+	return r.db.Save(record).Error
+}
+
+func (r *GormAttendanceRepository) ListByEvent(eventType string, eventID uint) ([]models.Attendance, error) {
+	var list []models.Attendance
+	result := r.db.Where("event_type = ? AND event_id = ?", eventType, eventID).Order("user_id").Find(&list)
+	return list, result.Error
+}
+
+func (r *GormAttendanceRepository) ListByUsers(userIDs []uint) ([]models.Attendance, error) {
+	var list []models.Attendance
+	if len(userIDs) == 0 {
+		return list, nil
+	}
+	result := r.db.Where("user_id IN ?", userIDs).Find(&list)
+	return list, result.Error
+}
@@ -0,0 +1,55 @@
+package databaseadapter
+
+import (
+	"gorm.io/gorm"
+	"melodiapp/models"
+)
+
+type GormAuditRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAuditRepository(db *gorm.DB) *GormAuditRepository {
+	return &GormAuditRepository{db: db}
+}
+
+func (r *GormAuditRepository) Create(entry *models.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+func (r *GormAuditRepository) List(filter models.AuditFilter) ([]models.AuditLog, int64, error) {
+	query := r.db.Model(&models.AuditLog{})
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.ServiceID != 0 {
+		query = query.Where("service_id = ?", filter.ServiceID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	logs := []models.AuditLog{}
+	result := query.Order("created_at DESC, id DESC").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&logs)
+	return logs, total, result.Error
+}
@@ -0,0 +1,37 @@
+package databaseadapter
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"melodiapp/models"
+)
+
+type GormCalendarTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewGormCalendarTokenRepository(db *gorm.DB) *GormCalendarTokenRepository {
+	return &GormCalendarTokenRepository{db: db}
+}
+
+func (r *GormCalendarTokenRepository) GetByHash(tokenHash string) (*models.CalendarToken, error) {
+	var token models.CalendarToken
+	result := r.db.Where("token_hash = ?", tokenHash).First(&token)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &token, result.Error
+}
+
+func (r *GormCalendarTokenRepository) Save(token *models.CalendarToken) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_hash", "created_at"}),
+	}).Create(token).Error
+}
+
+func (r *GormCalendarTokenRepository) Delete(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&models.CalendarToken{}).Error
+}
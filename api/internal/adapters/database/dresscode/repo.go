@@ -0,0 +1,36 @@
+package databaseadapter
+
+import (
+	"gorm.io/gorm"
+	"melodiapp/models"
+)
+
+type GormDressCodeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormDressCodeRepository(db *gorm.DB) *GormDressCodeRepository {
+	return &GormDressCodeRepository{db: db}
+}
+
+func (r *GormDressCodeRepository) ListByService(serviceID uint) ([]models.ServiceDressCode, error) {
+	var list []models.ServiceDressCode
+	result := r.db.Where("service_id = ?", serviceID).Order("id").Find(&list)
+	return list, result.Error
+}
+
+func (r *GormDressCodeRepository) ReplaceForService(serviceID uint, codes []models.ServiceDressCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("service_id = ?", serviceID).Delete(&models.ServiceDressCode{}).Error; err != nil {
+			return err
+		}
+		for i := range codes {
+			codes[i].ID = 0
+			codes[i].ServiceID = serviceID
+			if err := tx.Create(&codes[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,38 @@
+package databaseadapter
+
+import (
+	"gorm.io/gorm"
+	"melodiapp/models"
+)
+
+// GormImpactRepository solo consulta tablas y columnas de models.References
+// y models.ResourceTables; nunca arma SQL con datos de la petición.
+type GormImpactRepository struct {
+	db *gorm.DB
+}
+
+func NewGormImpactRepository(db *gorm.DB) *GormImpactRepository {
+	return &GormImpactRepository{db: db}
+}
+
+func (r *GormImpactRepository) Exists(resource string, id uint) (bool, error) {
+	table, ok := models.ResourceTables[resource]
+	if !ok {
+		return false, models.ErrInvalidResource
+	}
+	var count int64
+	result := r.db.Table(table).Where("id = ?", id).Count(&count)
+	return count > 0, result.Error
+}
+
+func (r *GormImpactRepository) Count(ref models.Reference, id uint) (int64, error) {
+	var count int64
+	result := r.db.Table(ref.Table).Where(ref.Column+" = ?", id).Count(&count)
+	return count, result.Error
+}
+
+func (r *GormImpactRepository) ServiceIDs(ref models.Reference, id uint) ([]uint, error) {
+	var ids []uint
+	result := r.db.Table(ref.Table).Where(ref.Column+" = ?", id).Distinct().Pluck("service_id", &ids)
+	return ids, result.Error
+}
@@ -0,0 +1,188 @@
+package databaseadapter
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"melodiapp/models"
+)
+
+type GormJobRepository struct {
+	db *gorm.DB
+}
+
+func NewGormJobRepository(db *gorm.DB) *GormJobRepository {
+	return &GormJobRepository{db: db}
+}
+
+func (r *GormJobRepository) Create(job *models.Job) error {
+	return r.db.Create(job).Error
+}
+
+func (r *GormJobRepository) GetByID(id uint) (*models.Job, error) {
+	var job models.Job
+	result := r.db.First(&job, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &job, nil
+}
+
+func (r *GormJobRepository) List(filter models.JobFilter) ([]models.Job, error) {
+	var jobs []models.Job
+	query := r.db.Order("id DESC").Limit(filter.Limit)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
+	}
+	if err := query.Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *GormJobRepository) CountByStatus() (map[string]int64, error) {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	if err := r.db.Model(&models.Job{}).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *GormJobRepository) Claim(worker string, now time.Time) (*models.Job, error) {
+	var claimed *models.Job
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var job models.Job
+		result := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND run_at <= ?", models.JobQueued, now).
+			Order("run_at, id").
+			Limit(1).
+			Find(&job)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		job.Status = models.JobRunning
+		job.Attempts++
+		job.LockedBy = worker
+		job.LockedAt = &now
+		if err := tx.Model(&job).Updates(map[string]interface{}{
+			"status":    job.Status,
+			"attempts":  job.Attempts,
+			"locked_by": job.LockedBy,
+			"locked_at": job.LockedAt,
+		}).Error; err != nil {
+			return err
+		}
+		claimed = &job
+		return nil
+	})
+	return claimed, err
+}
+
+func (r *GormJobRepository) Complete(id uint, worker string, now time.Time) error {
+	return r.release(id, worker, map[string]interface{}{
+		"status":      models.JobSucceeded,
+		"finished_at": now,
+		"last_error":  "",
+		"locked_by":   "",
+		"locked_at":   nil,
+	})
+}
+
+func (r *GormJobRepository) Fail(id uint, worker string, message string, retryAt *time.Time) error {
+	updates := map[string]interface{}{
+		"last_error": message,
+		"locked_by":  "",
+		"locked_at":  nil,
+	}
+	if retryAt != nil {
+		updates["status"] = models.JobQueued
+		updates["run_at"] = *retryAt
+	} else {
+		updates["status"] = models.JobDead
+		updates["finished_at"] = time.Now()
+	}
+	return r.release(id, worker, updates)
+}
+
+// release actualiza el trabajo solo si sigue tomado por worker; si el reaper ya
+// se lo dio a otro devuelve ErrJobLockLost.
+func (r *GormJobRepository) release(id uint, worker string, updates map[string]interface{}) error {
+	result := r.db.Model(&models.Job{}).
+		Where("id = ? AND status = ? AND locked_by = ?", id, models.JobRunning, worker).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrJobLockLost
+	}
+	return nil
+}
+
+func (r *GormJobRepository) Requeue(staleBefore time.Time, now time.Time) (requeued int64, dead int64, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		stale := tx.Model(&models.Job{}).
+			Where("status = ? AND locked_at < ?", models.JobRunning, staleBefore).
+			Session(&gorm.Session{})
+
+		result := stale.
+			Where("attempts >= max_attempts").
+			Updates(map[string]interface{}{
+				"status":      models.JobDead,
+				"last_error":  "Worker stopped before finishing the last attempt",
+				"finished_at": now,
+				"locked_by":   "",
+				"locked_at":   nil,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		dead = result.RowsAffected
+
+		result = stale.Updates(map[string]interface{}{
+			"status":    models.JobQueued,
+			"locked_by": "",
+			"locked_at": nil,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		requeued = result.RowsAffected
+		return nil
+	})
+	return requeued, dead, err
+}
+
+func (r *GormJobRepository) Retry(id uint, now time.Time) (bool, error) {
+	result := r.db.Model(&models.Job{}).
+		Where("id = ? AND status IN ?", id, []string{models.JobQueued, models.JobDead}).
+		Updates(map[string]interface{}{
+			"status":      models.JobQueued,
+			"run_at":      now,
+			"attempts":    0,
+			"finished_at": nil,
+		})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,72 @@
+package databaseadapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+// liveChannel es el canal de LISTEN/NOTIFY de los cambios en vivo.
+const liveChannel = "live_state"
+
+// PgLiveBus reparte los cambios en vivo entre las instancias de la API con
+// LISTEN/NOTIFY de Postgres. Cada aviso lleva el id de la instancia que lo
+// mandó, que ya avisó a sus clientes y lo ignora.
+type PgLiveBus struct {
+	db     *gorm.DB
+	origin string
+}
+
+func NewPgLiveBus(db *gorm.DB) *PgLiveBus {
+	return &PgLiveBus{db: db, origin: uuid.Must(uuid.NewV4()).String()}
+}
+
+func (b *PgLiveBus) Publish(serviceID uint) error {
+	payload := fmt.Sprintf("%s:%d", b.origin, serviceID)
+	return b.db.Exec("SELECT pg_notify(?, ?)", liveChannel, payload).Error
+}
+
+// Listen reserva una conexión del pool para el LISTEN y espera avisos hasta
+// que ctx termine o se corte la conexión.
+func (b *PgLiveBus) Listen(ctx context.Context, changed func(serviceID uint)) error {
+	sqlDB, err := b.db.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn any) error {
+		pgConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errors.New("live bus needs a pgx connection")
+		}
+		if _, err := pgConn.Conn().Exec(ctx, "LISTEN "+liveChannel); err != nil {
+			return err
+		}
+		for {
+			notification, err := pgConn.Conn().WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			origin, id, ok := strings.Cut(notification.Payload, ":")
+			if !ok || origin == b.origin {
+				continue
+			}
+			serviceID, err := strconv.ParseUint(id, 10, 64)
+			if err != nil {
+				continue
+			}
+			changed(uint(serviceID))
+		}
+	})
+}
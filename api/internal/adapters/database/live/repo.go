@@ -0,0 +1,48 @@
+package databaseadapter
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"melodiapp/models"
+)
+
+type GormLiveSessionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormLiveSessionRepository(db *gorm.DB) *GormLiveSessionRepository {
+	return &GormLiveSessionRepository{db: db}
+}
+
+func (r *GormLiveSessionRepository) GetByService(serviceID uint) (*models.LiveSession, error) {
+	var session models.LiveSession
+	result := r.db.Where("service_id = ?", serviceID).First(&session)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &session, result.Error
+}
+
+func (r *GormLiveSessionRepository) Save(session *models.LiveSession) error {
+	return r.db.Save(session).Error
+}
+
+// Update sube la versión y guarda solo si nadie cambió la sesión desde que se
+// leyó; si otra instancia se adelantó devuelve ErrLiveSessionModified.
+func (r *GormLiveSessionRepository) Update(session *models.LiveSession) error {
+	read := session.Version
+	session.Version = read + 1
+	result := r.db.Model(session).
+		Where("version = ?", read).
+		Select("*").
+		Omit("service_id").
+		Updates(session)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = models.ErrLiveSessionModified
+	}
+	if result.Error != nil {
+		session.Version = read
+	}
+	return result.Error
+}
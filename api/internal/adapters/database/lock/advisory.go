@@ -0,0 +1,47 @@
+package databaseadapter
+
+import (
+	"context"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// AdvisoryLocker usa pg_try_advisory_lock. Los locks de sesión de Postgres
+// pertenecen a una conexión, así que se reserva una del pool mientras dura.
+type AdvisoryLocker struct {
+	db *gorm.DB
+}
+
+func NewAdvisoryLocker(db *gorm.DB) *AdvisoryLocker {
+	return &AdvisoryLocker{db: db}
+}
+
+func (l *AdvisoryLocker) TryLock(ctx context.Context, key int64) (func(), bool, error) {
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	release := func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key); err != nil {
+			log.Printf("[AdvisoryLocker] Error releasing lock %d: %v", key, err)
+		}
+		conn.Close()
+	}
+	return release, true, nil
+}
@@ -0,0 +1,73 @@
+package databaseadapter
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"melodiapp/models"
+)
+
+type GormNotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewGormNotificationRepository(db *gorm.DB) *GormNotificationRepository {
+	return &GormNotificationRepository{db: db}
+}
+
+func (r *GormNotificationRepository) Create(notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return r.db.Create(&notifications).Error
+}
+
+func (r *GormNotificationRepository) ListByUser(userID uint, unreadOnly bool, limit int) ([]models.Notification, error) {
+	var list []models.Notification
+	query := r.db.Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	result := query.Order("created_at DESC, id DESC").Find(&list)
+	return list, result.Error
+}
+
+func (r *GormNotificationRepository) CountUnread(userID uint) (int64, error) {
+	var count int64
+	result := r.db.Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).Count(&count)
+	return count, result.Error
+}
+
+func (r *GormNotificationRepository) MarkRead(userID uint, ids []uint) error {
+	query := r.db.Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	return query.Update("read_at", time.Now()).Error
+}
+
+func (r *GormNotificationRepository) ListPreferences(userIDs []uint) ([]models.NotificationPreference, error) {
+	var list []models.NotificationPreference
+	if len(userIDs) == 0 {
+		return list, nil
+	}
+	result := r.db.Where("user_id IN ?", userIDs).Find(&list)
+	return list, result.Error
+}
+
+func (r *GormNotificationRepository) SavePreferences(userID uint, prefs []models.NotificationPreference) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range prefs {
+			prefs[i].UserID = userID
+			if err := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&prefs[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,64 @@
+package databaseadapter
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"melodiapp/models"
+)
+
+type GormOutfitRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOutfitRepository(db *gorm.DB) *GormOutfitRepository {
+	return &GormOutfitRepository{db: db}
+}
+
+func (r *GormOutfitRepository) GetAll() ([]models.Outfit, error) {
+	var outfits []models.Outfit
+	result := r.db.Order("name").Find(&outfits)
+	return outfits, result.Error
+}
+
+func (r *GormOutfitRepository) GetByID(id uint) (*models.Outfit, error) {
+	var outfit models.Outfit
+	result := r.db.First(&outfit, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &outfit, result.Error
+}
+
+func (r *GormOutfitRepository) GetByIDs(ids []uint) ([]models.Outfit, error) {
+	var outfits []models.Outfit
+	if len(ids) == 0 {
+		return outfits, nil
+	}
+	result := r.db.Where("id IN ?", ids).Find(&outfits)
+	return outfits, result.Error
+}
+
+func (r *GormOutfitRepository) Create(outfit *models.Outfit) error {
+	return r.db.Create(outfit).Error
+}
+
+func (r *GormOutfitRepository) Update(outfit *models.Outfit) error {
+	return r.db.Save(outfit).Error
+}
+
+func (r *GormOutfitRepository) DeleteByID(id uint) error {
+	return r.db.Delete(&models.Outfit{}, id).Error
+}
+
+// CountServices cuenta los servicios y códigos de vestimenta que usan el outfit.
+func (r *GormOutfitRepository) CountServices(id uint) (int64, error) {
+	var services, dressCodes int64
+	if err := r.db.Model(&models.ServiceOutfit{}).Where("outfit_id = ?", id).Count(&services).Error; err != nil {
+		return 0, err
+	}
+	if err := r.db.Model(&models.ServiceDressCode{}).Where("outfit_id = ?", id).Count(&dressCodes).Error; err != nil {
+		return 0, err
+	}
+	return services + dressCodes, nil
+}
@@ -0,0 +1,105 @@
+package databaseadapter
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"melodiapp/models"
+)
+
+type GormPositionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormPositionRepository(db *gorm.DB) *GormPositionRepository {
+	return &GormPositionRepository{db: db}
+}
+
+func (r *GormPositionRepository) GetAll() ([]models.Position, error) {
+	var positions []models.Position
+	result := r.db.Order("name").Find(&positions)
+	return positions, result.Error
+}
+
+func (r *GormPositionRepository) GetByID(id uint) (*models.Position, error) {
+	var position models.Position
+	result := r.db.First(&position, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &position, result.Error
+}
+
+func (r *GormPositionRepository) GetByIDs(ids []uint) ([]models.Position, error) {
+	var positions []models.Position
+	if len(ids) == 0 {
+		return positions, nil
+	}
+	result := r.db.Where("id IN ?", ids).Find(&positions)
+	return positions, result.Error
+}
+
+func (r *GormPositionRepository) Create(position *models.Position) error {
+	return r.db.Create(position).Error
+}
+
+func (r *GormPositionRepository) Update(position *models.Position) error {
+	return r.db.Save(position).Error
+}
+
+// DeleteByID borra el puesto. Las asignaciones y los intercambios que lo
+// usaban quedan sin puesto.
+func (r *GormPositionRepository) DeleteByID(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ServiceUser{}).Where("position_id = ?", id).Update("position_id", 0).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SwapRequest{}).Where("position_id = ?", id).Update("position_id", 0).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Position{}, id).Error
+	})
+}
+
+// CountUsage cuenta los cupos y códigos de vestimenta que usan el puesto.
+func (r *GormPositionRepository) CountUsage(id uint) (int64, error) {
+	var slots, dressCodes int64
+	if err := r.db.Model(&models.ServicePosition{}).Where("position_id = ?", id).Count(&slots).Error; err != nil {
+		return 0, err
+	}
+	if err := r.db.Model(&models.ServiceDressCode{}).Where("position_id = ?", id).Count(&dressCodes).Error; err != nil {
+		return 0, err
+	}
+	return slots + dressCodes, nil
+}
+
+func (r *GormPositionRepository) ListSlotsByService(serviceID uint) ([]models.ServicePosition, error) {
+	var slots []models.ServicePosition
+	result := r.db.Where("service_id = ?", serviceID).Order("position_id").Find(&slots)
+	return slots, result.Error
+}
+
+func (r *GormPositionRepository) ListSlotsByServices(serviceIDs []uint) ([]models.ServicePosition, error) {
+	var slots []models.ServicePosition
+	if len(serviceIDs) == 0 {
+		return slots, nil
+	}
+	result := r.db.Where("service_id IN ?", serviceIDs).Order("service_id, position_id").Find(&slots)
+	return slots, result.Error
+}
+
+// ReplaceSlots reemplaza todos los cupos del servicio en una sola transacción.
+func (r *GormPositionRepository) ReplaceSlots(serviceID uint, slots []models.ServicePosition) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("service_id = ?", serviceID).Delete(&models.ServicePosition{}).Error; err != nil {
+			return err
+		}
+		for i := range slots {
+			slots[i].ServiceID = serviceID
+			if err := tx.Create(&slots[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,128 @@
+package databaseadapter
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"melodiapp/models"
+)
+
+type GormRehearsalRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRehearsalRepository(db *gorm.DB) *GormRehearsalRepository {
+	return &GormRehearsalRepository{db: db}
+}
+
+// List devuelve los ensayos del rango; si from o to son cero, ese extremo queda abierto.
+func (r *GormRehearsalRepository) List(from, to time.Time) ([]models.Rehearsal, error) {
+	var list []models.Rehearsal
+	query := r.db.Preload("Services").Preload("Attendees")
+	if !from.IsZero() {
+		query = query.Where("end_time > ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("start_time < ?", to)
+	}
+	result := query.Order("start_time").Find(&list)
+	return list, result.Error
+}
+
+func (r *GormRehearsalRepository) ListByUser(userID uint) ([]models.Rehearsal, error) {
+	var list []models.Rehearsal
+	result := r.db.Preload("Services").Preload("Attendees").
+		Where("id IN (?)", r.db.Model(&models.RehearsalAttendee{}).Select("rehearsal_id").Where("user_id = ?", userID)).
+		Order("start_time").Find(&list)
+	return list, result.Error
+}
+
+func (r *GormRehearsalRepository) GetByID(id uint) (*models.Rehearsal, error) {
+	var rehearsal models.Rehearsal
+	result := r.db.Preload("Services").Preload("Attendees").First(&rehearsal, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &rehearsal, result.Error
+}
+
+func (r *GormRehearsalRepository) Create(rehearsal *models.Rehearsal, serviceIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		rehearsal.Services = nil
+		rehearsal.Attendees = nil
+		if err := tx.Create(rehearsal).Error; err != nil {
+			return err
+		}
+		return replaceServices(tx, rehearsal.ID, serviceIDs)
+	})
+}
+
+func (r *GormRehearsalRepository) Update(rehearsal *models.Rehearsal, serviceIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		rehearsal.Services = nil
+		rehearsal.Attendees = nil
+		if err := tx.Save(rehearsal).Error; err != nil {
+			return err
+		}
+		return replaceServices(tx, rehearsal.ID, serviceIDs)
+	})
+}
+
+func replaceServices(tx *gorm.DB, rehearsalID uint, serviceIDs []uint) error {
+	if err := tx.Where("rehearsal_id = ?", rehearsalID).Delete(&models.RehearsalService{}).Error; err != nil {
+		return err
+	}
+	for _, sid := range serviceIDs {
+		link := models.RehearsalService{RehearsalID: rehearsalID, ServiceID: sid}
+		if err := tx.Create(&link).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *GormRehearsalRepository) DeleteByID(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rehearsal_id = ?", id).Delete(&models.RehearsalAttendee{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("rehearsal_id = ?", id).Delete(&models.RehearsalService{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Rehearsal{}, id).Error
+	})
+}
+
+func (r *GormRehearsalRepository) SyncAttendees(rehearsalID uint, userIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		remove := tx.Where("rehearsal_id = ?", rehearsalID)
+		if len(userIDs) > 0 {
+			remove = remove.Where("user_id NOT IN ?", userIDs)
+		}
+		if err := remove.Delete(&models.RehearsalAttendee{}).Error; err != nil {
+			return err
+		}
+		for _, uid := range userIDs {
+			attendee := models.RehearsalAttendee{RehearsalID: rehearsalID, UserID: uid, RSVP: models.StatusPending}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&attendee).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *GormRehearsalRepository) GetAttendee(rehearsalID uint, userID uint) (*models.RehearsalAttendee, error) {
+	var attendee models.RehearsalAttendee
+	result := r.db.Where("rehearsal_id = ? AND user_id = ?", rehearsalID, userID).First(&attendee)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &attendee, result.Error
+}
+
+func (r *GormRehearsalRepository) UpdateAttendee(attendee *models.RehearsalAttendee) error {
+	return r.db.Save(attendee).Error
+}
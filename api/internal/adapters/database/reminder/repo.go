@@ -0,0 +1,20 @@
+package databaseadapter
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"melodiapp/models"
+)
+
+type GormReminderLogRepository struct {
+	db *gorm.DB
+}
+
+func NewGormReminderLogRepository(db *gorm.DB) *GormReminderLogRepository {
+	return &GormReminderLogRepository{db: db}
+}
+
+func (r *GormReminderLogRepository) Record(entry *models.ReminderLog) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+	return result.RowsAffected == 1, result.Error
+}
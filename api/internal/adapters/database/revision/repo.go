@@ -0,0 +1,96 @@
+package databaseadapter
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"melodiapp/models"
+)
+
+type GormRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRevisionRepository(db *gorm.DB) *GormRevisionRepository {
+	return &GormRevisionRepository{db: db}
+}
+
+func (r *GormRevisionRepository) Create(revision *models.Revision) error {
+	return r.db.Create(revision).Error
+}
+
+// CreateNext guarda la revisión con la versión siguiente a la última de la
+// entidad. Un advisory lock por entidad ordena los guardados concurrentes, así
+// ninguno choca con el índice único ni se pierde. Si la última revisión ya
+// tiene la misma foto (comparada como jsonb) no guarda nada y devuelve false.
+func (r *GormRevisionRepository) CreateNext(revision *models.Revision) (bool, error) {
+	created := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?), ?)", revision.EntityType, int32(revision.EntityID)).Error; err != nil {
+			return err
+		}
+
+		var latest models.Revision
+		result := tx.Where("entity_type = ? AND entity_id = ?", revision.EntityType, revision.EntityID).
+			Order("version DESC").
+			Limit(1).
+			Find(&latest)
+		if result.Error != nil {
+			return result.Error
+		}
+		revision.Version = 1
+		if result.RowsAffected > 0 {
+			var same bool
+			if err := tx.Raw("SELECT ?::jsonb = ?::jsonb", string(latest.Snapshot), string(revision.Snapshot)).Scan(&same).Error; err != nil {
+				return err
+			}
+			if same {
+				return nil
+			}
+			revision.Version = latest.Version + 1
+		}
+
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+		created = true
+		return nil
+	})
+	return created, err
+}
+
+func (r *GormRevisionRepository) Latest(entityType string, entityID uint) (*models.Revision, error) {
+	var revision models.Revision
+	err := r.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("version DESC").
+		First(&revision).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+func (r *GormRevisionRepository) GetByVersion(entityType string, entityID uint, version int) (*models.Revision, error) {
+	var revision models.Revision
+	err := r.db.Where("entity_type = ? AND entity_id = ? AND version = ?", entityType, entityID, version).
+		First(&revision).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// List devuelve las revisiones de la más nueva a la más vieja.
+func (r *GormRevisionRepository) List(entityType string, entityID uint) ([]models.Revision, error) {
+	revisions := []models.Revision{}
+	err := r.db.Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("version DESC").
+		Find(&revisions).Error
+	return revisions, err
+}
@@ -0,0 +1,85 @@
+package databaseadapter
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"melodiapp/models"
+)
+
+type GormRunSheetRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRunSheetRepository(db *gorm.DB) *GormRunSheetRepository {
+	return &GormRunSheetRepository{db: db}
+}
+
+func (r *GormRunSheetRepository) ListByService(serviceID uint) ([]models.RunSheetItem, error) {
+	var items []models.RunSheetItem
+	result := r.db.Where("service_id = ?", serviceID).Order("sort_order, id").Find(&items)
+	return items, result.Error
+}
+
+func (r *GormRunSheetRepository) GetItem(serviceID uint, itemID uint) (*models.RunSheetItem, error) {
+	var item models.RunSheetItem
+	result := r.db.Where("service_id = ? AND id = ?", serviceID, itemID).First(&item)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &item, result.Error
+}
+
+// CreateItem agrega el ítem al final del orden.
+func (r *GormRunSheetRepository) CreateItem(item *models.RunSheetItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var last int
+		if err := tx.Model(&models.RunSheetItem{}).
+			Where("service_id = ?", item.ServiceID).
+			Select("COALESCE(MAX(sort_order), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+		item.SortOrder = last + 1
+		return tx.Create(item).Error
+	})
+}
+
+func (r *GormRunSheetRepository) UpdateItem(item *models.RunSheetItem) error {
+	return r.db.Save(item).Error
+}
+
+func (r *GormRunSheetRepository) DeleteItem(serviceID uint, itemID uint) error {
+	return r.db.Where("service_id = ? AND id = ?", serviceID, itemID).Delete(&models.RunSheetItem{}).Error
+}
+
+// Reorder asigna sort_order según la posición de cada id en itemIDs.
+func (r *GormRunSheetRepository) Reorder(serviceID uint, itemIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, id := range itemIDs {
+			if err := tx.Model(&models.RunSheetItem{}).
+				Where("service_id = ? AND id = ?", serviceID, id).
+				Update("sort_order", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *GormRunSheetRepository) ReplaceItems(serviceID uint, items []models.RunSheetItem) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("service_id = ?", serviceID).Delete(&models.RunSheetItem{}).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].ID = 0
+			items[i].ServiceID = serviceID
+			items[i].SortOrder = i + 1
+			if err := tx.Create(&items[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -4,39 +4,84 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
-	"melodiapp/database"
 	"melodiapp/models"
 )
 
-type GormServiceRepository struct{}
+type GormServiceRepository struct {
+	db *gorm.DB
+}
 
-func NewGormServiceRepository() *GormServiceRepository {
-	return &GormServiceRepository{}
+func NewGormServiceRepository(db *gorm.DB) *GormServiceRepository {
+	return &GormServiceRepository{db: db}
 }
 
 func (r *GormServiceRepository) GetAll() ([]models.Service, error) {
 	var services []models.Service
-	result := database.DBConn.Find(&services)
+	result := r.db.Find(&services)
 	return services, result.Error
 }
 
 func (r *GormServiceRepository) GetByID(id string) (*models.Service, error) {
 	var svc models.Service
-	result := database.DBConn.Where("id = ?", id).First(&svc)
+	result := r.db.Where("id = ?", id).First(&svc)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
 	return &svc, result.Error
 }
 
+func (r *GormServiceRepository) GetByIDs(ids []uint) ([]models.Service, error) {
+	var services []models.Service
+	if len(ids) == 0 {
+		return services, nil
+	}
+	result := r.db.Where("id IN ?", ids).Find(&services)
+	return services, result.Error
+}
+
 func (r *GormServiceRepository) Create(svc *models.Service) error {
-	return database.DBConn.Create(svc).Error
+	return r.db.Create(svc).Error
 }
 
+// Update guarda solo si el registro sigue en la versión que se leyó y sube la
+// versión en uno. Si otra edición se adelantó devuelve models.ErrVersionMismatch.
 func (r *GormServiceRepository) Update(svc *models.Service) error {
-	return database.DBConn.Save(svc).Error
+	read := svc.Version
+	svc.Version = read + 1
+	result := r.db.Model(svc).
+		Where("version = ?", read).
+		Select("*").
+		Omit("id", "created_at", "deleted_at").
+		Updates(svc)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = models.ErrVersionMismatch
+	}
+	if result.Error != nil {
+		svc.Version = read
+	}
+	return result.Error
 }
 
+// DeleteByID manda el servicio a la papelera.
 func (r *GormServiceRepository) DeleteByID(id string) error {
-	return database.DBConn.Delete(&models.Service{}, id).Error
+	return r.db.Delete(&models.Service{}, id).Error
+}
+
+func (r *GormServiceRepository) ListDeleted() ([]models.Service, error) {
+	var services []models.Service
+	result := r.db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&services)
+	return services, result.Error
+}
+
+// Restore saca el servicio de la papelera. Devuelve false si no estaba ahí.
+func (r *GormServiceRepository) Restore(id uint) (bool, error) {
+	result := r.db.Unscoped().Model(&models.Service{}).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Purge borra definitivamente un servicio de la papelera junto con todo lo
+// que cuelga de él (foreign keys en cascada).
+func (r *GormServiceRepository) Purge(id uint) (bool, error) {
+	result := r.db.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&models.Service{})
+	return result.RowsAffected > 0, result.Error
 }
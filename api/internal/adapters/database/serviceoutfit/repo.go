@@ -1,14 +1,17 @@
 package databaseadapter
 
 import (
-	"melodiapp/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"melodiapp/models"
 )
 
-type GormServiceOutfitRepository struct{}
+type GormServiceOutfitRepository struct {
+	db *gorm.DB
+}
 
-func NewGormServiceOutfitRepository() *GormServiceOutfitRepository {
-	return &GormServiceOutfitRepository{}
+func NewGormServiceOutfitRepository(db *gorm.DB) *GormServiceOutfitRepository {
+	return &GormServiceOutfitRepository{db: db}
 }
 
 func (r *GormServiceOutfitRepository) AddOutfits(serviceID uint, outfitIDs []uint) error {
@@ -17,20 +20,44 @@ func (r *GormServiceOutfitRepository) AddOutfits(serviceID uint, outfitIDs []uin
 			ServiceID: serviceID,
 			OutfitID:  oid,
 		}
-		if err := database.DBConn.Create(&so).Error; err != nil {
+		// Asignar un outfit que ya está vinculado no es un error
+		if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&so).Error; err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+// ReplaceOutfits deja al servicio exactamente con outfitIDs.
+func (r *GormServiceOutfitRepository) ReplaceOutfits(serviceID uint, outfitIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("service_id = ?", serviceID)
+		if len(outfitIDs) > 0 {
+			query = query.Where("outfit_id NOT IN ?", outfitIDs)
+		}
+		if err := query.Delete(&models.ServiceOutfit{}).Error; err != nil {
+			return err
+		}
+		return NewGormServiceOutfitRepository(tx).AddOutfits(serviceID, outfitIDs)
+	})
+}
+
 func (r *GormServiceOutfitRepository) ListByService(serviceID uint) ([]models.ServiceOutfit, error) {
 	var list []models.ServiceOutfit
-	result := database.DBConn.Where("service_id = ?", serviceID).Find(&list)
+	result := r.db.Preload("Outfit").Where("service_id = ?", serviceID).Find(&list)
+	return list, result.Error
+}
+
+func (r *GormServiceOutfitRepository) ListByServices(serviceIDs []uint) ([]models.ServiceOutfit, error) {
+	var list []models.ServiceOutfit
+	if len(serviceIDs) == 0 {
+		return list, nil
+	}
+	result := r.db.Preload("Outfit").Where("service_id IN ?", serviceIDs).Find(&list)
 	return list, result.Error
 }
 
 func (r *GormServiceOutfitRepository) Remove(serviceID uint, outfitID uint) error {
-	return database.DBConn.Where("service_id = ? AND outfit_id = ?", serviceID, outfitID).
+	return r.db.Where("service_id = ? AND outfit_id = ?", serviceID, outfitID).
 		Delete(&models.ServiceOutfit{}).Error
 }
@@ -3,25 +3,30 @@ package databaseadapter
 import (
 	"log"
 
-	"melodiapp/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"melodiapp/models"
 )
 
-type GormServiceSongRepository struct{}
+type GormServiceSongRepository struct {
+	db *gorm.DB
+}
 
-func NewGormServiceSongRepository() *GormServiceSongRepository {
-	return &GormServiceSongRepository{}
+func NewGormServiceSongRepository(db *gorm.DB) *GormServiceSongRepository {
+	return &GormServiceSongRepository{db: db}
 }
 
-func (r *GormServiceSongRepository) AddSongs(serviceID uint, songIDs []uint) error {
+// ReplaceSongs reemplaza completamente el repertorio del servicio.
+func (r *GormServiceSongRepository) ReplaceSongs(serviceID uint, songIDs []uint) error {
 	log.Printf("[ServiceSongRepository] Replacing songs for service %d with %+v", serviceID, songIDs)
 
 	// Reemplazar completamente el repertorio del servicio:
-	// 1) Borrar asociaciones anteriores
+	// 1) Borrar asociaciones anteriores (las de canciones en la papelera se conservan)
 	// 2) Crear solo las nuevas seleccionadas
-	tx := database.DBConn.Begin()
+	tx := r.db.Begin()
+	active := tx.Model(&models.Song{}).Select("id")
 
-	res := tx.Where("service_id = ?", serviceID).Delete(&models.ServiceSong{})
+	res := tx.Where("service_id = ? AND song_id IN (?)", serviceID, active).Delete(&models.ServiceSong{})
 	if res.Error != nil {
 		log.Printf("[ServiceSongRepository] Error deleting existing songs for service %d: %v", serviceID, res.Error)
 		tx.Rollback()
@@ -29,6 +34,17 @@ func (r *GormServiceSongRepository) AddSongs(serviceID uint, songIDs []uint) err
 	}
 	log.Printf("[ServiceSongRepository] Deleted %d existing service_songs rows for service %d", res.RowsAffected, serviceID)
 
+	// Los ítems del orden que apuntan a canciones que salen del repertorio se borran.
+	orphans := tx.Where("service_id = ? AND type = ? AND song_id IN (?)", serviceID, models.RunItemSong, active)
+	if len(songIDs) > 0 {
+		orphans = orphans.Where("song_id NOT IN ?", songIDs)
+	}
+	if err := orphans.Delete(&models.RunSheetItem{}).Error; err != nil {
+		log.Printf("[ServiceSongRepository] Error deleting run sheet items for service %d: %v", serviceID, err)
+		tx.Rollback()
+		return err
+	}
+
 	for _, sid := range songIDs {
 		ss := models.ServiceSong{
 			ServiceID: serviceID,
@@ -51,13 +67,56 @@ func (r *GormServiceSongRepository) AddSongs(serviceID uint, songIDs []uint) err
 	return nil
 }
 
+// ApplySongDiff agrega y quita canciones sin tocar el resto del repertorio.
+// Es idempotente: agregar una canción existente o quitar una ausente no falla.
+func (r *GormServiceSongRepository) ApplySongDiff(serviceID uint, add []uint, remove []uint) error {
+	log.Printf("[ServiceSongRepository] Updating songs for service %d: add=%+v remove=%+v", serviceID, add, remove)
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(remove) > 0 {
+			if err := tx.Where("service_id = ? AND song_id IN ?", serviceID, remove).
+				Delete(&models.ServiceSong{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("service_id = ? AND type = ? AND song_id IN ?", serviceID, models.RunItemSong, remove).
+				Delete(&models.RunSheetItem{}).Error; err != nil {
+				return err
+			}
+		}
+		for _, sid := range add {
+			ss := models.ServiceSong{
+				ServiceID: serviceID,
+				SongID:    sid,
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&ss).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListByService no trae las canciones que están en la papelera: sus filas se
+// conservan para devolverlas al repertorio si se restauran.
 func (r *GormServiceSongRepository) ListByService(serviceID uint) ([]models.ServiceSong, error) {
 	var list []models.ServiceSong
-	result := database.DBConn.Where("service_id = ?", serviceID).Find(&list)
+	result := r.db.Where("service_id = ?", serviceID).
+		Where("song_id IN (?)", r.db.Model(&models.Song{}).Select("id")).
+		Find(&list)
+	return list, result.Error
+}
+
+func (r *GormServiceSongRepository) ListByServices(serviceIDs []uint) ([]models.ServiceSong, error) {
+	var list []models.ServiceSong
+	if len(serviceIDs) == 0 {
+		return list, nil
+	}
+	result := r.db.Where("service_id IN ?", serviceIDs).
+		Where("song_id IN (?)", r.db.Model(&models.Song{}).Select("id")).
+		Find(&list)
 	return list, result.Error
 }
 
 func (r *GormServiceSongRepository) Remove(serviceID uint, songID uint) error {
-	return database.DBConn.Where("service_id = ? AND song_id = ?", serviceID, songID).
-		Delete(&models.ServiceSong{}).Error
+	return r.ApplySongDiff(serviceID, nil, []uint{songID})
 }
@@ -1,41 +1,69 @@
 package databaseadapter
 
 import (
+	"errors"
 	"log"
 
-	"melodiapp/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"melodiapp/models"
 )
 
-type GormServiceUserRepository struct{}
+type GormServiceUserRepository struct {
+	db *gorm.DB
+}
 
-func NewGormServiceUserRepository() *GormServiceUserRepository {
-	return &GormServiceUserRepository{}
+func NewGormServiceUserRepository(db *gorm.DB) *GormServiceUserRepository {
+	return &GormServiceUserRepository{db: db}
 }
 
-// AddUsers reemplaza completamente el equipo de un servicio:
-// 1) Elimina todas las filas existentes de service_users para ese serviceID
-// 2) Inserta únicamente los userIDs recibidos, con estado "pending" por defecto
-func (r *GormServiceUserRepository) AddUsers(serviceID uint, userIDs []uint) error {
-	log.Printf("[ServiceUserRepository] Replacing users for service %d with %+v", serviceID, userIDs)
+// ReplaceUsers reemplaza el equipo de un servicio conservando las respuestas:
+// 1) Elimina las filas de service_users de quienes ya no están en assignments,
+// salvo las de usuarios en la papelera
+// 2) Inserta con estado "pending" solo a los usuarios nuevos
+// 3) Guarda el puesto de cada asignación que lo trae
+func (r *GormServiceUserRepository) ReplaceUsers(serviceID uint, assignments []models.UserAssignment) error {
+	log.Printf("[ServiceUserRepository] Replacing users for service %d with %+v", serviceID, assignments)
+
+	userIDs := make([]uint, 0, len(assignments))
+	for _, a := range assignments {
+		userIDs = append(userIDs, a.UserID)
+	}
 
-	tx := database.DBConn.Begin()
+	tx := r.db.Begin()
 
-	// Borrar asociaciones anteriores
-	res := tx.Where("service_id = ?", serviceID).Delete(&models.ServiceUser{})
+	// Borrar a quienes salen del equipo
+	res := tx.Where("service_id = ? AND user_id IN (?)", serviceID, tx.Model(&models.User{}).Select("id"))
+	if len(userIDs) > 0 {
+		res = res.Where("user_id NOT IN ?", userIDs)
+	}
+	res = res.Delete(&models.ServiceUser{})
 	if res.Error != nil {
-		log.Printf("[ServiceUserRepository] Error deleting existing users for service %d: %v", serviceID, res.Error)
+		log.Printf("[ServiceUserRepository] Error deleting removed users for service %d: %v", serviceID, res.Error)
 		tx.Rollback()
 		return res.Error
 	}
-	log.Printf("[ServiceUserRepository] Deleted %d existing service_users rows for service %d", res.RowsAffected, serviceID)
+	log.Printf("[ServiceUserRepository] Deleted %d service_users rows for service %d", res.RowsAffected, serviceID)
 
-	// Crear nuevas asociaciones
+	var existing []models.ServiceUser
+	if err := tx.Where("service_id = ?", serviceID).Find(&existing).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	kept := make(map[uint]bool, len(existing))
+	for _, su := range existing {
+		kept[su.UserID] = true
+	}
+
+	// Crear solo las asociaciones nuevas
 	for _, uid := range userIDs {
+		if kept[uid] {
+			continue
+		}
 		su := models.ServiceUser{
 			ServiceID: serviceID,
 			UserID:    uid,
-			Status:    "pending",
+			Status:    models.StatusPending,
 		}
 		if err := tx.Create(&su).Error; err != nil {
 			log.Printf("[ServiceUserRepository] Error creating service_user (service=%d, user=%d): %v", serviceID, uid, err)
@@ -45,6 +73,12 @@ func (r *GormServiceUserRepository) AddUsers(serviceID uint, userIDs []uint) err
 		log.Printf("[ServiceUserRepository] Inserted service_user (service=%d, user=%d)", serviceID, uid)
 	}
 
+	if err := setPositions(tx, serviceID, assignments); err != nil {
+		log.Printf("[ServiceUserRepository] Error setting positions for service %d: %v", serviceID, err)
+		tx.Rollback()
+		return err
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		log.Printf("[ServiceUserRepository] Error committing transaction for service %d: %v", serviceID, err)
 		return err
@@ -54,14 +88,128 @@ func (r *GormServiceUserRepository) AddUsers(serviceID uint, userIDs []uint) err
 	return nil
 }
 
+// ApplyUserDiff agrega y quita usuarios sin tocar al resto del equipo. Agregar
+// a alguien que ya está no hace nada (se conserva su estado, y el puesto solo
+// cambia si la asignación trae uno), y quitar a alguien que no está tampoco,
+// así que repetir la operación es seguro.
+func (r *GormServiceUserRepository) ApplyUserDiff(serviceID uint, add []models.UserAssignment, remove []uint) error {
+	log.Printf("[ServiceUserRepository] Updating users for service %d: add=%+v remove=%+v", serviceID, add, remove)
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(remove) > 0 {
+			if err := tx.Where("service_id = ? AND user_id IN ?", serviceID, remove).
+				Delete(&models.ServiceUser{}).Error; err != nil {
+				return err
+			}
+		}
+		for _, a := range add {
+			su := models.ServiceUser{
+				ServiceID: serviceID,
+				UserID:    a.UserID,
+				Status:    models.StatusPending,
+			}
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&su).Error; err != nil {
+				return err
+			}
+		}
+		return setPositions(tx, serviceID, add)
+	})
+}
+
+// setPositions guarda el puesto de las asignaciones que traen uno.
+func setPositions(tx *gorm.DB, serviceID uint, assignments []models.UserAssignment) error {
+	for _, a := range assignments {
+		if a.PositionID == 0 {
+			continue
+		}
+		if err := tx.Model(&models.ServiceUser{}).
+			Where("service_id = ? AND user_id = ?", serviceID, a.UserID).
+			Update("position_id", a.PositionID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *GormServiceUserRepository) GetAssignment(serviceID uint, userID uint) (*models.ServiceUser, error) {
+	var su models.ServiceUser
+	result := r.db.Where("service_id = ? AND user_id = ?", serviceID, userID).First(&su)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &su, result.Error
+}
+
+// ListByService no trae a los usuarios que están en la papelera: sus filas se
+// conservan para devolverlos al equipo si se restauran.
 func (r *GormServiceUserRepository) ListByService(serviceID uint) ([]models.ServiceUser, error) {
 	var list []models.ServiceUser
-	result := database.DBConn.Where("service_id = ?", serviceID).Find(&list)
+	result := r.db.Where("service_id = ?", serviceID).
+		Where("user_id IN (?)", r.db.Model(&models.User{}).Select("id")).
+		Find(&list)
+	return list, result.Error
+}
+
+func (r *GormServiceUserRepository) ListByServices(serviceIDs []uint) ([]models.ServiceUser, error) {
+	var list []models.ServiceUser
+	if len(serviceIDs) == 0 {
+		return list, nil
+	}
+	result := r.db.Where("service_id IN ?", serviceIDs).
+		Where("user_id IN (?)", r.db.Model(&models.User{}).Select("id")).
+		Find(&list)
+	return list, result.Error
+}
+
+// ListByUsers tampoco trae los servicios que están en la papelera.
+func (r *GormServiceUserRepository) ListByUsers(userIDs []uint) ([]models.ServiceUser, error) {
+	var list []models.ServiceUser
+	if len(userIDs) == 0 {
+		return list, nil
+	}
+	result := r.db.Where("user_id IN ?", userIDs).
+		Where("service_id IN (?)", r.db.Model(&models.Service{}).Select("id")).
+		Find(&list)
+	return list, result.Error
+}
+
+// UpdateStatus aplica el cambio de estado y lo guarda en el historial. Solo
+// actualiza si el estado sigue siendo FromStatus: si otra respuesta llegó antes
+// devuelve ErrStatusChanged en vez de pisarla.
+func (r *GormServiceUserRepository) UpdateStatus(change *models.ServiceUserStatusChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ServiceUser{}).
+			Where("service_id = ? AND user_id = ? AND status = ?", change.ServiceID, change.UserID, change.FromStatus).
+			Updates(map[string]interface{}{
+				"status":            change.ToStatus,
+				"decline_reason":    change.Reason,
+				"status_changed_at": change.ChangedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrStatusChanged
+		}
+		return tx.Create(change).Error
+	})
+}
+
+func (r *GormServiceUserRepository) ListStatusHistory(serviceID uint) ([]models.ServiceUserStatusChange, error) {
+	var list []models.ServiceUserStatusChange
+	result := r.db.Where("service_id = ?", serviceID).Order("changed_at").Find(&list)
 	return list, result.Error
 }
 
-func (r *GormServiceUserRepository) UpdateStatus(serviceID uint, userID uint, status string) error {
-	return database.DBConn.Model(&models.ServiceUser{}).
+func (r *GormServiceUserRepository) SetPosition(serviceID uint, userID uint, positionID uint) error {
+	result := r.db.Model(&models.ServiceUser{}).
 		Where("service_id = ? AND user_id = ?", serviceID, userID).
-		Update("status", status).Error
+		Update("position_id", positionID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return models.ErrAssignmentNotFound
+	}
+	return nil
 }
@@ -4,25 +4,35 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
-	"melodiapp/database"
 	"melodiapp/models"
 )
 
-type GormSongRepository struct{}
+type GormSongRepository struct {
+	db *gorm.DB
+}
 
-func NewGormSongRepository() *GormSongRepository {
-	return &GormSongRepository{}
+func NewGormSongRepository(db *gorm.DB) *GormSongRepository {
+	return &GormSongRepository{db: db}
 }
 
 func (r *GormSongRepository) GetAll() ([]models.Song, error) {
 	var songs []models.Song
-	result := database.DBConn.Find(&songs)
+	result := r.db.Find(&songs)
+	return songs, result.Error
+}
+
+func (r *GormSongRepository) GetByIDs(ids []uint) ([]models.Song, error) {
+	songs := []models.Song{}
+	if len(ids) == 0 {
+		return songs, nil
+	}
+	result := r.db.Where("id IN ?", ids).Find(&songs)
 	return songs, result.Error
 }
 
 func (r *GormSongRepository) GetByID(id string) (*models.Song, error) {
 	var song models.Song
-	result := database.DBConn.Where("id = ?", id).First(&song)
+	result := r.db.Where("id = ?", id).First(&song)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -30,13 +40,64 @@ func (r *GormSongRepository) GetByID(id string) (*models.Song, error) {
 }
 
 func (r *GormSongRepository) Create(song *models.Song) error {
-	return database.DBConn.Create(song).Error
+	return r.db.Create(song).Error
 }
 
+// Update guarda solo si el registro sigue en la versión que se leyó y sube la
+// versión en uno. Si otra edición se adelantó devuelve models.ErrVersionMismatch.
 func (r *GormSongRepository) Update(song *models.Song) error {
-	return database.DBConn.Save(song).Error
+	read := song.Version
+	song.Version = read + 1
+	result := r.db.Model(song).
+		Where("version = ?", read).
+		Select("*").
+		Omit("id", "created_at", "deleted_at").
+		Updates(song)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = models.ErrVersionMismatch
+	}
+	if result.Error != nil {
+		song.Version = read
+	}
+	return result.Error
 }
 
+// DeleteByID manda la canción a la papelera.
 func (r *GormSongRepository) DeleteByID(id string) error {
-	return database.DBConn.Delete(&models.Song{}, id).Error
+	return r.db.Delete(&models.Song{}, id).Error
+}
+
+func (r *GormSongRepository) ListDeleted() ([]models.Song, error) {
+	var songs []models.Song
+	result := r.db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&songs)
+	return songs, result.Error
+}
+
+// Restore saca la canción de la papelera. Devuelve false si no estaba ahí.
+func (r *GormSongRepository) Restore(id uint) (bool, error) {
+	result := r.db.Unscoped().Model(&models.Song{}).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Purge borra definitivamente una canción de la papelera. Si sigue en algún
+// repertorio devuelve ErrSongInSetlists; en la hoja de ruta el ítem queda sin
+// canción vinculada.
+func (r *GormSongRepository) Purge(id uint) (bool, error) {
+	var purged bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var inSetlists int64
+		if err := tx.Model(&models.ServiceSong{}).Where("song_id = ?", id).Count(&inSetlists).Error; err != nil {
+			return err
+		}
+		if inSetlists > 0 {
+			return models.ErrSongInSetlists
+		}
+		if err := tx.Model(&models.RunSheetItem{}).Where("song_id = ?", id).Update("song_id", 0).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&models.Song{})
+		purged = result.RowsAffected > 0
+		return result.Error
+	})
+	return purged, err
 }
@@ -0,0 +1,141 @@
+package databaseadapter
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"melodiapp/models"
+)
+
+type GormSwapRepository struct {
+	db *gorm.DB
+}
+
+func NewGormSwapRepository(db *gorm.DB) *GormSwapRepository {
+	return &GormSwapRepository{db: db}
+}
+
+func (r *GormSwapRepository) Create(swap *models.SwapRequest, event *models.SwapEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(swap).Error; err != nil {
+			return err
+		}
+		event.SwapRequestID = swap.ID
+		return tx.Create(event).Error
+	})
+}
+
+func (r *GormSwapRepository) GetByID(id uint) (*models.SwapRequest, error) {
+	var swap models.SwapRequest
+	result := r.db.Preload("Targets").
+		Preload("Events", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		First(&swap, id)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &swap, result.Error
+}
+
+func (r *GormSwapRepository) ListByService(serviceID uint) ([]models.SwapRequest, error) {
+	var list []models.SwapRequest
+	result := r.db.Preload("Targets").
+		Where("service_id = ?", serviceID).
+		Order("created_at DESC").
+		Find(&list)
+	return list, result.Error
+}
+
+func (r *GormSwapRepository) ListOpen() ([]models.SwapRequest, error) {
+	var list []models.SwapRequest
+	result := r.db.Preload("Targets").
+		Where("status = ?", models.SwapOpen).
+		Order("created_at").
+		Find(&list)
+	return list, result.Error
+}
+
+func (r *GormSwapRepository) Update(swap *models.SwapRequest, fromStatus string, event *models.SwapEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.SwapRequest{}).
+			Where("id = ? AND status = ?", swap.ID, fromStatus).
+			Updates(map[string]interface{}{
+				"status":     swap.Status,
+				"claimed_by": swap.ClaimedBy,
+				"updated_at": time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrSwapModified
+		}
+		event.SwapRequestID = swap.ID
+		return tx.Create(event).Error
+	})
+}
+
+func (r *GormSwapRepository) Execute(swap *models.SwapRequest, event *models.SwapEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.ServiceUser
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("service_id = ? AND user_id = ?", swap.ServiceID, swap.RequesterID).
+			First(&current).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.ErrAssignmentNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var taken int64
+		if err := tx.Model(&models.ServiceUser{}).
+			Where("service_id = ? AND user_id = ?", swap.ServiceID, swap.ClaimedBy).
+			Count(&taken).Error; err != nil {
+			return err
+		}
+		if taken > 0 {
+			return models.ErrAlreadyAssigned
+		}
+
+		result := tx.Model(&models.SwapRequest{}).
+			Where("id = ? AND status = ?", swap.ID, models.SwapClaimed).
+			Updates(map[string]interface{}{"status": models.SwapApproved, "updated_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return models.ErrSwapModified
+		}
+
+		now := time.Now()
+		if err := tx.Where("service_id = ? AND user_id = ?", swap.ServiceID, swap.RequesterID).
+			Delete(&models.ServiceUser{}).Error; err != nil {
+			return err
+		}
+		replacement := models.ServiceUser{
+			ServiceID:       swap.ServiceID,
+			UserID:          swap.ClaimedBy,
+			Status:          models.StatusAccepted,
+			PositionID:      current.PositionID,
+			StatusChangedAt: &now,
+		}
+		if err := tx.Create(&replacement).Error; err != nil {
+			return err
+		}
+
+		reason := fmt.Sprintf("Swap request %d", swap.ID)
+		history := []models.ServiceUserStatusChange{
+			{ServiceID: swap.ServiceID, UserID: swap.RequesterID, FromStatus: current.Status, ToStatus: models.StatusCancelled, Reason: reason, ChangedAt: now},
+			{ServiceID: swap.ServiceID, UserID: swap.ClaimedBy, FromStatus: "", ToStatus: models.StatusAccepted, Reason: reason, ChangedAt: now},
+		}
+		if err := tx.Create(&history).Error; err != nil {
+			return err
+		}
+
+		event.SwapRequestID = swap.ID
+		return tx.Create(event).Error
+	})
+}
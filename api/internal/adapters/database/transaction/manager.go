@@ -0,0 +1,44 @@
+package databaseadapter
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+
+	dboutfit "melodiapp/internal/adapters/database/outfit"
+	dbposition "melodiapp/internal/adapters/database/position"
+	dbservice "melodiapp/internal/adapters/database/service"
+	dbserviceoutfit "melodiapp/internal/adapters/database/serviceoutfit"
+	dbservicesong "melodiapp/internal/adapters/database/servicesong"
+	dbserviceuser "melodiapp/internal/adapters/database/serviceuser"
+	dbsong "melodiapp/internal/adapters/database/song"
+	dbuser "melodiapp/internal/adapters/database/user"
+	dbuserblackout "melodiapp/internal/adapters/database/userblackout"
+	transactionports "melodiapp/internal/ports/transaction"
+)
+
+type GormTxManager struct {
+	db *gorm.DB
+}
+
+func NewGormTxManager(db *gorm.DB) *GormTxManager {
+	return &GormTxManager{db: db}
+}
+
+// WithinTx arma los repositorios sobre la transacción. Las transacciones que
+// abren los repositorios por dentro quedan como savepoints de esta.
+func (m *GormTxManager) WithinTx(ctx context.Context, fn func(repos transactionports.Repositories) error) error {
+	return m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(transactionports.Repositories{
+			Services:       dbservice.NewGormServiceRepository(tx),
+			ServiceUsers:   dbserviceuser.NewGormServiceUserRepository(tx),
+			ServiceSongs:   dbservicesong.NewGormServiceSongRepository(tx),
+			ServiceOutfits: dbserviceoutfit.NewGormServiceOutfitRepository(tx),
+			Songs:          dbsong.NewGormSongRepository(tx),
+			Outfits:        dboutfit.NewGormOutfitRepository(tx),
+			Users:          dbuser.NewGormUserRepository(tx),
+			Positions:      dbposition.NewGormPositionRepository(tx),
+			Blackouts:      dbuserblackout.NewGormUserBlackoutRepository(tx),
+		})
+	})
+}
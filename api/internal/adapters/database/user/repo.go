@@ -4,25 +4,35 @@ import (
 	"errors"
 
 	"gorm.io/gorm"
-	"melodiapp/database"
 	"melodiapp/models"
 )
 
-type GormUserRepository struct{}
+type GormUserRepository struct {
+	db *gorm.DB
+}
 
-func NewGormUserRepository() *GormUserRepository {
-	return &GormUserRepository{}
+func NewGormUserRepository(db *gorm.DB) *GormUserRepository {
+	return &GormUserRepository{db: db}
 }
 
 func (r *GormUserRepository) GetAllUsers() ([]models.User, error) {
 	var users []models.User
-	result := database.DBConn.Find(&users)
+	result := r.db.Find(&users)
+	return users, result.Error
+}
+
+func (r *GormUserRepository) GetUsersByIDs(ids []uint) ([]models.User, error) {
+	var users []models.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	result := r.db.Where("id IN ?", ids).Find(&users)
 	return users, result.Error
 }
 
 func (r *GormUserRepository) GetUserByID(id string) (*models.User, error) {
 	var user models.User
-	result := database.DBConn.Where("id = ?", id).First(&user)
+	result := r.db.Where("id = ?", id).First(&user)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -31,7 +41,7 @@ func (r *GormUserRepository) GetUserByID(id string) (*models.User, error) {
 
 func (r *GormUserRepository) GetUserByUintID(id uint) (*models.User, error) {
 	var user models.User
-	result := database.DBConn.First(&user, id)
+	result := r.db.First(&user, id)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -40,7 +50,7 @@ func (r *GormUserRepository) GetUserByUintID(id uint) (*models.User, error) {
 
 func (r *GormUserRepository) GetUserByEmail(email string) (*models.User, error) {
 	var user models.User
-	result := database.DBConn.Where("email = ?", email).First(&user)
+	result := r.db.Where("email = ?", email).First(&user)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -48,16 +58,48 @@ func (r *GormUserRepository) GetUserByEmail(email string) (*models.User, error)
 }
 
 func (r *GormUserRepository) CreateUser(user *models.User) error {
-	result := database.DBConn.Create(user)
+	result := r.db.Create(user)
 	return result.Error
 }
 
 func (r *GormUserRepository) UpdateUser(user *models.User) error {
-	result := database.DBConn.Save(user)
+	result := r.db.Save(user)
 	return result.Error
 }
 
+// DeleteUserByID manda al usuario a la papelera.
 func (r *GormUserRepository) DeleteUserByID(id string) error {
-	result := database.DBConn.Delete(&models.User{}, id)
+	result := r.db.Delete(&models.User{}, id)
 	return result.Error
 }
+
+func (r *GormUserRepository) ListDeleted() ([]models.User, error) {
+	var users []models.User
+	result := r.db.Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at DESC").Find(&users)
+	return users, result.Error
+}
+
+// Restore saca al usuario de la papelera. Devuelve false si no estaba ahí.
+func (r *GormUserRepository) Restore(id uint) (bool, error) {
+	result := r.db.Unscoped().Model(&models.User{}).Where("id = ? AND deleted_at IS NOT NULL", id).Update("deleted_at", nil)
+	return result.RowsAffected > 0, result.Error
+}
+
+// Purge borra definitivamente un usuario de la papelera. Las foreign keys
+// borran sus asignaciones, bloqueos, notificaciones y asistencias; los ítems de
+// la hoja de ruta y los intercambios que había tomado quedan sin responsable.
+func (r *GormUserRepository) Purge(id uint) (bool, error) {
+	var purged bool
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.RunSheetItem{}).Where("responsible_id = ?", id).Update("responsible_id", 0).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SwapRequest{}).Where("claimed_by = ?", id).Update("claimed_by", 0).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&models.User{})
+		purged = result.RowsAffected > 0
+		return result.Error
+	})
+	return purged, err
+}
@@ -0,0 +1,38 @@
+package databaseadapter
+
+import (
+	"gorm.io/gorm"
+	"melodiapp/models"
+)
+
+type GormUserBlackoutRepository struct {
+	db *gorm.DB
+}
+
+func NewGormUserBlackoutRepository(db *gorm.DB) *GormUserBlackoutRepository {
+	return &GormUserBlackoutRepository{db: db}
+}
+
+func (r *GormUserBlackoutRepository) ListByUser(userID uint) ([]models.UserBlackout, error) {
+	var list []models.UserBlackout
+	result := r.db.Where("user_id = ?", userID).Order("start_date").Find(&list)
+	return list, result.Error
+}
+
+func (r *GormUserBlackoutRepository) ListByUsers(userIDs []uint) ([]models.UserBlackout, error) {
+	var list []models.UserBlackout
+	if len(userIDs) == 0 {
+		return list, nil
+	}
+	result := r.db.Where("user_id IN ?", userIDs).Find(&list)
+	return list, result.Error
+}
+
+func (r *GormUserBlackoutRepository) Create(blackout *models.UserBlackout) error {
+	return r.db.Create(blackout).Error
+}
+
+func (r *GormUserBlackoutRepository) Delete(userID uint, blackoutID uint) error {
+	return r.db.Where("id = ? AND user_id = ?", blackoutID, userID).
+		Delete(&models.UserBlackout{}).Error
+}
@@ -0,0 +1,69 @@
+package emailchannel
+
+import (
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+
+	"melodiapp/models"
+)
+
+// EmailChannel envía notificaciones por SMTP. Se configura con SMTP_HOST,
+// SMTP_PORT, SMTP_USER, SMTP_PASSWORD y SMTP_FROM.
+type EmailChannel struct {
+	host     string
+	port     string
+	user     string
+	password string
+	from     string
+}
+
+func NewEmailChannel() *EmailChannel {
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+	return &EmailChannel{
+		host:     os.Getenv("SMTP_HOST"),
+		port:     port,
+		user:     os.Getenv("SMTP_USER"),
+		password: os.Getenv("SMTP_PASSWORD"),
+		from:     os.Getenv("SMTP_FROM"),
+	}
+}
+
+func (c *EmailChannel) Name() string {
+	return models.ChannelEmail
+}
+
+func (c *EmailChannel) Enabled() bool {
+	return c.host != "" && c.from != ""
+}
+
+func (c *EmailChannel) Send(user *models.User, notification *models.Notification) error {
+	if user.Email == "" {
+		return nil
+	}
+
+	body := notification.Body
+	if notification.Link != "" {
+		body += "\r\n\r\n" + notification.Link
+	}
+	msg := strings.Join([]string{
+		"From: " + c.from,
+		"To: " + user.Email,
+		"Subject: " + notification.Title,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	var auth smtp.Auth
+	if c.user != "" {
+		auth = smtp.PlainAuth("", c.user, c.password, c.host)
+	}
+	addr := fmt.Sprintf("%s:%s", c.host, c.port)
+	return smtp.SendMail(addr, auth, c.from, []string{user.Email}, []byte(msg))
+}
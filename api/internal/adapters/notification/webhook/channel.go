@@ -0,0 +1,85 @@
+package webhookchannel
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"melodiapp/models"
+)
+
+// WebhookChannel manda la notificación como JSON a NOTIFY_WEBHOOK_URL, pensado
+// para bots de WhatsApp o Telegram que usan el celular del usuario.
+// Si NOTIFY_WEBHOOK_TOKEN está definido se envía como Bearer.
+type WebhookChannel struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+type webhookPayload struct {
+	UserID    uint   `json:"user_id"`
+	Phone     string `json:"phone"`
+	Event     string `json:"event"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	ServiceID uint   `json:"service_id,omitempty"`
+	Link      string `json:"link,omitempty"`
+}
+
+func NewWebhookChannel() *WebhookChannel {
+	return &WebhookChannel{
+		url:    os.Getenv("NOTIFY_WEBHOOK_URL"),
+		token:  os.Getenv("NOTIFY_WEBHOOK_TOKEN"),
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (c *WebhookChannel) Name() string {
+	return models.ChannelWebhook
+}
+
+func (c *WebhookChannel) Enabled() bool {
+	return c.url != ""
+}
+
+func (c *WebhookChannel) Send(user *models.User, notification *models.Notification) error {
+	if user.Celphone == "" {
+		return nil
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		UserID:    user.ID,
+		Phone:     user.Celphone,
+		Event:     notification.Event,
+		Title:     notification.Title,
+		Body:      notification.Body,
+		ServiceID: notification.ServiceID,
+		Link:      notification.Link,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded %d", resp.StatusCode)
+	}
+	return nil
+}
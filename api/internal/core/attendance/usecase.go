@@ -0,0 +1,264 @@
+package attendance
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	attendanceports "melodiapp/internal/ports/attendance"
+	rehearsalports "melodiapp/internal/ports/rehearsal"
+	serviceports "melodiapp/internal/ports/service"
+	serviceuserports "melodiapp/internal/ports/serviceuser"
+	userports "melodiapp/internal/ports/user"
+	"melodiapp/models"
+)
+
+// Ventana de check-in propio: abre CheckInOpensBefore antes del inicio y
+// cierra al terminar el evento. Pasado LateGrace del inicio cuenta como tarde.
+const (
+	CheckInOpensBefore = 60 * time.Minute
+	LateGrace          = 10 * time.Minute
+)
+
+type Service struct {
+	repo            attendanceports.AttendanceRepository
+	serviceRepo     serviceports.ServiceRepository
+	serviceUserRepo serviceuserports.ServiceUserRepository
+	rehearsalRepo   rehearsalports.RehearsalRepository
+	userRepo        userports.UserRepository
+}
+
+func NewService(
+	repo attendanceports.AttendanceRepository,
+	serviceRepo serviceports.ServiceRepository,
+	serviceUserRepo serviceuserports.ServiceUserRepository,
+	rehearsalRepo rehearsalports.RehearsalRepository,
+	userRepo userports.UserRepository,
+) *Service {
+	return &Service{
+		repo:            repo,
+		serviceRepo:     serviceRepo,
+		serviceUserRepo: serviceUserRepo,
+		rehearsalRepo:   rehearsalRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// event devuelve el horario del evento y quiénes deberían asistir: el equipo
+// activo del servicio o los invitados al ensayo que no rechazaron.
+func (s *Service) event(eventType string, eventID uint) (time.Time, time.Time, []uint, error) {
+	switch eventType {
+	case models.EventService:
+		svc, err := s.serviceRepo.GetByID(strconv.FormatUint(uint64(eventID), 10))
+		if err != nil {
+			return time.Time{}, time.Time{}, nil, err
+		}
+		if svc == nil {
+			return time.Time{}, time.Time{}, nil, models.ErrServiceNotFound
+		}
+		start, end, err := svc.Window()
+		if err != nil {
+			return time.Time{}, time.Time{}, nil, err
+		}
+		team, err := s.serviceUserRepo.ListByService(eventID)
+		if err != nil {
+			return time.Time{}, time.Time{}, nil, err
+		}
+		expected := make([]uint, 0, len(team))
+		for _, member := range team {
+			if member.IsActive() {
+				expected = append(expected, member.UserID)
+			}
+		}
+		return start, end, expected, nil
+
+	case models.EventRehearsal:
+		rehearsal, err := s.rehearsalRepo.GetByID(eventID)
+		if err != nil {
+			return time.Time{}, time.Time{}, nil, err
+		}
+		if rehearsal == nil {
+			return time.Time{}, time.Time{}, nil, models.ErrRehearsalNotFound
+		}
+		expected := make([]uint, 0, len(rehearsal.Attendees))
+		for _, a := range rehearsal.Attendees {
+			if a.RSVP != models.StatusDeclined {
+				expected = append(expected, a.UserID)
+			}
+		}
+		return rehearsal.StartTime, rehearsal.EndTime, expected, nil
+	}
+	return time.Time{}, time.Time{}, nil, models.Invalid("invalid_event_type", "Invalid event type")
+}
+
+func contains(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckIn registra la llegada del propio usuario dentro de la ventana permitida.
+func (s *Service) CheckIn(eventType string, eventID uint, user *models.User) (*models.Attendance, error) {
+	start, end, expected, err := s.event(eventType, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !contains(expected, user.ID) {
+		return nil, models.Forbidden("not_expected_at_event", "User is not expected at this event")
+	}
+
+	now := time.Now()
+	if now.Before(start.Add(-CheckInOpensBefore)) || now.After(end) {
+		return nil, models.Conflict("check_in_closed", "Check-in is not open")
+	}
+
+	record, err := s.repo.Get(eventType, eventID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil && record.CheckedInAt != nil {
+		return nil, models.Conflict("already_checked_in", "Already checked in")
+	}
+	if record == nil {
+		record = &models.Attendance{EventType: eventType, EventID: eventID, UserID: user.ID}
+	}
+
+	record.CheckedInAt = &now
+	record.Source = models.CheckInSelf
+	record.MarkedBy = user.ID
+	record.Status, record.LateMinutes = arrivalStatus(start, now)
+	if err := s.repo.Save(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func arrivalStatus(start, arrival time.Time) (string, int) {
+	if arrival.After(start.Add(LateGrace)) {
+		return models.AttendanceLate, int(arrival.Sub(start).Minutes())
+	}
+	return models.AttendancePresent, 0
+}
+
+// Mark deja que un admin corrija o registre la asistencia de cualquier integrante.
+func (s *Service) Mark(eventType string, eventID uint, userID uint, status string, note string, admin *models.User) (*models.Attendance, error) {
+	if !models.IsAttendanceStatus(status) {
+		return nil, models.Invalid("invalid_attendance_status", "Invalid attendance status")
+	}
+	start, _, expected, err := s.event(eventType, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !contains(expected, userID) {
+		return nil, models.Forbidden("not_expected_at_event", "User is not expected at this event")
+	}
+
+	record, err := s.repo.Get(eventType, eventID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		record = &models.Attendance{EventType: eventType, EventID: eventID, UserID: userID}
+	}
+
+	record.Status = status
+	record.Source = models.CheckInAdmin
+	record.MarkedBy = admin.ID
+	record.Note = strings.TrimSpace(note)
+	switch status {
+	case models.AttendancePresent, models.AttendanceLate:
+		if record.CheckedInAt == nil {
+			now := time.Now()
+			record.CheckedInAt = &now
+		}
+		if status == models.AttendanceLate && record.LateMinutes == 0 {
+			_, record.LateMinutes = arrivalStatus(start, *record.CheckedInAt)
+		}
+		if status == models.AttendancePresent {
+			record.LateMinutes = 0
+		}
+	default:
+		record.CheckedInAt = nil
+		record.LateMinutes = 0
+	}
+
+	if err := s.repo.Save(record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Close cierra la asistencia del evento: quien debía venir y no tiene registro
+// queda como no_show. Solo se puede cerrar una vez empezado el evento.
+func (s *Service) Close(eventType string, eventID uint, admin *models.User) ([]models.Attendance, error) {
+	start, _, expected, err := s.event(eventType, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().Before(start) {
+		return nil, models.Conflict("event_not_started", "Event has not started")
+	}
+
+	existing, err := s.repo.ListByEvent(eventType, eventID)
+	if err != nil {
+		return nil, err
+	}
+	recorded := make(map[uint]bool, len(existing))
+	for _, r := range existing {
+		recorded[r.UserID] = true
+	}
+
+	for _, uid := range expected {
+		if recorded[uid] {
+			continue
+		}
+		record := &models.Attendance{
+			EventType: eventType,
+			EventID:   eventID,
+			UserID:    uid,
+			Status:    models.AttendanceNoShow,
+			Source:    models.CheckInAdmin,
+			MarkedBy:  admin.ID,
+		}
+		if err := s.repo.Save(record); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.ListByEvent(eventType, eventID)
+}
+
+func (s *Service) ListByEvent(eventType string, eventID uint) ([]models.Attendance, error) {
+	if _, _, _, err := s.event(eventType, eventID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByEvent(eventType, eventID)
+}
+
+// Reliability calcula las estadísticas de userIDs o, si está vacío, de todos los usuarios.
+func (s *Service) Reliability(userIDs []uint) ([]models.MemberReliability, error) {
+	if len(userIDs) == 0 {
+		users, err := s.userRepo.GetAllUsers()
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range users {
+			userIDs = append(userIDs, u.ID)
+		}
+	}
+	records, err := s.repo.ListByUsers(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	stats := models.ComputeReliability(userIDs, records)
+
+	list := make([]models.MemberReliability, 0, len(stats))
+	for _, st := range stats {
+		list = append(list, *st)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].UserID < list[j].UserID })
+	return list, nil
+}
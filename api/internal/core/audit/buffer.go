@@ -0,0 +1,35 @@
+package audit
+
+import (
+	"context"
+
+	auditports "melodiapp/internal/ports/audit"
+	"melodiapp/models"
+)
+
+type pendingEntry struct {
+	ctx   context.Context
+	entry models.AuditEntry
+}
+
+// Buffer junta las entradas generadas dentro de una transacción para
+// guardarlas recién cuando se confirma, igual que el Outbox de notificaciones.
+type Buffer struct {
+	target  auditports.Recorder
+	pending []pendingEntry
+}
+
+func NewBuffer(target auditports.Recorder) *Buffer {
+	return &Buffer{target: target}
+}
+
+func (b *Buffer) Record(ctx context.Context, entry models.AuditEntry) {
+	b.pending = append(b.pending, pendingEntry{ctx: ctx, entry: entry})
+}
+
+func (b *Buffer) Flush() {
+	for _, p := range b.pending {
+		b.target.Record(p.ctx, p.entry)
+	}
+	b.pending = nil
+}
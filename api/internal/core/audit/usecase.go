@@ -0,0 +1,149 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+
+	auditports "melodiapp/internal/ports/audit"
+	"melodiapp/models"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// ignoredFields no cuentan como cambio ni se guardan (password).
+var ignoredFields = map[string]bool{
+	"password":   true,
+	"updated_at": true,
+	"version":    true,
+}
+
+type Service struct {
+	repo auditports.AuditRepository
+}
+
+func NewService(repo auditports.AuditRepository) *Service {
+	return &Service{repo: repo}
+}
+
+type change struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// Record guarda la entrada con el actor de ctx. Una falla se registra en el log
+// pero no rompe la operación auditada. Una actualización sin cambios no se guarda.
+func (s *Service) Record(ctx context.Context, entry models.AuditEntry) {
+	if err := s.record(ctx, entry); err != nil {
+		log.Printf("[Audit] Could not record %s %s %d: %v", entry.Action, entry.EntityType, entry.EntityID, err)
+	}
+}
+
+func (s *Service) record(ctx context.Context, entry models.AuditEntry) error {
+	before, err := snapshot(entry.Before)
+	if err != nil {
+		return err
+	}
+	after, err := snapshot(entry.After)
+	if err != nil {
+		return err
+	}
+	changes := diff(before, after)
+	if entry.Action == models.AuditUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	actor := models.AuditActorFrom(ctx)
+	row := &models.AuditLog{
+		ActorID:    actor.UserID,
+		Action:     entry.Action,
+		EntityType: entry.EntityType,
+		EntityID:   entry.EntityID,
+		ServiceID:  entry.ServiceID,
+		IP:         actor.IP,
+	}
+	if row.Before, err = encode(before); err != nil {
+		return err
+	}
+	if row.After, err = encode(after); err != nil {
+		return err
+	}
+	if row.Changes, err = json.Marshal(changes); err != nil {
+		return err
+	}
+	return s.repo.Create(row)
+}
+
+// snapshot pasa el valor a un mapa JSON sin los campos ignorados.
+func snapshot(value any) (map[string]any, error) {
+	if v := reflect.ValueOf(value); !v.IsValid() || v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	for name := range ignoredFields {
+		delete(fields, name)
+	}
+	return fields, nil
+}
+
+func encode(fields map[string]any) (json.RawMessage, error) {
+	if fields == nil {
+		return nil, nil
+	}
+	return json.Marshal(fields)
+}
+
+func diff(before, after map[string]any) map[string]change {
+	changes := make(map[string]change)
+	for name, from := range before {
+		if to, ok := after[name]; !ok || !reflect.DeepEqual(from, to) {
+			changes[name] = change{From: from, To: after[name]}
+		}
+	}
+	for name, to := range after {
+		if _, ok := before[name]; !ok {
+			changes[name] = change{From: nil, To: to}
+		}
+	}
+	return changes
+}
+
+func (s *Service) List(filter models.AuditFilter) (*models.AuditPage, error) {
+	if filter.Action != "" && !isAction(filter.Action) {
+		return nil, models.Invalid("invalid_audit_action", "Invalid audit action")
+	}
+	if filter.Page <= 0 {
+		filter.Page = 1
+	}
+	if filter.PageSize <= 0 {
+		filter.PageSize = defaultPageSize
+	}
+	if filter.PageSize > maxPageSize {
+		filter.PageSize = maxPageSize
+	}
+
+	logs, total, err := s.repo.List(filter)
+	if err != nil {
+		return nil, err
+	}
+	return &models.AuditPage{Items: logs, Total: total, Page: filter.Page, PageSize: filter.PageSize}, nil
+}
+
+func isAction(value string) bool {
+	switch value {
+	case models.AuditCreate, models.AuditUpdate, models.AuditDelete, models.AuditRestore, models.AuditPurge:
+		return true
+	}
+	return false
+}
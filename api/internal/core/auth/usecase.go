@@ -1,7 +1,6 @@
 package authcore
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"regexp"
@@ -16,6 +15,11 @@ import (
 	"melodiapp/shared"
 )
 
+var (
+	errInvalidEmail       = models.Invalid("invalid_email", "Invalid email format")
+	errInvalidCredentials = &models.Error{Kind: models.KindUnauthorized, Code: "invalid_credentials", Message: "Invalid credentials"}
+)
+
 type Service struct {
 	repo userports.UserRepository
 }
@@ -26,13 +30,13 @@ func NewService(repo userports.UserRepository) *Service {
 
 func (s *Service) Register(input models.UserInput) (string, error) {
 	if input.Username == "" || input.Email == "" || input.Password == "" {
-		return "", errors.New("Incomplete fields")
+		return "", models.ErrIncompleteFields
 	}
 
 	emailRegex := `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
 	re := regexp.MustCompile(emailRegex)
 	if !re.MatchString(input.Email) {
-		return "", errors.New("Invalid email format")
+		return "", errInvalidEmail
 	}
 
 	// Check existing by email
@@ -41,7 +45,7 @@ func (s *Service) Register(input models.UserInput) (string, error) {
 		return "", err
 	}
 	if existingByEmail != nil {
-		return "", fmt.Errorf("Email already exists")
+		return "", models.Conflict("email_taken", "Email already exists")
 	}
 
 	user := models.User{
@@ -81,13 +85,13 @@ func (s *Service) Logout(tokenStr string) error {
 	})
 
 	if err != nil {
-		return fmt.Errorf("invalid token: %w", err)
+		return models.ErrInvalidToken
 	}
 
 	claims, _ := token.Claims.(*shared.Payload)
 	_, exists := shared.Sessions[claims.Session]
 	if !exists {
-		return errors.New("You don't have permission")
+		return models.ErrUnauthorized
 	}
 
 	delete(shared.Sessions, claims.Session)
@@ -97,13 +101,13 @@ func (s *Service) Logout(tokenStr string) error {
 
 func (s *Service) Login(input models.UserInput) (string, error) {
 	if input.Email == "" || input.Password == "" {
-		return "", errors.New("Incomplete fields")
+		return "", models.ErrIncompleteFields
 	}
 
 	emailRegex := `^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`
 	re := regexp.MustCompile(emailRegex)
 	if !re.MatchString(input.Email) {
-		return "", errors.New("Invalid email format")
+		return "", errInvalidEmail
 	}
 
 	user, err := s.repo.GetUserByEmail(input.Email)
@@ -111,11 +115,11 @@ func (s *Service) Login(input models.UserInput) (string, error) {
 		return "", err
 	}
 	if user == nil {
-		return "", errors.New("Invalid credentials")
+		return "", errInvalidCredentials
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(input.Password)); err != nil {
-		return "", errors.New("Invalid credentials")
+		return "", errInvalidCredentials
 	}
 
 	token, err := createSessionAndToken(user.ID)
@@ -0,0 +1,162 @@
+package calendar
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	calendarports "melodiapp/internal/ports/calendar"
+	rehearsalports "melodiapp/internal/ports/rehearsal"
+	serviceports "melodiapp/internal/ports/service"
+	serviceuserports "melodiapp/internal/ports/serviceuser"
+	userports "melodiapp/internal/ports/user"
+	"melodiapp/models"
+)
+
+// tokenBytes es el largo en bytes aleatorios de los tokens de los feeds.
+const tokenBytes = 32
+
+type Service struct {
+	serviceRepo     serviceports.ServiceRepository
+	serviceUserRepo serviceuserports.ServiceUserRepository
+	rehearsalRepo   rehearsalports.RehearsalRepository
+	tokenRepo       calendarports.CalendarTokenRepository
+	userRepo        userports.UserRepository
+}
+
+func NewService(
+	serviceRepo serviceports.ServiceRepository,
+	serviceUserRepo serviceuserports.ServiceUserRepository,
+	rehearsalRepo rehearsalports.RehearsalRepository,
+	tokenRepo calendarports.CalendarTokenRepository,
+	userRepo userports.UserRepository,
+) *Service {
+	return &Service{
+		serviceRepo:     serviceRepo,
+		serviceUserRepo: serviceUserRepo,
+		rehearsalRepo:   rehearsalRepo,
+		tokenRepo:       tokenRepo,
+		userRepo:        userRepo,
+	}
+}
+
+// IssueToken genera el token del feed .ics del usuario. Si ya tenía uno, el
+// anterior deja de servir.
+func (s *Service) IssueToken(userID uint) (*models.CalendarFeed, error) {
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	saved := &models.CalendarToken{UserID: userID, TokenHash: hashToken(token), CreatedAt: time.Now()}
+	if err := s.tokenRepo.Save(saved); err != nil {
+		return nil, err
+	}
+	return &models.CalendarFeed{
+		Token:     token,
+		URL:       "/calendar/feed/" + token + ".ics",
+		CreatedAt: saved.CreatedAt,
+	}, nil
+}
+
+func (s *Service) RevokeToken(userID uint) error {
+	return s.tokenRepo.Delete(userID)
+}
+
+// EventsForToken es EventsForUser para el dueño del token. Un token revocado,
+// rotado o de un usuario en la papelera no sirve.
+func (s *Service) EventsForToken(token string) ([]models.CalendarEvent, error) {
+	saved, err := s.tokenRepo.GetByHash(hashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if saved == nil {
+		return nil, models.ErrInvalidToken
+	}
+	user, err := s.userRepo.GetUserByUintID(saved.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, models.ErrInvalidToken
+	}
+	return s.EventsForUser(user.ID)
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// EventsForUser junta los servicios donde el usuario sirve (sin contar
+// rechazos ni cancelaciones) y los ensayos a los que está invitado.
+func (s *Service) EventsForUser(userID uint) ([]models.CalendarEvent, error) {
+	events := []models.CalendarEvent{}
+
+	assignments, err := s.serviceUserRepo.ListByUsers([]uint{userID})
+	if err != nil {
+		return nil, err
+	}
+	status := make(map[uint]string, len(assignments))
+	serviceIDs := make([]uint, 0, len(assignments))
+	for _, a := range assignments {
+		if a.IsActive() {
+			status[a.ServiceID] = a.Status
+			serviceIDs = append(serviceIDs, a.ServiceID)
+		}
+	}
+	services, err := s.serviceRepo.GetByIDs(serviceIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, svc := range services {
+		start, end, err := svc.Window()
+		if err != nil {
+			continue
+		}
+		events = append(events, models.CalendarEvent{
+			UID:    fmt.Sprintf("service-%d@melodiapp", svc.ID),
+			Kind:   models.CalendarService,
+			RefID:  svc.ID,
+			Title:  svc.Name,
+			Start:  start,
+			End:    end,
+			Status: status[svc.ID],
+		})
+	}
+
+	rehearsals, err := s.rehearsalRepo.ListByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rehearsals {
+		rsvp := models.StatusPending
+		for _, a := range r.Attendees {
+			if a.UserID == userID {
+				rsvp = a.RSVP
+			}
+		}
+		if rsvp == models.StatusDeclined {
+			continue
+		}
+		events = append(events, models.CalendarEvent{
+			UID:         fmt.Sprintf("rehearsal-%d@melodiapp", r.ID),
+			Kind:        models.CalendarRehearsal,
+			RefID:       r.ID,
+			Title:       r.Name,
+			Start:       r.StartTime,
+			End:         r.EndTime,
+			Location:    r.Location,
+			Description: r.Notes,
+			Status:      rsvp,
+		})
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Start.Before(events[j].Start) })
+	return events, nil
+}
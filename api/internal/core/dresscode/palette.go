@@ -0,0 +1,108 @@
+package dresscode
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"melodiapp/models"
+)
+
+// similarDistance es la distancia RGB por debajo de la cual dos colores se
+// consideran "el mismo" a efectos de no repetir vestimenta.
+const similarDistance = 60.0
+
+type rgb struct{ r, g, b float64 }
+
+type hsl struct{ h, s, l float64 }
+
+// parseHex recibe colores ya normalizados (#rrggbb).
+func parseHex(hex string) (rgb, error) {
+	if len(hex) != 7 || hex[0] != '#' {
+		return rgb{}, models.ErrInvalidHexColor
+	}
+	v, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return rgb{}, models.ErrInvalidHexColor
+	}
+	return rgb{float64(v >> 16 & 0xff), float64(v >> 8 & 0xff), float64(v & 0xff)}, nil
+}
+
+func (c rgb) hex() string {
+	clamp := func(v float64) int { return int(math.Max(0, math.Min(255, math.Round(v)))) }
+	return fmt.Sprintf("#%02x%02x%02x", clamp(c.r), clamp(c.g), clamp(c.b))
+}
+
+func (c rgb) distance(other rgb) float64 {
+	return math.Sqrt((c.r-other.r)*(c.r-other.r) + (c.g-other.g)*(c.g-other.g) + (c.b-other.b)*(c.b-other.b))
+}
+
+func (c rgb) toHSL() hsl {
+	r, g, b := c.r/255, c.g/255, c.b/255
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	l := (max + min) / 2
+	if max == min {
+		return hsl{0, 0, l}
+	}
+
+	d := max - min
+	s := d / (1 - math.Abs(2*l-1))
+	var h float64
+	switch max {
+	case r:
+		h = math.Mod((g-b)/d, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return hsl{h, s, l}
+}
+
+func (c hsl) toRGB() rgb {
+	chroma := (1 - math.Abs(2*c.l-1)) * c.s
+	x := chroma * (1 - math.Abs(math.Mod(c.h/60, 2)-1))
+	m := c.l - chroma/2
+
+	var r, g, b float64
+	switch {
+	case c.h < 60:
+		r, g, b = chroma, x, 0
+	case c.h < 120:
+		r, g, b = x, chroma, 0
+	case c.h < 180:
+		r, g, b = 0, chroma, x
+	case c.h < 240:
+		r, g, b = 0, x, chroma
+	case c.h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return rgb{(r + m) * 255, (g + m) * 255, (b + m) * 255}
+}
+
+func (c hsl) rotate(degrees float64) hsl {
+	c.h = math.Mod(c.h+degrees+360, 360)
+	return c
+}
+
+func (c hsl) withLightness(l float64) hsl {
+	c.l = math.Max(0.1, math.Min(0.9, l))
+	return c
+}
+
+// harmony devuelve la paleta base del esquema pedido.
+func harmony(base hsl, scheme string) []hsl {
+	switch scheme {
+	case "analogous":
+		return []hsl{base.rotate(-30), base, base.rotate(30)}
+	default:
+		return []hsl{base, base.rotate(180)}
+	}
+}
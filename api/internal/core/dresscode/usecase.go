@@ -0,0 +1,255 @@
+package dresscode
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	dresscodeports "melodiapp/internal/ports/dresscode"
+	positionports "melodiapp/internal/ports/position"
+	serviceports "melodiapp/internal/ports/service"
+	serviceoutfitports "melodiapp/internal/ports/serviceoutfit"
+	"melodiapp/models"
+)
+
+// defaultRecentServices es cuántos servicios anteriores se revisan para no repetir colores.
+const defaultRecentServices = 4
+
+// lightnessShifts genera variantes más claras y más oscuras de cada paleta.
+var lightnessShifts = []float64{0, 0.15, -0.15}
+
+type Service struct {
+	repo              dresscodeports.DressCodeRepository
+	serviceRepo       serviceports.ServiceRepository
+	serviceOutfitRepo serviceoutfitports.ServiceOutfitRepository
+	positionRepo      positionports.PositionRepository
+}
+
+func NewService(
+	repo dresscodeports.DressCodeRepository,
+	serviceRepo serviceports.ServiceRepository,
+	serviceOutfitRepo serviceoutfitports.ServiceOutfitRepository,
+	positionRepo positionports.PositionRepository,
+) *Service {
+	return &Service{
+		repo:              repo,
+		serviceRepo:       serviceRepo,
+		serviceOutfitRepo: serviceOutfitRepo,
+		positionRepo:      positionRepo,
+	}
+}
+
+func (s *Service) ListByService(serviceID uint) ([]models.ServiceDressCode, error) {
+	return s.repo.ListByService(serviceID)
+}
+
+// SetForService reemplaza los códigos de vestimenta del servicio. Cada outfit
+// referenciado debe estar asignado al servicio; si el código no trae colores,
+// toma los del outfit.
+func (s *Service) SetForService(serviceID uint, codes []models.ServiceDressCode) ([]models.ServiceDressCode, error) {
+	svc, err := s.serviceRepo.GetByID(strconv.FormatUint(uint64(serviceID), 10))
+	if err != nil {
+		return nil, err
+	}
+	if svc == nil {
+		return nil, models.ErrServiceNotFound
+	}
+
+	linked, err := s.serviceOutfitRepo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	outfits := make(map[uint]*models.Outfit, len(linked))
+	for _, so := range linked {
+		outfits[so.OutfitID] = so.Outfit
+	}
+
+	groups := make(map[string]bool)
+	for i := range codes {
+		code := &codes[i]
+		code.Group = strings.TrimSpace(code.Group)
+
+		if code.PositionID != 0 {
+			position, err := s.positionRepo.GetByID(code.PositionID)
+			if err != nil {
+				return nil, err
+			}
+			if position == nil {
+				return nil, models.ErrPositionNotFound
+			}
+			if code.Group == "" {
+				code.Group = position.Name
+			}
+		}
+		if code.Group == "" {
+			return nil, models.ErrIncompleteFields
+		}
+		key := strings.ToLower(code.Group)
+		if groups[key] {
+			return nil, models.Invalid("duplicated_dress_code_group", "Duplicated dress code group")
+		}
+		groups[key] = true
+
+		if code.OutfitID != 0 {
+			outfit, ok := outfits[code.OutfitID]
+			if !ok {
+				return nil, models.Invalid("outfit_not_in_service", "Outfit is not assigned to the service")
+			}
+			if len(code.Colors) == 0 && outfit != nil {
+				code.Colors = outfit.Colors
+			}
+		}
+
+		colors, err := code.Colors.Normalize()
+		if err != nil {
+			return nil, err
+		}
+		code.Colors = colors
+	}
+
+	if err := s.repo.ReplaceForService(serviceID, codes); err != nil {
+		return nil, err
+	}
+	return s.repo.ListByService(serviceID)
+}
+
+type usedColor struct {
+	color     rgb
+	hex       string
+	outfitID  uint
+	serviceID uint
+}
+
+// SuggestPalettes arma paletas armónicas a partir de base y las compara con los
+// outfits de los últimos servicios. Las paletas sin repeticiones van primero.
+func (s *Service) SuggestPalettes(base string, scheme string, serviceID uint, recent int) ([]models.PaletteSuggestion, error) {
+	schemes := []string{models.PaletteComplementary, models.PaletteAnalogous}
+	if scheme != "" {
+		if scheme != models.PaletteComplementary && scheme != models.PaletteAnalogous {
+			return nil, models.Invalid("invalid_palette_scheme", "Invalid palette scheme")
+		}
+		schemes = []string{scheme}
+	}
+	if recent <= 0 {
+		recent = defaultRecentServices
+	}
+
+	normalized, err := models.HexColors{base}.Normalize()
+	if err != nil {
+		return nil, err
+	}
+	baseRGB, err := parseHex(normalized[0])
+	if err != nil {
+		return nil, err
+	}
+
+	used, err := s.recentColors(serviceID, recent)
+	if err != nil {
+		return nil, err
+	}
+
+	baseHSL := baseRGB.toHSL()
+	suggestions := []models.PaletteSuggestion{}
+	seen := make(map[string]bool)
+	for _, sc := range schemes {
+		for _, shift := range lightnessShifts {
+			suggestion := models.PaletteSuggestion{Scheme: sc, Colors: models.HexColors{}, RecentMatches: []models.PaletteMatch{}}
+			for _, c := range harmony(baseHSL, sc) {
+				color := c.withLightness(c.l + shift).toRGB()
+				hex := color.hex()
+				suggestion.Colors = append(suggestion.Colors, hex)
+				for _, u := range used {
+					if color.distance(u.color) < similarDistance {
+						suggestion.RecentMatches = append(suggestion.RecentMatches, models.PaletteMatch{
+							Color:     hex,
+							UsedColor: u.hex,
+							OutfitID:  u.outfitID,
+							ServiceID: u.serviceID,
+						})
+					}
+				}
+			}
+
+			key := strings.Join(suggestion.Colors, ",")
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			suggestion.Fresh = len(suggestion.RecentMatches) == 0
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	sort.SliceStable(suggestions, func(i, j int) bool {
+		return len(suggestions[i].RecentMatches) < len(suggestions[j].RecentMatches)
+	})
+	return suggestions, nil
+}
+
+// recentColors junta los colores de los outfits de los últimos servicios
+// anteriores al servicio indicado (o a ahora, si serviceID es 0).
+func (s *Service) recentColors(serviceID uint, recent int) ([]usedColor, error) {
+	reference := time.Now()
+	if serviceID != 0 {
+		svc, err := s.serviceRepo.GetByID(strconv.FormatUint(uint64(serviceID), 10))
+		if err != nil {
+			return nil, err
+		}
+		if svc == nil {
+			return nil, models.ErrServiceNotFound
+		}
+		if start, _, err := svc.Window(); err == nil {
+			reference = start
+		}
+	}
+
+	all, err := s.serviceRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	type dated struct {
+		id    uint
+		start time.Time
+	}
+	var previous []dated
+	for _, svc := range all {
+		start, _, err := svc.Window()
+		if err != nil || svc.ID == serviceID || !start.Before(reference) {
+			continue
+		}
+		previous = append(previous, dated{svc.ID, start})
+	}
+	sort.Slice(previous, func(i, j int) bool { return previous[i].start.After(previous[j].start) })
+	if len(previous) > recent {
+		previous = previous[:recent]
+	}
+
+	ids := make([]uint, 0, len(previous))
+	for _, p := range previous {
+		ids = append(ids, p.id)
+	}
+	linked, err := s.serviceOutfitRepo.ListByServices(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var used []usedColor
+	for _, so := range linked {
+		if so.Outfit == nil {
+			continue
+		}
+		colors, err := so.Outfit.Colors.Normalize()
+		if err != nil {
+			continue
+		}
+		for _, hex := range colors {
+			c, err := parseHex(hex)
+			if err != nil {
+				continue
+			}
+			used = append(used, usedColor{color: c, hex: hex, outfitID: so.OutfitID, serviceID: so.ServiceID})
+		}
+	}
+	return used, nil
+}
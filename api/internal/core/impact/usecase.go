@@ -0,0 +1,84 @@
+package impact
+
+import (
+	"sort"
+
+	impactports "melodiapp/internal/ports/impact"
+	serviceports "melodiapp/internal/ports/service"
+	"melodiapp/models"
+)
+
+type Service struct {
+	repo        impactports.ImpactRepository
+	serviceRepo serviceports.ServiceRepository
+}
+
+func NewService(repo impactports.ImpactRepository, serviceRepo serviceports.ServiceRepository) *Service {
+	return &Service{repo: repo, serviceRepo: serviceRepo}
+}
+
+// Preview cuenta, sin borrar nada, las filas que se borrarían, bloquearían el
+// borrado o quedarían sin asignar, y lista los servicios afectados.
+func (s *Service) Preview(resource string, id uint) (*models.DeleteImpact, error) {
+	refs, ok := models.References[resource]
+	if !ok {
+		return nil, models.ErrInvalidResource
+	}
+	exists, err := s.repo.Exists(resource, id)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, models.NotFound("resource_not_found", "Resource not found")
+	}
+
+	impact := &models.DeleteImpact{
+		Resource: resource,
+		ID:       id,
+		Allowed:  true,
+		Effects:  []models.ImpactEffect{},
+		Services: []models.ImpactedService{},
+	}
+	serviceIDs := make(map[uint]bool)
+	for _, ref := range refs {
+		count, err := s.repo.Count(ref, id)
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			continue
+		}
+		impact.Effects = append(impact.Effects, models.ImpactEffect{Reference: ref, Count: count})
+		if ref.OnDelete == models.OnDeleteRestrict {
+			impact.Allowed = false
+		}
+
+		if !ref.ByService {
+			continue
+		}
+		ids, err := s.repo.ServiceIDs(ref, id)
+		if err != nil {
+			return nil, err
+		}
+		for _, sid := range ids {
+			serviceIDs[sid] = true
+		}
+	}
+
+	if len(serviceIDs) == 0 {
+		return impact, nil
+	}
+	ids := make([]uint, 0, len(serviceIDs))
+	for sid := range serviceIDs {
+		ids = append(ids, sid)
+	}
+	services, err := s.serviceRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(services, func(i, j int) bool { return services[i].StartTime < services[j].StartTime })
+	for _, svc := range services {
+		impact.Services = append(impact.Services, models.ImpactedService{ID: svc.ID, Name: svc.Name, StartTime: svc.StartTime})
+	}
+	return impact, nil
+}
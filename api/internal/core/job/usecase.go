@@ -0,0 +1,108 @@
+package job
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	jobports "melodiapp/internal/ports/job"
+	"melodiapp/models"
+)
+
+const (
+	defaultMaxAttempts = 5
+	defaultListLimit   = 50
+)
+
+type Service struct {
+	repo jobports.JobRepository
+}
+
+func NewService(repo jobports.JobRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Enqueue guarda el trabajo para que lo tome un worker. Con opts.RunAt en el
+// futuro queda programado hasta esa hora.
+func (s *Service) Enqueue(jobType string, payload any, opts models.JobOptions) (*models.Job, error) {
+	jobType = strings.TrimSpace(jobType)
+	if jobType == "" {
+		return nil, models.Invalid("invalid_job_type", "Invalid job type")
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &models.Job{
+		Type:        jobType,
+		Payload:     data,
+		Status:      models.JobQueued,
+		RunAt:       opts.RunAt,
+		MaxAttempts: opts.MaxAttempts,
+	}
+	if job.RunAt.IsZero() {
+		job.RunAt = time.Now()
+	}
+	if job.MaxAttempts <= 0 {
+		job.MaxAttempts = defaultMaxAttempts
+	}
+	if err := s.repo.Create(job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+func (s *Service) List(filter models.JobFilter) ([]models.Job, error) {
+	if filter.Status != "" && !models.IsJobStatus(filter.Status) {
+		return nil, models.Invalid("invalid_job_status", "Invalid job status")
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultListLimit
+	}
+	return s.repo.List(filter)
+}
+
+func (s *Service) GetByID(id uint) (*models.Job, error) {
+	return s.repo.GetByID(id)
+}
+
+// Stats devuelve cuántos trabajos hay en cada estado.
+func (s *Service) Stats() (map[string]int64, error) {
+	counts, err := s.repo.CountByStatus()
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range []string{models.JobQueued, models.JobRunning, models.JobSucceeded, models.JobDead} {
+		if _, ok := counts[status]; !ok {
+			counts[status] = 0
+		}
+	}
+	return counts, nil
+}
+
+// Retry vuelve a encolar un trabajo para que corra ya, con los intentos en cero.
+// Los que están corriendo o ya terminaron bien no se reintentan.
+func (s *Service) Retry(id uint) (*models.Job, error) {
+	job, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil {
+		return nil, models.ErrJobNotFound
+	}
+	if job.Status == models.JobRunning {
+		return nil, models.Conflict("job_running", "Job is running")
+	}
+	if job.Status == models.JobSucceeded {
+		return nil, models.Conflict("job_succeeded", "Job already succeeded")
+	}
+	retried, err := s.repo.Retry(id, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !retried {
+		return nil, models.Conflict("job_modified", "Job changed state, try again")
+	}
+	return s.repo.GetByID(id)
+}
@@ -0,0 +1,184 @@
+package job
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	jobports "melodiapp/internal/ports/job"
+)
+
+// WorkerConfig se lee de JOB_WORKERS, JOB_POLL_INTERVAL y JOB_TIMEOUT.
+type WorkerConfig struct {
+	Concurrency  int
+	PollInterval time.Duration
+	// Timeout es lo máximo que puede tardar un trabajo; pasado el doble, otro
+	// worker lo da por perdido y lo vuelve a encolar.
+	Timeout time.Duration
+}
+
+func WorkerConfigFromEnv() WorkerConfig {
+	config := WorkerConfig{Concurrency: 2, PollInterval: 2 * time.Second, Timeout: 5 * time.Minute}
+	if n, err := strconv.Atoi(os.Getenv("JOB_WORKERS")); err == nil && n > 0 {
+		config.Concurrency = n
+	}
+	if d, err := time.ParseDuration(os.Getenv("JOB_POLL_INTERVAL")); err == nil && d > 0 {
+		config.PollInterval = d
+	}
+	if d, err := time.ParseDuration(os.Getenv("JOB_TIMEOUT")); err == nil && d > 0 {
+		config.Timeout = d
+	}
+	return config
+}
+
+// Worker toma trabajos de la cola y los pasa al handler registrado para su tipo.
+type Worker struct {
+	config   WorkerConfig
+	repo     jobports.JobRepository
+	handlers map[string]jobports.Handler
+	name     string
+}
+
+func NewWorker(config WorkerConfig, repo jobports.JobRepository) *Worker {
+	host, _ := os.Hostname()
+	return &Worker{
+		config:   config,
+		repo:     repo,
+		handlers: make(map[string]jobports.Handler),
+		name:     fmt.Sprintf("%s:%d", host, os.Getpid()),
+	}
+}
+
+// Register asocia un handler a un tipo de trabajo. Se llama antes de Run.
+func (w *Worker) Register(jobType string, handler jobports.Handler) {
+	w.handlers[jobType] = handler
+}
+
+// Run procesa la cola hasta que se cancele ctx. Al cancelarse no toma trabajos
+// nuevos y espera a que terminen los que están en curso.
+func (w *Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < w.config.Concurrency; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			w.loop(ctx, fmt.Sprintf("%s/%d", w.name, n))
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.reap(ctx)
+	}()
+
+	wg.Wait()
+}
+
+func (w *Worker) loop(ctx context.Context, name string) {
+	for {
+		// Mientras haya trabajo vencido se sigue sin esperar.
+		for ctx.Err() == nil {
+			job, err := w.repo.Claim(name, time.Now())
+			if err != nil {
+				log.Printf("[Job] Claim failed: %v", err)
+				break
+			}
+			if job == nil {
+				break
+			}
+			w.process(name, job.ID, job.Type, job.Payload, job.Attempts, job.MaxAttempts)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(w.config.PollInterval):
+		}
+	}
+}
+
+func (w *Worker) process(name string, id uint, jobType string, payload []byte, attempts, maxAttempts int) {
+	handler, ok := w.handlers[jobType]
+	if !ok {
+		w.fail(name, id, "No handler registered for "+jobType, nil)
+		return
+	}
+
+	// El trabajo en curso no se corta con el apagado del servidor, solo con su timeout.
+	ctx, cancel := context.WithTimeout(context.Background(), w.config.Timeout)
+	err := runHandler(ctx, handler, payload)
+	cancel()
+
+	if err == nil {
+		if err := w.repo.Complete(id, name, time.Now()); err != nil {
+			log.Printf("[Job] Could not complete job %d: %v", id, err)
+		}
+		return
+	}
+
+	log.Printf("[Job] %s job %d failed (attempt %d/%d): %v", jobType, id, attempts, maxAttempts, err)
+	if attempts >= maxAttempts {
+		w.fail(name, id, err.Error(), nil)
+		return
+	}
+	retryAt := time.Now().Add(backoff(attempts))
+	w.fail(name, id, err.Error(), &retryAt)
+}
+
+func (w *Worker) fail(name string, id uint, message string, retryAt *time.Time) {
+	if err := w.repo.Fail(id, name, message, retryAt); err != nil {
+		log.Printf("[Job] Could not update job %d: %v", id, err)
+	}
+}
+
+// runHandler convierte un panic del handler en un error para que el trabajo se reintente.
+func runHandler(ctx context.Context, handler jobports.Handler, payload []byte) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, payload)
+}
+
+// reap devuelve a la cola los trabajos de workers que murieron a mitad.
+func (w *Worker) reap(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Timeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := time.Now()
+			requeued, dead, err := w.repo.Requeue(now.Add(-2*w.config.Timeout), now)
+			if err != nil {
+				log.Printf("[Job] Requeue failed: %v", err)
+				continue
+			}
+			if requeued > 0 {
+				log.Printf("[Job] Requeued %d stale jobs", requeued)
+			}
+			if dead > 0 {
+				log.Printf("[Job] Marked %d stale jobs without attempts left as dead", dead)
+			}
+		}
+	}
+}
+
+// backoff crece exponencialmente desde 10s hasta un máximo de una hora.
+func backoff(attempts int) time.Duration {
+	delay := 10 * time.Second
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= time.Hour {
+			return time.Hour
+		}
+	}
+	return delay
+}
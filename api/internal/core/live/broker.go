@@ -0,0 +1,71 @@
+package live
+
+import (
+	"sync"
+
+	"melodiapp/models"
+)
+
+// broker reparte los cambios de estado a los clientes conectados de cada servicio.
+// Vive en memoria: los cambios hechos en otras instancias llegan por el LiveBus.
+type broker struct {
+	mu          sync.Mutex
+	subscribers map[uint]map[chan *models.LiveState]struct{}
+}
+
+func newBroker() *broker {
+	return &broker{subscribers: make(map[uint]map[chan *models.LiveState]struct{})}
+}
+
+func (b *broker) subscribe(serviceID uint) (chan *models.LiveState, func()) {
+	ch := make(chan *models.LiveState, 1)
+
+	b.mu.Lock()
+	if b.subscribers[serviceID] == nil {
+		b.subscribers[serviceID] = make(map[chan *models.LiveState]struct{})
+	}
+	b.subscribers[serviceID][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	cancel := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers[serviceID], ch)
+			if len(b.subscribers[serviceID]) == 0 {
+				delete(b.subscribers, serviceID)
+			}
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, cancel
+}
+
+// watching indica si algún cliente de esta instancia sigue el servicio.
+func (b *broker) watching(serviceID uint) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers[serviceID]) > 0
+}
+
+// publish nunca bloquea: si un cliente no leyó el estado anterior, se
+// reemplaza por el nuevo, que es el único que importa.
+func (b *broker) publish(state *models.LiveState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers[state.ServiceID] {
+		select {
+		case ch <- state:
+		default:
+			select {
+			case <-ch:
+			default:
+			}
+			select {
+			case ch <- state:
+			default:
+			}
+		}
+	}
+}
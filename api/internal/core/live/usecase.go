@@ -0,0 +1,225 @@
+package live
+
+import (
+	"context"
+	"log"
+	"time"
+
+	liveports "melodiapp/internal/ports/live"
+	runsheetports "melodiapp/internal/ports/runsheet"
+	serviceuserports "melodiapp/internal/ports/serviceuser"
+	"melodiapp/models"
+)
+
+// listenRetry es cuánto se espera para volver a escuchar si se corta el LiveBus.
+const listenRetry = 5 * time.Second
+
+type Service struct {
+	repo            liveports.LiveSessionRepository
+	bus             liveports.LiveBus
+	runSheet        runsheetports.RunSheetService
+	serviceUserRepo serviceuserports.ServiceUserRepository
+	broker          *broker
+}
+
+func NewService(
+	repo liveports.LiveSessionRepository,
+	bus liveports.LiveBus,
+	runSheet runsheetports.RunSheetService,
+	serviceUserRepo serviceuserports.ServiceUserRepository,
+) *Service {
+	return &Service{
+		repo:            repo,
+		bus:             bus,
+		runSheet:        runSheet,
+		serviceUserRepo: serviceUserRepo,
+		broker:          newBroker(),
+	}
+}
+
+// canOperate permite mover el orden a los admins y a quienes sirven en el servicio.
+func (s *Service) canOperate(serviceID uint, user *models.User) error {
+	if user.Role == "admin" {
+		return nil
+	}
+	assignment, err := s.serviceUserRepo.GetAssignment(serviceID, user.ID)
+	if err != nil {
+		return err
+	}
+	if assignment == nil || !assignment.IsActive() {
+		return models.ErrForbidden
+	}
+	return nil
+}
+
+// Start abre (o reinicia) la sesión en vivo en el primer ítem del orden.
+func (s *Service) Start(serviceID uint, operator *models.User) (*models.LiveState, error) {
+	if err := s.canOperate(serviceID, operator); err != nil {
+		return nil, err
+	}
+
+	sheet, err := s.runSheet.Get(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(sheet.Items) == 0 {
+		return nil, models.Invalid("run_sheet_empty", "Run sheet is empty")
+	}
+
+	existing, err := s.repo.GetByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	session := &models.LiveSession{
+		ServiceID:     serviceID,
+		CurrentIndex:  0,
+		StartedAt:     now,
+		ItemStartedAt: now,
+		OperatorID:    operator.ID,
+		Version:       1,
+	}
+	if existing == nil {
+		if err := s.repo.Save(session); err != nil {
+			return nil, err
+		}
+		return s.publish(session, sheet), nil
+	}
+	// Reiniciar también compite con los movimientos de otros operadores.
+	session.Version = existing.Version
+	if err := s.repo.Update(session); err != nil {
+		return nil, err
+	}
+	return s.publish(session, sheet), nil
+}
+
+func (s *Service) Advance(serviceID uint, operator *models.User) (*models.LiveState, error) {
+	return s.move(serviceID, operator, func(current int) int { return current + 1 })
+}
+
+func (s *Service) Rewind(serviceID uint, operator *models.User) (*models.LiveState, error) {
+	return s.move(serviceID, operator, func(current int) int { return current - 1 })
+}
+
+func (s *Service) GoTo(serviceID uint, index int, operator *models.User) (*models.LiveState, error) {
+	return s.move(serviceID, operator, func(int) int { return index })
+}
+
+func (s *Service) move(serviceID uint, operator *models.User, next func(current int) int) (*models.LiveState, error) {
+	if err := s.canOperate(serviceID, operator); err != nil {
+		return nil, err
+	}
+
+	session, err := s.activeSession(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	sheet, err := s.runSheet.Get(serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	index := next(session.CurrentIndex)
+	if index < 0 || index >= len(sheet.Items) {
+		return nil, models.Invalid("no_more_items", "No more items in that direction")
+	}
+
+	session.CurrentIndex = index
+	session.ItemStartedAt = time.Now()
+	session.OperatorID = operator.ID
+	if err := s.repo.Update(session); err != nil {
+		return nil, err
+	}
+	return s.publish(session, sheet), nil
+}
+
+func (s *Service) End(serviceID uint, operator *models.User) (*models.LiveState, error) {
+	if err := s.canOperate(serviceID, operator); err != nil {
+		return nil, err
+	}
+
+	session, err := s.activeSession(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	sheet, err := s.runSheet.Get(serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session.EndedAt = &now
+	session.OperatorID = operator.ID
+	if err := s.repo.Update(session); err != nil {
+		return nil, err
+	}
+	return s.publish(session, sheet), nil
+}
+
+// State devuelve el estado actual con el temporizador calculado al momento.
+func (s *Service) State(serviceID uint) (*models.LiveState, error) {
+	session, err := s.repo.GetByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil {
+		return nil, models.NotFound("live_session_not_found", "Live session not found")
+	}
+	sheet, err := s.runSheet.Get(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	return models.BuildLiveState(session, sheet, time.Now()), nil
+}
+
+func (s *Service) Subscribe(serviceID uint) (<-chan *models.LiveState, func()) {
+	return s.broker.subscribe(serviceID)
+}
+
+func (s *Service) activeSession(serviceID uint) (*models.LiveSession, error) {
+	session, err := s.repo.GetByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if session == nil || !session.IsActive() {
+		return nil, models.Conflict("live_session_not_started", "Live session not started")
+	}
+	return session, nil
+}
+
+// publish avisa a los clientes de esta instancia y, por el LiveBus, a las demás.
+func (s *Service) publish(session *models.LiveSession, sheet *models.RunSheet) *models.LiveState {
+	state := models.BuildLiveState(session, sheet, time.Now())
+	s.broker.publish(state)
+	if err := s.bus.Publish(session.ServiceID); err != nil {
+		log.Printf("[Live] Error publishing service %d: %v", session.ServiceID, err)
+	}
+	return state
+}
+
+// Listen reenvía a los clientes de esta instancia los cambios hechos en otras.
+// Si se corta la conexión vuelve a escuchar después de listenRetry.
+func (s *Service) Listen(ctx context.Context) {
+	for {
+		err := s.bus.Listen(ctx, func(serviceID uint) {
+			if !s.broker.watching(serviceID) {
+				return
+			}
+			state, err := s.State(serviceID)
+			if err != nil {
+				log.Printf("[Live] Error loading state for service %d: %v", serviceID, err)
+				return
+			}
+			s.broker.publish(state)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("[Live] Listener stopped, retrying: %v", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetry):
+		}
+	}
+}
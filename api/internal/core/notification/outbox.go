@@ -0,0 +1,42 @@
+package notification
+
+import (
+	"log"
+
+	notificationports "melodiapp/internal/ports/notification"
+	"melodiapp/models"
+)
+
+type pendingNotification struct {
+	event   string
+	userIDs []uint
+	message models.NotificationMessage
+}
+
+// Outbox junta las notificaciones generadas dentro de una transacción para
+// mandarlas recién cuando se confirma. Si se revierte, basta con no llamar Flush.
+type Outbox struct {
+	target  notificationports.Notifier
+	pending []pendingNotification
+}
+
+func NewOutbox(target notificationports.Notifier) *Outbox {
+	return &Outbox{target: target}
+}
+
+func (o *Outbox) Notify(event string, userIDs []uint, message models.NotificationMessage) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	o.pending = append(o.pending, pendingNotification{event: event, userIDs: userIDs, message: message})
+	return nil
+}
+
+func (o *Outbox) Flush() {
+	for _, n := range o.pending {
+		if err := o.target.Notify(n.event, n.userIDs, n.message); err != nil {
+			log.Printf("[Notification] Error delivering %s after commit: %v", n.event, err)
+		}
+	}
+	o.pending = nil
+}
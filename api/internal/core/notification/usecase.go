@@ -0,0 +1,200 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	jobports "melodiapp/internal/ports/job"
+	notificationports "melodiapp/internal/ports/notification"
+	userports "melodiapp/internal/ports/user"
+	"melodiapp/models"
+)
+
+// DeliverJob es el tipo de trabajo que entrega una notificación por un canal externo.
+const DeliverJob = "notification.deliver"
+
+type deliveryPayload struct {
+	Channel      string              `json:"channel"`
+	Notification models.Notification `json:"notification"`
+}
+
+var errInvalidEvent = models.Invalid("invalid_notification_event", "Invalid notification event")
+
+type Service struct {
+	repo     notificationports.NotificationRepository
+	userRepo userports.UserRepository
+	jobs     jobports.Enqueuer
+	channels []notificationports.Channel
+}
+
+func NewService(
+	repo notificationports.NotificationRepository,
+	userRepo userports.UserRepository,
+	jobs jobports.Enqueuer,
+	channels ...notificationports.Channel,
+) *Service {
+	return &Service{repo: repo, userRepo: userRepo, jobs: jobs, channels: channels}
+}
+
+// Notify guarda la notificación en el inbox de cada usuario y encola un
+// trabajo por cada canal externo que tenga habilitado. Así un email caído no
+// rompe la operación que avisa y se reintenta solo.
+func (s *Service) Notify(event string, userIDs []uint, message models.NotificationMessage) error {
+	if !models.IsNotificationEvent(event) {
+		return errInvalidEvent
+	}
+
+	unique := make([]uint, 0, len(userIDs))
+	seen := make(map[uint]bool, len(userIDs))
+	for _, uid := range userIDs {
+		if uid != 0 && !seen[uid] {
+			seen[uid] = true
+			unique = append(unique, uid)
+		}
+	}
+	if len(unique) == 0 {
+		return nil
+	}
+
+	prefs, err := s.preferencesFor(unique, event)
+	if err != nil {
+		return err
+	}
+
+	inbox := []models.Notification{}
+	var external []models.Notification
+	for _, uid := range unique {
+		n := models.Notification{
+			UserID:    uid,
+			Event:     event,
+			Title:     message.Title,
+			Body:      message.Body,
+			ServiceID: message.ServiceID,
+			Link:      message.Link,
+		}
+		if prefs[uid].InApp {
+			inbox = append(inbox, n)
+		}
+		external = append(external, n)
+	}
+	if err := s.repo.Create(inbox); err != nil {
+		return err
+	}
+
+	s.enqueueDeliveries(external, prefs)
+	return nil
+}
+
+func (s *Service) enqueueDeliveries(notifications []models.Notification, prefs map[uint]models.NotificationPreference) {
+	for _, n := range notifications {
+		for _, ch := range s.channels {
+			if !ch.Enabled() || !prefs[n.UserID].Allows(ch.Name()) {
+				continue
+			}
+			payload := deliveryPayload{Channel: ch.Name(), Notification: n}
+			if _, err := s.jobs.Enqueue(DeliverJob, payload, models.JobOptions{}); err != nil {
+				log.Printf("[Notification] Could not enqueue %s delivery to user %d: %v", ch.Name(), n.UserID, err)
+			}
+		}
+	}
+}
+
+// Deliver es el handler de DeliverJob.
+func (s *Service) Deliver(ctx context.Context, data []byte) error {
+	var payload deliveryPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+
+	var channel notificationports.Channel
+	for _, ch := range s.channels {
+		if ch.Name() == payload.Channel {
+			channel = ch
+		}
+	}
+	if channel == nil || !channel.Enabled() {
+		return fmt.Errorf("channel %s is not available", payload.Channel)
+	}
+
+	user, err := s.userRepo.GetUserByUintID(payload.Notification.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		// El usuario ya no existe: no hay a quién entregar.
+		return nil
+	}
+	return channel.Send(user, &payload.Notification)
+}
+
+func (s *Service) preferencesFor(userIDs []uint, event string) (map[uint]models.NotificationPreference, error) {
+	saved, err := s.repo.ListPreferences(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	prefs := make(map[uint]models.NotificationPreference, len(userIDs))
+	for _, uid := range userIDs {
+		prefs[uid] = models.DefaultNotificationPreference(uid, event)
+	}
+	for _, p := range saved {
+		if p.Event == event {
+			prefs[p.UserID] = p
+		}
+	}
+	return prefs, nil
+}
+
+func (s *Service) List(userID uint, unreadOnly bool, limit int) ([]models.Notification, error) {
+	return s.repo.ListByUser(userID, unreadOnly, limit)
+}
+
+func (s *Service) CountUnread(userID uint) (int64, error) {
+	return s.repo.CountUnread(userID)
+}
+
+func (s *Service) MarkRead(userID uint, ids []uint) error {
+	return s.repo.MarkRead(userID, ids)
+}
+
+// Preferences devuelve una preferencia por evento, completando con los valores por defecto.
+func (s *Service) Preferences(userID uint) ([]models.NotificationPreference, error) {
+	saved, err := s.repo.ListPreferences([]uint{userID})
+	if err != nil {
+		return nil, err
+	}
+	byEvent := make(map[string]models.NotificationPreference, len(saved))
+	for _, p := range saved {
+		byEvent[p.Event] = p
+	}
+
+	prefs := make([]models.NotificationPreference, 0, len(models.NotificationEvents))
+	for _, event := range models.NotificationEvents {
+		if p, ok := byEvent[event]; ok {
+			prefs = append(prefs, p)
+		} else {
+			prefs = append(prefs, models.DefaultNotificationPreference(userID, event))
+		}
+	}
+	return prefs, nil
+}
+
+func (s *Service) UpdatePreferences(userID uint, prefs []models.NotificationPreference) ([]models.NotificationPreference, error) {
+	seen := make(map[string]bool, len(prefs))
+	for i := range prefs {
+		prefs[i].Event = strings.TrimSpace(prefs[i].Event)
+		if !models.IsNotificationEvent(prefs[i].Event) {
+			return nil, errInvalidEvent
+		}
+		if seen[prefs[i].Event] {
+			return nil, models.Invalid("duplicated_notification_event", "Duplicated notification event")
+		}
+		seen[prefs[i].Event] = true
+	}
+	if err := s.repo.SavePreferences(userID, prefs); err != nil {
+		return nil, err
+	}
+	return s.Preferences(userID)
+}
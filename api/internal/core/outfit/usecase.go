@@ -0,0 +1,100 @@
+package outfit
+
+import (
+	"strings"
+
+	outfitports "melodiapp/internal/ports/outfit"
+	"melodiapp/models"
+)
+
+type Service struct {
+	repo outfitports.OutfitRepository
+}
+
+func NewService(repo outfitports.OutfitRepository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) GetAll() ([]models.Outfit, error) {
+	return s.repo.GetAll()
+}
+
+func (s *Service) GetByID(id uint) (*models.Outfit, error) {
+	return s.repo.GetByID(id)
+}
+
+func validate(outfit *models.Outfit) error {
+	outfit.Name = strings.TrimSpace(outfit.Name)
+	if outfit.Name == "" || len(outfit.Colors) == 0 {
+		return models.ErrIncompleteFields
+	}
+	colors, err := outfit.Colors.Normalize()
+	if err != nil {
+		return err
+	}
+	outfit.Colors = colors
+	return nil
+}
+
+func (s *Service) Create(outfit *models.Outfit) (*models.Outfit, error) {
+	if err := validate(outfit); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(outfit); err != nil {
+		return nil, err
+	}
+	return outfit, nil
+}
+
+func (s *Service) Update(id uint, input *models.Outfit) (*models.Outfit, error) {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, models.ErrOutfitNotFound
+	}
+
+	existing.Name = input.Name
+	existing.Colors = input.Colors
+	existing.Description = input.Description
+	if input.ImageURL != "" {
+		existing.ImageURL = input.ImageURL
+	}
+	if err := validate(existing); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (s *Service) SetImage(id uint, imageURL string) (*models.Outfit, error) {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, models.ErrOutfitNotFound
+	}
+
+	existing.ImageURL = imageURL
+	if err := s.repo.Update(existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// Delete no permite borrar un outfit que todavía usa algún servicio.
+func (s *Service) Delete(id uint) error {
+	count, err := s.repo.CountServices(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.Conflict("outfit_in_use", "Outfit is used by services")
+	}
+	return s.repo.DeleteByID(id)
+}
@@ -0,0 +1,118 @@
+package position
+
+import (
+	"strings"
+
+	positionports "melodiapp/internal/ports/position"
+	serviceuserports "melodiapp/internal/ports/serviceuser"
+	"melodiapp/models"
+)
+
+type Service struct {
+	repo            positionports.PositionRepository
+	serviceUserRepo serviceuserports.ServiceUserRepository
+}
+
+func NewService(repo positionports.PositionRepository, serviceUserRepo serviceuserports.ServiceUserRepository) *Service {
+	return &Service{repo: repo, serviceUserRepo: serviceUserRepo}
+}
+
+func (s *Service) GetAll() ([]models.Position, error) {
+	return s.repo.GetAll()
+}
+
+func (s *Service) GetByID(id uint) (*models.Position, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *Service) Create(position *models.Position) (*models.Position, error) {
+	position.Name = strings.TrimSpace(position.Name)
+	if position.Name == "" {
+		return nil, models.ErrIncompleteFields
+	}
+	if err := s.repo.Create(position); err != nil {
+		return nil, err
+	}
+	return position, nil
+}
+
+func (s *Service) Update(id uint, input *models.Position) (*models.Position, error) {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, models.ErrPositionNotFound
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, models.ErrIncompleteFields
+	}
+	existing.Name = name
+	existing.Description = input.Description
+
+	if err := s.repo.Update(existing); err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// Delete no permite borrar un puesto que todavía tiene cupos o códigos de vestimenta.
+func (s *Service) Delete(id uint) error {
+	count, err := s.repo.CountUsage(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.Conflict("position_in_use", "Position is in use")
+	}
+	return s.repo.DeleteByID(id)
+}
+
+// SetServiceSlots define cuántas personas requiere cada puesto en el servicio.
+func (s *Service) SetServiceSlots(serviceID uint, slots []models.ServicePosition) error {
+	ids := make([]uint, 0, len(slots))
+	seen := make(map[uint]bool)
+	for _, sp := range slots {
+		if sp.PositionID == 0 || sp.Required < 0 {
+			return models.Invalid("invalid_position_slot", "Invalid position slot")
+		}
+		if seen[sp.PositionID] {
+			return models.Invalid("duplicated_position", "Duplicated position")
+		}
+		seen[sp.PositionID] = true
+		ids = append(ids, sp.PositionID)
+	}
+
+	positions, err := s.repo.GetByIDs(ids)
+	if err != nil {
+		return err
+	}
+	found := make([]uint, 0, len(positions))
+	for _, p := range positions {
+		found = append(found, p.ID)
+	}
+	if missing := models.MissingIDs(ids, found); len(missing) > 0 {
+		return models.MissingReferences("Position", missing)
+	}
+
+	return s.repo.ReplaceSlots(serviceID, slots)
+}
+
+// ServiceBreakdown devuelve los cupos del servicio con los usuarios que los cubren.
+func (s *Service) ServiceBreakdown(serviceID uint) ([]models.PositionSlot, error) {
+	slots, err := s.repo.ListSlotsByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := s.serviceUserRepo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return models.BuildPositionSlots(positions, slots, assignments), nil
+}
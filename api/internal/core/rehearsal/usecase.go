@@ -0,0 +1,212 @@
+package rehearsal
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	rehearsalports "melodiapp/internal/ports/rehearsal"
+	serviceports "melodiapp/internal/ports/service"
+	servicesongports "melodiapp/internal/ports/servicesong"
+	serviceuserports "melodiapp/internal/ports/serviceuser"
+	"melodiapp/models"
+)
+
+type Service struct {
+	repo            rehearsalports.RehearsalRepository
+	serviceRepo     serviceports.ServiceRepository
+	serviceUserRepo serviceuserports.ServiceUserRepository
+	serviceSongRepo servicesongports.ServiceSongRepository
+}
+
+func NewService(
+	repo rehearsalports.RehearsalRepository,
+	serviceRepo serviceports.ServiceRepository,
+	serviceUserRepo serviceuserports.ServiceUserRepository,
+	serviceSongRepo servicesongports.ServiceSongRepository,
+) *Service {
+	return &Service{
+		repo:            repo,
+		serviceRepo:     serviceRepo,
+		serviceUserRepo: serviceUserRepo,
+		serviceSongRepo: serviceSongRepo,
+	}
+}
+
+func (s *Service) List(from, to time.Time) ([]models.Rehearsal, error) {
+	return s.repo.List(from, to)
+}
+
+func (s *Service) ListByUser(userID uint) ([]models.Rehearsal, error) {
+	return s.repo.ListByUser(userID)
+}
+
+// GetByID devuelve el ensayo con el repertorio unido de sus servicios.
+func (s *Service) GetByID(id uint) (*models.RehearsalDetail, error) {
+	rehearsal, err := s.repo.GetByID(id)
+	if err != nil || rehearsal == nil {
+		return nil, err
+	}
+
+	songIDs := []uint{}
+	seen := make(map[uint]bool)
+	for _, sid := range rehearsal.ServiceIDs() {
+		songs, err := s.serviceSongRepo.ListByService(sid)
+		if err != nil {
+			return nil, err
+		}
+		for _, ss := range songs {
+			if !seen[ss.SongID] {
+				seen[ss.SongID] = true
+				songIDs = append(songIDs, ss.SongID)
+			}
+		}
+	}
+	sort.Slice(songIDs, func(i, j int) bool { return songIDs[i] < songIDs[j] })
+
+	return &models.RehearsalDetail{Rehearsal: *rehearsal, SongIDs: songIDs}, nil
+}
+
+func (s *Service) Create(rehearsal *models.Rehearsal, serviceIDs []uint) (*models.RehearsalDetail, error) {
+	ids, err := s.prepare(rehearsal, serviceIDs)
+	if err != nil {
+		return nil, err
+	}
+	rehearsal.ID = 0
+	if err := s.repo.Create(rehearsal, ids); err != nil {
+		return nil, err
+	}
+	return s.SyncAttendees(rehearsal.ID)
+}
+
+func (s *Service) Update(id uint, input *models.Rehearsal, serviceIDs []uint) (*models.RehearsalDetail, error) {
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, models.ErrRehearsalNotFound
+	}
+	ids, err := s.prepare(input, serviceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	current.Name = input.Name
+	current.StartTime = input.StartTime
+	current.EndTime = input.EndTime
+	current.Location = input.Location
+	current.Notes = input.Notes
+	if err := s.repo.Update(current, ids); err != nil {
+		return nil, err
+	}
+	return s.SyncAttendees(id)
+}
+
+// prepare valida el ensayo y que todos los servicios existan.
+func (s *Service) prepare(rehearsal *models.Rehearsal, serviceIDs []uint) ([]uint, error) {
+	rehearsal.Name = strings.TrimSpace(rehearsal.Name)
+	if err := rehearsal.Validate(); err != nil {
+		return nil, err
+	}
+	if len(serviceIDs) == 0 {
+		return nil, models.Invalid("rehearsal_without_services", "A rehearsal needs at least one service")
+	}
+
+	ids := make([]uint, 0, len(serviceIDs))
+	seen := make(map[uint]bool)
+	for _, id := range serviceIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	services, err := s.serviceRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(services) != len(ids) {
+		return nil, models.ErrServiceNotFound
+	}
+
+	if rehearsal.Name == "" {
+		names := make([]string, 0, len(services))
+		for _, svc := range services {
+			names = append(names, svc.Name)
+		}
+		rehearsal.Name = "Ensayo: " + strings.Join(names, ", ")
+	}
+	return ids, nil
+}
+
+func (s *Service) Delete(id uint) error {
+	current, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return models.ErrRehearsalNotFound
+	}
+	return s.repo.DeleteByID(id)
+}
+
+// SyncAttendees recalcula los asistentes a partir del equipo activo de los
+// servicios del ensayo. Quienes ya estaban conservan su respuesta.
+func (s *Service) SyncAttendees(id uint) (*models.RehearsalDetail, error) {
+	rehearsal, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if rehearsal == nil {
+		return nil, models.ErrRehearsalNotFound
+	}
+
+	userIDs := []uint{}
+	seen := make(map[uint]bool)
+	for _, sid := range rehearsal.ServiceIDs() {
+		team, err := s.serviceUserRepo.ListByService(sid)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range team {
+			if member.IsActive() && !seen[member.UserID] {
+				seen[member.UserID] = true
+				userIDs = append(userIDs, member.UserID)
+			}
+		}
+	}
+
+	if err := s.repo.SyncAttendees(id, userIDs); err != nil {
+		return nil, err
+	}
+	return s.GetByID(id)
+}
+
+// RSVP registra la respuesta del integrante: accepted, declined o pending.
+func (s *Service) RSVP(id uint, userID uint, response string, note string) (*models.RehearsalAttendee, error) {
+	switch response {
+	case models.StatusPending, models.StatusAccepted, models.StatusDeclined:
+	default:
+		return nil, models.Invalid("invalid_rsvp", "Invalid RSVP")
+	}
+
+	attendee, err := s.repo.GetAttendee(id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if attendee == nil {
+		return nil, models.Invalid("not_invited", "User is not invited to the rehearsal")
+	}
+
+	now := time.Now()
+	attendee.RSVP = response
+	attendee.RSVPNote = strings.TrimSpace(note)
+	attendee.RespondedAt = &now
+	if response == models.StatusPending {
+		attendee.RespondedAt = nil
+	}
+	if err := s.repo.UpdateAttendee(attendee); err != nil {
+		return nil, err
+	}
+	return attendee, nil
+}
@@ -0,0 +1,312 @@
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	notificationports "melodiapp/internal/ports/notification"
+	positionports "melodiapp/internal/ports/position"
+	rehearsalports "melodiapp/internal/ports/rehearsal"
+	reminderports "melodiapp/internal/ports/reminder"
+	serviceports "melodiapp/internal/ports/service"
+	serviceuserports "melodiapp/internal/ports/serviceuser"
+	userports "melodiapp/internal/ports/user"
+	"melodiapp/models"
+)
+
+// lockKey es la llave del advisory lock compartida por todas las instancias.
+const lockKey int64 = 0x6d656c6f01
+
+// Config define cuándo se manda cada recordatorio.
+type Config struct {
+	Interval time.Duration
+	// Lead es cuánto antes de un servicio o ensayo se recuerda a los aceptados.
+	Lead time.Duration
+	// PendingNudgeDays es a cuántos días del servicio se insiste a los pendientes.
+	PendingNudgeDays int
+	// DigestDays es cuántos días hacia adelante revisa el resumen de puestos sin cubrir.
+	DigestDays int
+}
+
+// ConfigFromEnv lee REMINDER_INTERVAL, REMINDER_LEAD (duraciones de Go, p. ej. "24h"),
+// REMINDER_PENDING_DAYS y REMINDER_DIGEST_DAYS.
+func ConfigFromEnv() Config {
+	config := Config{
+		Interval:         5 * time.Minute,
+		Lead:             24 * time.Hour,
+		PendingNudgeDays: 3,
+		DigestDays:       7,
+	}
+	if d, err := time.ParseDuration(os.Getenv("REMINDER_INTERVAL")); err == nil && d > 0 {
+		config.Interval = d
+	}
+	if d, err := time.ParseDuration(os.Getenv("REMINDER_LEAD")); err == nil && d > 0 {
+		config.Lead = d
+	}
+	if n, err := strconv.Atoi(os.Getenv("REMINDER_PENDING_DAYS")); err == nil && n > 0 {
+		config.PendingNudgeDays = n
+	}
+	if n, err := strconv.Atoi(os.Getenv("REMINDER_DIGEST_DAYS")); err == nil && n > 0 {
+		config.DigestDays = n
+	}
+	return config
+}
+
+type Service struct {
+	config          Config
+	logRepo         reminderports.ReminderLogRepository
+	locker          reminderports.Locker
+	serviceRepo     serviceports.ServiceRepository
+	serviceUserRepo serviceuserports.ServiceUserRepository
+	rehearsalRepo   rehearsalports.RehearsalRepository
+	positionRepo    positionports.PositionRepository
+	userRepo        userports.UserRepository
+	notifier        notificationports.Notifier
+}
+
+func NewService(
+	config Config,
+	logRepo reminderports.ReminderLogRepository,
+	locker reminderports.Locker,
+	serviceRepo serviceports.ServiceRepository,
+	serviceUserRepo serviceuserports.ServiceUserRepository,
+	rehearsalRepo rehearsalports.RehearsalRepository,
+	positionRepo positionports.PositionRepository,
+	userRepo userports.UserRepository,
+	notifier notificationports.Notifier,
+) *Service {
+	return &Service{
+		config:          config,
+		logRepo:         logRepo,
+		locker:          locker,
+		serviceRepo:     serviceRepo,
+		serviceUserRepo: serviceUserRepo,
+		rehearsalRepo:   rehearsalRepo,
+		positionRepo:    positionRepo,
+		userRepo:        userRepo,
+		notifier:        notifier,
+	}
+}
+
+// Start corre RunOnce cada Interval hasta que se cancele ctx.
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.RunOnce(ctx, time.Now()); err != nil {
+			log.Printf("[Reminder] Run failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce hace una pasada completa. Solo una instancia a la vez la ejecuta;
+// las demás la saltan sin esperar.
+func (s *Service) RunOnce(ctx context.Context, now time.Time) error {
+	release, acquired, err := s.locker.TryLock(ctx, lockKey)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return nil
+	}
+	defer release()
+
+	services, err := s.serviceRepo.GetAll()
+	if err != nil {
+		return err
+	}
+	if err := s.remindServices(services, now); err != nil {
+		return err
+	}
+	if err := s.remindRehearsals(now); err != nil {
+		return err
+	}
+	if err := s.nudgePending(services, now); err != nil {
+		return err
+	}
+	return s.digestUnfilled(services, now)
+}
+
+// send registra el envío y notifica solo si no se había mandado antes.
+// Se registra primero: ante una caída se pierde un aviso antes que duplicarlo.
+func (s *Service) send(kind, eventType string, eventID uint, userIDs []uint, event string, message models.NotificationMessage, now time.Time) error {
+	var pending []uint
+	for _, uid := range userIDs {
+		recorded, err := s.logRepo.Record(&models.ReminderLog{
+			Kind:      kind,
+			EventType: eventType,
+			EventID:   eventID,
+			UserID:    uid,
+			SentAt:    now,
+		})
+		if err != nil {
+			return err
+		}
+		if recorded {
+			pending = append(pending, uid)
+		}
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	return s.notifier.Notify(event, pending, message)
+}
+
+func (s *Service) remindServices(services []models.Service, now time.Time) error {
+	for i := range services {
+		svc := &services[i]
+		start, _, err := svc.Window()
+		if err != nil || start.Before(now) || start.After(now.Add(s.config.Lead)) {
+			continue
+		}
+
+		team, err := s.serviceUserRepo.ListByService(svc.ID)
+		if err != nil {
+			return err
+		}
+		var accepted []uint
+		for _, member := range team {
+			if member.Status == models.StatusAccepted {
+				accepted = append(accepted, member.UserID)
+			}
+		}
+
+		message := models.NotificationMessage{
+			Title:     "Recordatorio: " + svc.Name,
+			Body:      fmt.Sprintf("Sirves en %s el %s.", svc.Name, start.Format("02/01 15:04")),
+			ServiceID: svc.ID,
+		}
+		if err := s.send(models.ReminderUpcoming, models.EventService, svc.ID, accepted, models.NotifyReminder, message, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) remindRehearsals(now time.Time) error {
+	rehearsals, err := s.rehearsalRepo.List(now, now.Add(s.config.Lead))
+	if err != nil {
+		return err
+	}
+	for _, r := range rehearsals {
+		if r.StartTime.Before(now) {
+			continue
+		}
+		var accepted []uint
+		for _, a := range r.Attendees {
+			if a.RSVP == models.StatusAccepted {
+				accepted = append(accepted, a.UserID)
+			}
+		}
+
+		body := fmt.Sprintf("Ensayo %s el %s.", r.Name, r.StartTime.Format("02/01 15:04"))
+		if r.Location != "" {
+			body += " Lugar: " + r.Location + "."
+		}
+		message := models.NotificationMessage{Title: "Recordatorio: " + r.Name, Body: body}
+		if err := s.send(models.ReminderUpcoming, models.EventRehearsal, r.ID, accepted, models.NotifyReminder, message, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nudgePending insiste una vez a quienes siguen sin responder cuando faltan
+// PendingNudgeDays días o menos para el servicio.
+func (s *Service) nudgePending(services []models.Service, now time.Time) error {
+	horizon := now.AddDate(0, 0, s.config.PendingNudgeDays)
+	for i := range services {
+		svc := &services[i]
+		start, _, err := svc.Window()
+		if err != nil || start.Before(now) || start.After(horizon) {
+			continue
+		}
+
+		team, err := s.serviceUserRepo.ListByService(svc.ID)
+		if err != nil {
+			return err
+		}
+		var pending []uint
+		for _, member := range team {
+			if member.Status == models.StatusPending {
+				pending = append(pending, member.UserID)
+			}
+		}
+
+		message := models.NotificationMessage{
+			Title:     "Falta tu respuesta para " + svc.Name,
+			Body:      fmt.Sprintf("%s es el %s y todavía no confirmaste si puedes servir.", svc.Name, start.Format("02/01 15:04")),
+			ServiceID: svc.ID,
+		}
+		if err := s.send(models.ReminderPending, models.EventService, svc.ID, pending, models.NotifyPendingNudge, message, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// digestUnfilled manda a los admins, una vez por día, los puestos sin cubrir
+// de los próximos DigestDays días.
+func (s *Service) digestUnfilled(services []models.Service, now time.Time) error {
+	positions, err := s.positionRepo.GetAll()
+	if err != nil {
+		return err
+	}
+
+	horizon := now.AddDate(0, 0, s.config.DigestDays)
+	var lines []string
+	for i := range services {
+		svc := &services[i]
+		start, _, err := svc.Window()
+		if err != nil || start.Before(now) || start.After(horizon) {
+			continue
+		}
+		slots, err := s.positionRepo.ListSlotsByService(svc.ID)
+		if err != nil {
+			return err
+		}
+		if len(slots) == 0 {
+			continue
+		}
+		team, err := s.serviceUserRepo.ListByService(svc.ID)
+		if err != nil {
+			return err
+		}
+		for _, slot := range models.BuildPositionSlots(positions, slots, team) {
+			if slot.Unfilled > 0 {
+				lines = append(lines, fmt.Sprintf("%s (%s): faltan %d de %s", svc.Name, start.Format("02/01"), slot.Unfilled, slot.Name))
+			}
+		}
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+
+	users, err := s.userRepo.GetAllUsers()
+	if err != nil {
+		return err
+	}
+	var admins []uint
+	for _, u := range users {
+		if u.Role == "admin" {
+			admins = append(admins, u.ID)
+		}
+	}
+
+	day, _ := strconv.Atoi(now.Format("20060102"))
+	message := models.NotificationMessage{
+		Title: "Puestos sin cubrir",
+		Body:  strings.Join(lines, "\n"),
+	}
+	return s.send(models.ReminderDigest, models.EventDigest, uint(day), admins, models.NotifyUnfilledDigest, message, now)
+}
@@ -0,0 +1,207 @@
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"strconv"
+
+	auditports "melodiapp/internal/ports/audit"
+	revisionports "melodiapp/internal/ports/revision"
+	runsheetports "melodiapp/internal/ports/runsheet"
+	serviceports "melodiapp/internal/ports/service"
+	serviceoutfitports "melodiapp/internal/ports/serviceoutfit"
+	servicesongports "melodiapp/internal/ports/servicesong"
+	serviceuserports "melodiapp/internal/ports/serviceuser"
+	songports "melodiapp/internal/ports/song"
+	"melodiapp/models"
+)
+
+// volatileFields cambian en cada guardado y no forman parte de la revisión.
+var volatileFields = []string{"id", "created_at", "updated_at", "version"}
+
+// Tracker envuelve al Recorder de auditoría: además de auditar, guarda una
+// revisión nueva de la canción o del servicio afectado si su foto cambió.
+// Como las entradas de una transacción llegan recién después del commit, la
+// foto siempre refleja lo confirmado.
+type Tracker struct {
+	next              auditports.Recorder
+	repo              revisionports.RevisionRepository
+	songRepo          songports.SongRepository
+	serviceRepo       serviceports.ServiceRepository
+	serviceSongRepo   servicesongports.ServiceSongRepository
+	serviceUserRepo   serviceuserports.ServiceUserRepository
+	serviceOutfitRepo serviceoutfitports.ServiceOutfitRepository
+	runSheetRepo      runsheetports.RunSheetRepository
+}
+
+func NewTracker(
+	next auditports.Recorder,
+	repo revisionports.RevisionRepository,
+	songRepo songports.SongRepository,
+	serviceRepo serviceports.ServiceRepository,
+	serviceSongRepo servicesongports.ServiceSongRepository,
+	serviceUserRepo serviceuserports.ServiceUserRepository,
+	serviceOutfitRepo serviceoutfitports.ServiceOutfitRepository,
+	runSheetRepo runsheetports.RunSheetRepository,
+) *Tracker {
+	return &Tracker{
+		next:              next,
+		repo:              repo,
+		songRepo:          songRepo,
+		serviceRepo:       serviceRepo,
+		serviceSongRepo:   serviceSongRepo,
+		serviceUserRepo:   serviceUserRepo,
+		serviceOutfitRepo: serviceOutfitRepo,
+		runSheetRepo:      runSheetRepo,
+	}
+}
+
+func (t *Tracker) Record(ctx context.Context, entry models.AuditEntry) {
+	t.next.Record(ctx, entry)
+	if entry.Action == models.AuditDelete || entry.Action == models.AuditPurge {
+		return
+	}
+
+	switch entry.EntityType {
+	case models.ResourceSong:
+		t.capture(ctx, models.RevisionSong, entry.EntityID)
+	case models.ResourceService, models.AuditServiceSongs, models.AuditServiceUsers, models.AuditServiceOutfits:
+		t.capture(ctx, models.RevisionService, entry.ServiceID)
+	}
+}
+
+func (t *Tracker) capture(ctx context.Context, entityType string, entityID uint) {
+	if err := t.save(ctx, entityType, entityID); err != nil {
+		log.Printf("[Revision] Could not save revision of %s %d: %v", entityType, entityID, err)
+	}
+}
+
+func (t *Tracker) save(ctx context.Context, entityType string, entityID uint) error {
+	var value any
+	var err error
+	if entityType == models.RevisionSong {
+		value, err = t.song(entityID)
+	} else {
+		value, err = t.composition(entityID)
+	}
+	if err != nil || value == nil {
+		return err
+	}
+	snapshot, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = t.repo.CreateNext(&models.Revision{
+		EntityType: entityType,
+		EntityID:   entityID,
+		Snapshot:   snapshot,
+		ActorID:    models.AuditActorFrom(ctx).UserID,
+	})
+	return err
+}
+
+// song es la canción sin los campos volátiles.
+func (t *Tracker) song(songID uint) (map[string]any, error) {
+	song, err := t.songRepo.GetByID(strconv.FormatUint(uint64(songID), 10))
+	if err != nil || song == nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(song)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]any{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	for _, name := range volatileFields {
+		delete(fields, name)
+	}
+	return fields, nil
+}
+
+// composition arma la foto del servicio. El orden de las canciones sale del
+// orden del servicio; las que no están en él van al final con Order 0.
+func (t *Tracker) composition(serviceID uint) (*models.ServiceComposition, error) {
+	svc, err := t.serviceRepo.GetByID(strconv.FormatUint(uint64(serviceID), 10))
+	if err != nil || svc == nil {
+		return nil, err
+	}
+
+	serviceSongs, err := t.serviceSongRepo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	songIDs := make([]uint, 0, len(serviceSongs))
+	for _, ss := range serviceSongs {
+		songIDs = append(songIDs, ss.SongID)
+	}
+	songs, err := t.songRepo.GetByIDs(songIDs)
+	if err != nil {
+		return nil, err
+	}
+	items, err := t.runSheetRepo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	order := make(map[uint]int)
+	for _, item := range items {
+		if item.Type == models.RunItemSong && item.SongID != 0 && order[item.SongID] == 0 {
+			order[item.SongID] = len(order) + 1
+		}
+	}
+
+	composition := &models.ServiceComposition{
+		Name:      svc.Name,
+		StartTime: svc.StartTime,
+		EndTime:   svc.EndTime,
+		Songs:     make([]models.CompositionSong, 0, len(songs)),
+		Team:      []models.CompositionMember{},
+		OutfitIDs: []uint{},
+	}
+	for _, song := range songs {
+		composition.Songs = append(composition.Songs, models.CompositionSong{
+			SongID: song.ID,
+			Name:   song.Name,
+			Order:  order[song.ID],
+			Key:    song.SongKey,
+		})
+	}
+	sort.Slice(composition.Songs, func(i, j int) bool {
+		a, b := composition.Songs[i], composition.Songs[j]
+		if (a.Order == 0) != (b.Order == 0) {
+			return b.Order == 0
+		}
+		if a.Order != b.Order {
+			return a.Order < b.Order
+		}
+		return a.SongID < b.SongID
+	})
+
+	team, err := t.serviceUserRepo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, su := range team {
+		composition.Team = append(composition.Team, models.CompositionMember{
+			UserID:     su.UserID,
+			PositionID: su.PositionID,
+			Status:     su.Status,
+		})
+	}
+	sort.Slice(composition.Team, func(i, j int) bool { return composition.Team[i].UserID < composition.Team[j].UserID })
+
+	outfits, err := t.serviceOutfitRepo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, so := range outfits {
+		composition.OutfitIDs = append(composition.OutfitIDs, so.OutfitID)
+	}
+	sort.Slice(composition.OutfitIDs, func(i, j int) bool { return composition.OutfitIDs[i] < composition.OutfitIDs[j] })
+
+	return composition, nil
+}
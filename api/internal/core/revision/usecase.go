@@ -0,0 +1,181 @@
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strconv"
+
+	revisionports "melodiapp/internal/ports/revision"
+	serviceports "melodiapp/internal/ports/service"
+	songports "melodiapp/internal/ports/song"
+	"melodiapp/models"
+)
+
+var errInvalidEntity = models.Invalid("invalid_revision_entity", "Invalid revision entity")
+
+// Service lista y compara revisiones y revierte a una de ellas. Revertir pasa
+// por los casos de uso de canciones y servicios, así la reversión también se
+// audita, avisa al equipo y queda como una revisión nueva.
+type Service struct {
+	repo     revisionports.RevisionRepository
+	songs    songports.SongService
+	services serviceports.ServiceService
+}
+
+func NewService(repo revisionports.RevisionRepository, songs songports.SongService, services serviceports.ServiceService) *Service {
+	return &Service{repo: repo, songs: songs, services: services}
+}
+
+func (s *Service) List(entityType string, entityID uint) ([]models.Revision, error) {
+	if !models.IsRevisionEntity(entityType) {
+		return nil, errInvalidEntity
+	}
+	return s.repo.List(entityType, entityID)
+}
+
+func (s *Service) get(entityType string, entityID uint, version int) (*models.Revision, error) {
+	if !models.IsRevisionEntity(entityType) {
+		return nil, errInvalidEntity
+	}
+	revision, err := s.repo.GetByVersion(entityType, entityID, version)
+	if err != nil {
+		return nil, err
+	}
+	if revision == nil {
+		return nil, models.NotFound("revision_not_found", "Revision not found")
+	}
+	return revision, nil
+}
+
+// Diff compara la revisión from contra la revisión to.
+func (s *Service) Diff(entityType string, entityID uint, from int, to int) (*models.RevisionDiff, error) {
+	before, err := s.get(entityType, entityID, from)
+	if err != nil {
+		return nil, err
+	}
+	after, err := s.get(entityType, entityID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &models.RevisionDiff{EntityType: entityType, EntityID: entityID, From: from, To: to}
+	if entityType == models.RevisionSong {
+		var a, b map[string]any
+		if err := json.Unmarshal(before.Snapshot, &a); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(after.Snapshot, &b); err != nil {
+			return nil, err
+		}
+		diff.Fields = diffFields(a, b)
+		return diff, nil
+	}
+
+	var a, b models.ServiceComposition
+	if err := json.Unmarshal(before.Snapshot, &a); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(after.Snapshot, &b); err != nil {
+		return nil, err
+	}
+	diff.Fields = diffFields(
+		map[string]any{"name": a.Name, "start_time": a.StartTime, "end_time": a.EndTime},
+		map[string]any{"name": b.Name, "start_time": b.StartTime, "end_time": b.EndTime},
+	)
+	diff.Songs = diffItems(a.Songs, b.Songs, func(song models.CompositionSong) uint { return song.SongID })
+	diff.Team = diffItems(a.Team, b.Team, func(member models.CompositionMember) uint { return member.UserID })
+	diff.Outfits = diffItems(a.OutfitIDs, b.OutfitIDs, func(id uint) uint { return id })
+	return diff, nil
+}
+
+func diffFields(before, after map[string]any) map[string]models.RevisionChange {
+	changes := make(map[string]models.RevisionChange)
+	for name, value := range after {
+		if !reflect.DeepEqual(before[name], value) {
+			changes[name] = models.RevisionChange{From: before[name], To: value}
+		}
+	}
+	for name, value := range before {
+		if _, ok := after[name]; !ok {
+			changes[name] = models.RevisionChange{From: value, To: nil}
+		}
+	}
+	return changes
+}
+
+// diffItems compara dos listas usando key como identidad de cada elemento.
+func diffItems[T comparable](before, after []T, key func(T) uint) *models.RevisionItemsDiff {
+	diff := &models.RevisionItemsDiff{Added: []any{}, Removed: []any{}, Changed: []models.RevisionChange{}}
+	previous := make(map[uint]T, len(before))
+	for _, item := range before {
+		previous[key(item)] = item
+	}
+	present := make(map[uint]bool, len(after))
+	for _, item := range after {
+		present[key(item)] = true
+		old, ok := previous[key(item)]
+		if !ok {
+			diff.Added = append(diff.Added, item)
+		} else if old != item {
+			diff.Changed = append(diff.Changed, models.RevisionChange{From: old, To: item})
+		}
+	}
+	for _, item := range before {
+		if !present[key(item)] {
+			diff.Removed = append(diff.Removed, item)
+		}
+	}
+	return diff
+}
+
+// RevertSong vuelve los datos de la canción a los de la revisión.
+func (s *Service) RevertSong(ctx context.Context, songID uint, version int, expected uint) (*models.Song, error) {
+	revision, err := s.get(models.RevisionSong, songID, version)
+	if err != nil {
+		return nil, err
+	}
+	var song models.Song
+	if err := json.Unmarshal(revision.Snapshot, &song); err != nil {
+		return nil, err
+	}
+	song.Version = expected
+
+	return s.songs.Update(ctx, strconv.FormatUint(uint64(songID), 10), &song)
+}
+
+// RevertService vuelve el servicio, su repertorio, su equipo (con puestos) y
+// sus outfits a los de la revisión en una sola transacción. Los integrantes
+// que vuelven al equipo quedan pendientes de confirmar; el orden del servicio
+// no se toca.
+func (s *Service) RevertService(ctx context.Context, serviceID uint, version int, expected uint, override bool) (*models.ServiceDetail, []models.AssignmentConflict, error) {
+	revision, err := s.get(models.RevisionService, serviceID, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	var composition models.ServiceComposition
+	if err := json.Unmarshal(revision.Snapshot, &composition); err != nil {
+		return nil, nil, err
+	}
+
+	bundle := &models.ServiceBundle{
+		Service: models.Service{
+			Name:      composition.Name,
+			StartTime: composition.StartTime,
+			EndTime:   composition.EndTime,
+			Version:   expected,
+		},
+		Songs:    make([]uint, 0, len(composition.Songs)),
+		Users:    make([]models.UserAssignment, 0, len(composition.Team)),
+		Outfits:  append([]uint{}, composition.OutfitIDs...),
+		Override: override,
+	}
+	for _, song := range composition.Songs {
+		bundle.Songs = append(bundle.Songs, song.SongID)
+	}
+	for _, member := range composition.Team {
+		bundle.Users = append(bundle.Users, models.UserAssignment{UserID: member.UserID, PositionID: member.PositionID})
+	}
+
+	return s.services.SaveBundle(ctx, strconv.FormatUint(uint64(serviceID), 10), bundle)
+}
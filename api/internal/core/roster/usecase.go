@@ -0,0 +1,408 @@
+package roster
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	coreaudit "melodiapp/internal/core/audit"
+	corenotification "melodiapp/internal/core/notification"
+	coreserviceuser "melodiapp/internal/core/serviceuser"
+	attendanceports "melodiapp/internal/ports/attendance"
+	auditports "melodiapp/internal/ports/audit"
+	notificationports "melodiapp/internal/ports/notification"
+	positionports "melodiapp/internal/ports/position"
+	serviceports "melodiapp/internal/ports/service"
+	serviceuserports "melodiapp/internal/ports/serviceuser"
+	transactionports "melodiapp/internal/ports/transaction"
+	userports "melodiapp/internal/ports/user"
+	userblackoutports "melodiapp/internal/ports/userblackout"
+	"melodiapp/models"
+)
+
+// Pesos del puntaje de cada candidato: menor puntaje = mejor candidato.
+const (
+	loadWeight          = 10
+	backToBackWeight    = 5
+	secondaryRoleWeight = 3
+	// reliabilityWeight se multiplica por la fracción de faltas del historial.
+	reliabilityWeight = 8
+	// Preferencias del integrante: servir otro día o más veces de las que prefiere.
+	weekdayWeight        = 4
+	overPreferenceWeight = 6
+)
+
+type Service struct {
+	serviceRepo     serviceports.ServiceRepository
+	serviceUserRepo serviceuserports.ServiceUserRepository
+	userRepo        userports.UserRepository
+	blackoutRepo    userblackoutports.UserBlackoutRepository
+	positionRepo    positionports.PositionRepository
+	attendanceRepo  attendanceports.AttendanceRepository
+	tx              transactionports.Manager
+	notifier        notificationports.Notifier
+	audit           auditports.Recorder
+}
+
+func NewService(
+	serviceRepo serviceports.ServiceRepository,
+	serviceUserRepo serviceuserports.ServiceUserRepository,
+	userRepo userports.UserRepository,
+	blackoutRepo userblackoutports.UserBlackoutRepository,
+	positionRepo positionports.PositionRepository,
+	attendanceRepo attendanceports.AttendanceRepository,
+	tx transactionports.Manager,
+	notifier notificationports.Notifier,
+	audit auditports.Recorder,
+) *Service {
+	return &Service{
+		serviceRepo:     serviceRepo,
+		serviceUserRepo: serviceUserRepo,
+		userRepo:        userRepo,
+		blackoutRepo:    blackoutRepo,
+		positionRepo:    positionRepo,
+		attendanceRepo:  attendanceRepo,
+		tx:              tx,
+		notifier:        notifier,
+		audit:           audit,
+	}
+}
+
+// slot es un servicio (ya existente o propuesto) en la agenda de un usuario.
+type slot struct {
+	serviceID  uint
+	start, end time.Time
+}
+
+type candidate struct {
+	user        *models.User
+	secondary   bool
+	reliability float64
+	score       int
+}
+
+// Generate propone un equipo para cada servicio del rango sin guardar nada.
+func (s *Service) Generate(options models.RosterOptions) (*models.RosterDraft, error) {
+	if options.From.IsZero() || options.To.IsZero() || !options.To.After(options.From) {
+		return nil, models.ErrInvalidDateRange
+	}
+
+	all, err := s.serviceRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var services []models.Service
+	windows := make(map[uint]slot)
+	for _, svc := range all {
+		start, end, err := svc.Window()
+		if err != nil {
+			continue
+		}
+		windows[svc.ID] = slot{serviceID: svc.ID, start: start, end: end}
+		if !start.Before(options.From) && start.Before(options.To) {
+			services = append(services, svc)
+		}
+	}
+	sort.Slice(services, func(i, j int) bool {
+		return windows[services[i].ID].start.Before(windows[services[j].ID].start)
+	})
+
+	users, err := s.userRepo.GetAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+
+	userIDs := make([]uint, 0, len(users))
+	for _, u := range users {
+		userIDs = append(userIDs, u.ID)
+	}
+
+	blackouts, err := s.blackoutRepo.ListByUsers(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	blackoutsByUser := make(map[uint][]models.UserBlackout)
+	for _, b := range blackouts {
+		blackoutsByUser[b.UserID] = append(blackoutsByUser[b.UserID], b)
+	}
+
+	records, err := s.attendanceRepo.ListByUsers(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	reliability := models.ComputeReliability(userIDs, records)
+
+	inRange := make(map[uint]bool, len(services))
+	for _, svc := range services {
+		inRange[svc.ID] = true
+	}
+
+	// Los servicios del rango se van a reemplazar, así que solo cuentan las
+	// asignaciones que ya existen fuera de él.
+	existing, err := s.serviceUserRepo.ListByUsers(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	schedule := make(map[uint][]slot)
+	for _, a := range existing {
+		if inRange[a.ServiceID] || !a.IsActive() {
+			continue
+		}
+		if w, ok := windows[a.ServiceID]; ok {
+			schedule[a.UserID] = append(schedule[a.UserID], w)
+		}
+	}
+
+	catalog, err := s.positionRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	positionIDs := make(map[string]uint, len(catalog))
+	for _, p := range catalog {
+		positionIDs[strings.ToLower(p.Name)] = p.ID
+	}
+
+	load := make(map[uint]int)
+	draft := &models.RosterDraft{From: options.From, To: options.To}
+
+	for _, svc := range services {
+		window := windows[svc.ID]
+		positions, err := s.requiredPositions(options, svc.ID, catalog)
+		if err != nil {
+			return nil, err
+		}
+
+		roles := make([]string, 0, len(positions))
+		for role := range positions {
+			roles = append(roles, role)
+		}
+		sort.Strings(roles)
+
+		serviceDraft := models.RosterServiceDraft{
+			ServiceID:   svc.ID,
+			ServiceName: svc.Name,
+			StartTime:   svc.StartTime,
+			Assignments: []models.RosterAssignment{},
+			Unfilled:    []models.RosterUnfilled{},
+		}
+		picked := make(map[uint]bool)
+
+		for _, role := range roles {
+			needed := positions[role]
+			for needed > 0 {
+				best := s.bestCandidate(users, role, window, picked, schedule, load, blackoutsByUser, reliability)
+				if best == nil {
+					break
+				}
+				picked[best.user.ID] = true
+				load[best.user.ID]++
+				schedule[best.user.ID] = append(schedule[best.user.ID], window)
+				serviceDraft.Assignments = append(serviceDraft.Assignments, models.RosterAssignment{
+					UserID:      best.user.ID,
+					Username:    best.user.Username,
+					Role:        role,
+					PositionID:  positionIDs[strings.ToLower(role)],
+					Secondary:   best.secondary,
+					Reliability: best.reliability,
+				})
+				needed--
+			}
+			if needed > 0 {
+				serviceDraft.Unfilled = append(serviceDraft.Unfilled, models.RosterUnfilled{Role: role, Missing: needed})
+			}
+		}
+
+		draft.Services = append(draft.Services, serviceDraft)
+	}
+
+	if draft.Services == nil {
+		draft.Services = []models.RosterServiceDraft{}
+	}
+	draft.Load = []models.RosterLoad{}
+	for _, u := range users {
+		if load[u.ID] > 0 {
+			draft.Load = append(draft.Load, models.RosterLoad{UserID: u.ID, Username: u.Username, Services: load[u.ID]})
+		}
+	}
+
+	return draft, nil
+}
+
+// requiredPositions decide cuántas personas pide cada rol en el servicio.
+func (s *Service) requiredPositions(options models.RosterOptions, serviceID uint, catalog []models.Position) (map[string]int, error) {
+	if custom, ok := options.ServicePositions[serviceID]; ok {
+		return custom, nil
+	}
+	if len(options.Positions) > 0 {
+		return options.Positions, nil
+	}
+
+	slots, err := s.positionRepo.ListSlotsByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(catalog))
+	for _, p := range catalog {
+		names[p.ID] = p.Name
+	}
+	positions := make(map[string]int, len(slots))
+	for _, sp := range slots {
+		if name, ok := names[sp.PositionID]; ok && sp.Required > 0 {
+			positions[name] = sp.Required
+		}
+	}
+	return positions, nil
+}
+
+func (s *Service) bestCandidate(
+	users []models.User,
+	role string,
+	window slot,
+	picked map[uint]bool,
+	schedule map[uint][]slot,
+	load map[uint]int,
+	blackouts map[uint][]models.UserBlackout,
+	reliability map[uint]*models.MemberReliability,
+) *candidate {
+	var best *candidate
+	for i := range users {
+		u := &users[i]
+		if picked[u.ID] {
+			continue
+		}
+
+		secondary := false
+		switch {
+		case sameRole(u.Role, role):
+		case sameRole(u.SecondaryRole, role):
+			secondary = true
+		default:
+			continue
+		}
+
+		if !available(u, window, schedule[u.ID], blackouts[u.ID]) {
+			continue
+		}
+
+		score := load[u.ID] * loadWeight
+		if servedAdjacentWeek(window, schedule[u.ID]) {
+			score += backToBackWeight
+		}
+		if secondary {
+			score += secondaryRoleWeight
+		}
+		if !u.PreferredWeekdays.Includes(window.start.Weekday()) {
+			score += weekdayWeight
+		}
+		if u.PreferredServicesPerMonth > 0 && servicesInMonth(window, schedule[u.ID]) >= u.PreferredServicesPerMonth {
+			score += overPreferenceWeight
+		}
+		// Quien falta seguido queda detrás de quien siempre viene.
+		rel := 1.0
+		if st, ok := reliability[u.ID]; ok {
+			rel = st.Score
+		}
+		score += int(math.Round((1 - rel) * reliabilityWeight))
+
+		if best == nil || score < best.score {
+			best = &candidate{user: u, secondary: secondary, reliability: rel, score: score}
+		}
+	}
+	return best
+}
+
+func sameRole(userRole, role string) bool {
+	return userRole != "" && strings.EqualFold(strings.TrimSpace(userRole), strings.TrimSpace(role))
+}
+
+// available aplica las reglas duras: fechas bloqueadas, cruces de horario y
+// máximo de servicios por mes.
+func available(u *models.User, window slot, schedule []slot, blackouts []models.UserBlackout) bool {
+	for i := range blackouts {
+		if blackouts[i].Covers(window.start, window.end) {
+			return false
+		}
+	}
+
+	for _, other := range schedule {
+		if window.start.Before(other.end) && other.start.Before(window.end) {
+			return false
+		}
+	}
+
+	return u.MaxServicesPerMonth == 0 || servicesInMonth(window, schedule) < u.MaxServicesPerMonth
+}
+
+// servicesInMonth cuenta los servicios de la agenda en el mes de window.
+func servicesInMonth(window slot, schedule []slot) int {
+	count := 0
+	for _, other := range schedule {
+		if other.start.Year() == window.start.Year() && other.start.Month() == window.start.Month() {
+			count++
+		}
+	}
+	return count
+}
+
+// servedAdjacentWeek indica si el usuario ya sirve la semana anterior o la siguiente.
+func servedAdjacentWeek(window slot, schedule []slot) bool {
+	prevYear, prevWeek := window.start.AddDate(0, 0, -7).ISOWeek()
+	nextYear, nextWeek := window.start.AddDate(0, 0, 7).ISOWeek()
+	for _, other := range schedule {
+		year, week := other.start.ISOWeek()
+		if (year == prevYear && week == prevWeek) || (year == nextYear && week == nextWeek) {
+			return true
+		}
+	}
+	return false
+}
+
+// Commit guarda la propuesta revisada en una sola transacción. Cada equipo se
+// reemplaza con AssignUsers, que vuelve a validar usuarios, puestos, fechas
+// bloqueadas y cruces de horario: el borrador viene del cliente y pudo cambiar.
+// Si algún servicio tiene conflictos y no se pide override no se guarda nada.
+// Las notificaciones y la auditoría salen recién después del commit.
+func (s *Service) Commit(ctx context.Context, draft *models.RosterDraft) error {
+	if draft == nil || len(draft.Services) == 0 {
+		return models.ErrIncompleteFields
+	}
+	seen := make(map[uint]bool, len(draft.Services))
+	for _, svc := range draft.Services {
+		if seen[svc.ServiceID] {
+			return models.Invalid("duplicated_service", "Duplicated service")
+		}
+		seen[svc.ServiceID] = true
+	}
+
+	outbox := corenotification.NewOutbox(s.notifier)
+	audit := coreaudit.NewBuffer(s.audit)
+	err := s.tx.WithinTx(ctx, func(repos transactionports.Repositories) error {
+		team := coreserviceuser.NewService(repos.ServiceUsers, repos.Services, repos.Users, repos.Blackouts, repos.Positions, outbox, audit)
+		for _, svc := range draft.Services {
+			assignments := make([]models.UserAssignment, 0, len(svc.Assignments))
+			for _, a := range svc.Assignments {
+				assignments = append(assignments, models.UserAssignment{UserID: a.UserID, PositionID: a.PositionID})
+			}
+			if _, err := team.AssignUsers(ctx, svc.ServiceID, assignments, draft.Override); err != nil {
+				var domain *models.Error
+				if errors.As(err, &domain) {
+					return domain.With("service_id", svc.ServiceID)
+				}
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	outbox.Flush()
+	audit.Flush()
+	return nil
+}
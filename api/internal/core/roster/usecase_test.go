@@ -0,0 +1,211 @@
+package roster
+
+import (
+	"testing"
+	"time"
+
+	"melodiapp/models"
+)
+
+// sunday es un domingo a las 10; los servicios de prueba duran dos horas.
+var sunday = time.Date(2026, time.October, 11, 10, 0, 0, 0, time.UTC)
+
+func at(day time.Time) slot {
+	return slot{start: day, end: day.Add(2 * time.Hour)}
+}
+
+func TestAvailable(t *testing.T) {
+	window := at(sunday)
+
+	tests := []struct {
+		name      string
+		user      models.User
+		schedule  []slot
+		blackouts []models.UserBlackout
+		want      bool
+	}{
+		{name: "free", want: true},
+		{
+			name:      "blackout covers the service",
+			blackouts: []models.UserBlackout{{StartDate: sunday.AddDate(0, 0, -1), EndDate: sunday.AddDate(0, 0, 1)}},
+		},
+		{
+			name:      "blackout ends before the service",
+			blackouts: []models.UserBlackout{{StartDate: sunday.AddDate(0, 0, -3), EndDate: sunday.AddDate(0, 0, -2)}},
+			want:      true,
+		},
+		{
+			name:     "overlapping service",
+			schedule: []slot{at(sunday.Add(time.Hour))},
+		},
+		{
+			name:     "back to back service does not overlap",
+			schedule: []slot{at(sunday.Add(2 * time.Hour))},
+			want:     true,
+		},
+		{
+			name:     "monthly limit reached",
+			user:     models.User{MaxServicesPerMonth: 2},
+			schedule: []slot{at(sunday.AddDate(0, 0, -7)), at(sunday.AddDate(0, 0, 7))},
+		},
+		{
+			name:     "services in other months do not count for the limit",
+			user:     models.User{MaxServicesPerMonth: 1},
+			schedule: []slot{at(sunday.AddDate(0, -1, 0)), at(sunday.AddDate(0, 1, 0))},
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := available(&tt.user, window, tt.schedule, tt.blackouts); got != tt.want {
+				t.Errorf("available() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServicesInMonth(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule []slot
+		want     int
+	}{
+		{name: "empty", want: 0},
+		{name: "same month", schedule: []slot{at(sunday.AddDate(0, 0, -7)), at(sunday.AddDate(0, 0, 7))}, want: 2},
+		{name: "other months", schedule: []slot{at(sunday.AddDate(0, -1, 0)), at(sunday.AddDate(0, 1, 0))}, want: 0},
+		{name: "same month of another year", schedule: []slot{at(sunday.AddDate(-1, 0, 0))}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := servicesInMonth(at(sunday), tt.schedule); got != tt.want {
+				t.Errorf("servicesInMonth() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServedAdjacentWeek(t *testing.T) {
+	tests := []struct {
+		name     string
+		schedule []slot
+		want     bool
+	}{
+		{name: "empty"},
+		{name: "previous week", schedule: []slot{at(sunday.AddDate(0, 0, -7))}, want: true},
+		{name: "next week", schedule: []slot{at(sunday.AddDate(0, 0, 7))}, want: true},
+		{name: "two weeks away", schedule: []slot{at(sunday.AddDate(0, 0, -14)), at(sunday.AddDate(0, 0, 14))}},
+		{name: "same week", schedule: []slot{at(sunday.AddDate(0, 0, -3))}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := servedAdjacentWeek(at(sunday), tt.schedule); got != tt.want {
+				t.Errorf("servedAdjacentWeek() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBestCandidate(t *testing.T) {
+	window := at(sunday)
+
+	tests := []struct {
+		name        string
+		users       []models.User
+		picked      map[uint]bool
+		schedule    map[uint][]slot
+		load        map[uint]int
+		reliability map[uint]*models.MemberReliability
+		want        uint
+		secondary   bool
+	}{
+		{
+			name:  "nobody with the role",
+			users: []models.User{{ID: 1, Role: "bajo"}},
+		},
+		{
+			name:   "already picked",
+			users:  []models.User{{ID: 1, Role: "voz"}},
+			picked: map[uint]bool{1: true},
+		},
+		{
+			name:  "role matches ignoring case and spaces",
+			users: []models.User{{ID: 1, Role: " Voz "}},
+			want:  1,
+		},
+		{
+			name:  "main role before secondary role",
+			users: []models.User{{ID: 1, Role: "bajo", SecondaryRole: "voz"}, {ID: 2, Role: "voz"}},
+			want:  2,
+		},
+		{
+			name:      "secondary role when nobody else",
+			users:     []models.User{{ID: 1, Role: "bajo", SecondaryRole: "voz"}},
+			want:      1,
+			secondary: true,
+		},
+		{
+			name:  "less load first",
+			users: []models.User{{ID: 1, Role: "voz"}, {ID: 2, Role: "voz"}},
+			load:  map[uint]int{1: 2, 2: 1},
+			want:  2,
+		},
+		{
+			name:     "not back to back",
+			users:    []models.User{{ID: 1, Role: "voz"}, {ID: 2, Role: "voz"}},
+			schedule: map[uint][]slot{1: {at(sunday.AddDate(0, 0, -7))}},
+			want:     2,
+		},
+		{
+			name:     "unavailable users are skipped",
+			users:    []models.User{{ID: 1, Role: "voz"}, {ID: 2, Role: "voz"}},
+			schedule: map[uint][]slot{2: {window}},
+			load:     map[uint]int{1: 5},
+			want:     1,
+		},
+		{
+			name:  "preferred weekday",
+			users: []models.User{{ID: 1, Role: "voz", PreferredWeekdays: models.Weekdays{time.Wednesday}}, {ID: 2, Role: "voz", PreferredWeekdays: models.Weekdays{time.Sunday}}},
+			want:  2,
+		},
+		{
+			name:  "no weekday preference counts as any day",
+			users: []models.User{{ID: 1, Role: "voz", PreferredWeekdays: models.Weekdays{time.Wednesday}}, {ID: 2, Role: "voz"}},
+			want:  2,
+		},
+		{
+			name:     "over the preferred services per month",
+			users:    []models.User{{ID: 1, Role: "voz", PreferredServicesPerMonth: 1}, {ID: 2, Role: "voz"}},
+			schedule: map[uint][]slot{1: {at(sunday.AddDate(0, 0, 14))}, 2: {at(sunday.AddDate(0, 0, 14))}},
+			want:     2,
+		},
+		{
+			name:        "more reliable first",
+			users:       []models.User{{ID: 1, Role: "voz"}, {ID: 2, Role: "voz"}},
+			reliability: map[uint]*models.MemberReliability{1: {Score: 0.5}, 2: {Score: 1}},
+			want:        2,
+		},
+		{
+			name:  "ties keep the first user",
+			users: []models.User{{ID: 1, Role: "voz"}, {ID: 2, Role: "voz"}},
+			want:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{}
+			got := s.bestCandidate(tt.users, "voz", window, tt.picked, tt.schedule, tt.load, nil, tt.reliability)
+			if tt.want == 0 {
+				if got != nil {
+					t.Fatalf("got user %d, want none", got.user.ID)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatalf("got none, want user %d", tt.want)
+			}
+			if got.user.ID != tt.want || got.secondary != tt.secondary {
+				t.Errorf("got user %d (secondary %v), want %d (secondary %v)", got.user.ID, got.secondary, tt.want, tt.secondary)
+			}
+		})
+	}
+}
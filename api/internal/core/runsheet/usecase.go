@@ -0,0 +1,215 @@
+package runsheet
+
+import (
+	"strconv"
+	"strings"
+
+	runsheetports "melodiapp/internal/ports/runsheet"
+	serviceports "melodiapp/internal/ports/service"
+	servicesongports "melodiapp/internal/ports/servicesong"
+	userports "melodiapp/internal/ports/user"
+	"melodiapp/models"
+)
+
+var errIncompleteOrder = models.Invalid("incomplete_order", "Order must include every item once")
+
+type Service struct {
+	repo            runsheetports.RunSheetRepository
+	serviceRepo     serviceports.ServiceRepository
+	serviceSongRepo servicesongports.ServiceSongRepository
+	userRepo        userports.UserRepository
+}
+
+func NewService(
+	repo runsheetports.RunSheetRepository,
+	serviceRepo serviceports.ServiceRepository,
+	serviceSongRepo servicesongports.ServiceSongRepository,
+	userRepo userports.UserRepository,
+) *Service {
+	return &Service{
+		repo:            repo,
+		serviceRepo:     serviceRepo,
+		serviceSongRepo: serviceSongRepo,
+		userRepo:        userRepo,
+	}
+}
+
+func (s *Service) getService(serviceID uint) (*models.Service, error) {
+	svc, err := s.serviceRepo.GetByID(strconv.FormatUint(uint64(serviceID), 10))
+	if err != nil {
+		return nil, err
+	}
+	if svc == nil {
+		return nil, models.ErrServiceNotFound
+	}
+	return svc, nil
+}
+
+// Get devuelve el orden del servicio con el inicio de cada ítem y el total.
+func (s *Service) Get(serviceID uint) (*models.RunSheet, error) {
+	svc, err := s.getService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	return models.BuildRunSheet(svc, items), nil
+}
+
+// Replace arma el orden completo de una vez; el orden de items es el del servicio.
+func (s *Service) Replace(serviceID uint, items []models.RunSheetItem) (*models.RunSheet, error) {
+	if _, err := s.getService(serviceID); err != nil {
+		return nil, err
+	}
+	songs, err := s.serviceSongs(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if err := s.validateItem(&items[i], songs); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.repo.ReplaceItems(serviceID, items); err != nil {
+		return nil, err
+	}
+	return s.Get(serviceID)
+}
+
+func (s *Service) AddItem(serviceID uint, item *models.RunSheetItem) (*models.RunSheet, error) {
+	if _, err := s.getService(serviceID); err != nil {
+		return nil, err
+	}
+	songs, err := s.serviceSongs(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateItem(item, songs); err != nil {
+		return nil, err
+	}
+	item.ID = 0
+	item.ServiceID = serviceID
+	if err := s.repo.CreateItem(item); err != nil {
+		return nil, err
+	}
+	return s.Get(serviceID)
+}
+
+func (s *Service) UpdateItem(serviceID uint, itemID uint, input *models.RunSheetItem) (*models.RunSheet, error) {
+	current, err := s.repo.GetItem(serviceID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, models.ErrRunSheetItemNotFound
+	}
+	songs, err := s.serviceSongs(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validateItem(input, songs); err != nil {
+		return nil, err
+	}
+
+	current.Type = input.Type
+	current.Title = input.Title
+	current.DurationSeconds = input.DurationSeconds
+	current.ResponsibleID = input.ResponsibleID
+	current.SongID = input.SongID
+	current.Notes = input.Notes
+	if err := s.repo.UpdateItem(current); err != nil {
+		return nil, err
+	}
+	return s.Get(serviceID)
+}
+
+func (s *Service) RemoveItem(serviceID uint, itemID uint) (*models.RunSheet, error) {
+	current, err := s.repo.GetItem(serviceID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, models.ErrRunSheetItemNotFound
+	}
+	if err := s.repo.DeleteItem(serviceID, itemID); err != nil {
+		return nil, err
+	}
+	return s.Get(serviceID)
+}
+
+// Reorder exige todos los ítems del servicio, cada uno una sola vez.
+func (s *Service) Reorder(serviceID uint, itemIDs []uint) (*models.RunSheet, error) {
+	items, err := s.repo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(itemIDs) != len(items) {
+		return nil, errIncompleteOrder
+	}
+	existing := make(map[uint]bool, len(items))
+	for _, item := range items {
+		existing[item.ID] = true
+	}
+	seen := make(map[uint]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		if !existing[id] || seen[id] {
+			return nil, errIncompleteOrder
+		}
+		seen[id] = true
+	}
+
+	if err := s.repo.Reorder(serviceID, itemIDs); err != nil {
+		return nil, err
+	}
+	return s.Get(serviceID)
+}
+
+func (s *Service) serviceSongs(serviceID uint) (map[uint]bool, error) {
+	list, err := s.serviceSongRepo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	songs := make(map[uint]bool, len(list))
+	for _, ss := range list {
+		songs[ss.SongID] = true
+	}
+	return songs, nil
+}
+
+func (s *Service) validateItem(item *models.RunSheetItem, songs map[uint]bool) error {
+	item.Type = strings.ToLower(strings.TrimSpace(item.Type))
+	item.Title = strings.TrimSpace(item.Title)
+	if !models.IsRunItemType(item.Type) {
+		return models.Invalid("invalid_item_type", "Invalid item type")
+	}
+	if item.DurationSeconds < 0 {
+		return models.Invalid("negative_duration", "Duration must not be negative")
+	}
+
+	if item.Type == models.RunItemSong {
+		if item.SongID == 0 {
+			return models.Invalid("song_id_required", "Song items need a song_id")
+		}
+		if !songs[item.SongID] {
+			return models.Invalid("song_not_in_service", "Song is not assigned to the service")
+		}
+	} else {
+		item.SongID = 0
+		if item.Title == "" {
+			return models.ErrIncompleteFields
+		}
+	}
+
+	if item.ResponsibleID != 0 {
+		user, err := s.userRepo.GetUserByUintID(item.ResponsibleID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return models.ErrUserNotFound
+		}
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	coreaudit "melodiapp/internal/core/audit"
+	corenotification "melodiapp/internal/core/notification"
+	coreserviceoutfit "melodiapp/internal/core/serviceoutfit"
+	coreservicesong "melodiapp/internal/core/servicesong"
+	coreserviceuser "melodiapp/internal/core/serviceuser"
+	transactionports "melodiapp/internal/ports/transaction"
+	"melodiapp/models"
+)
+
+// CreateBundle crea el servicio con sus canciones, outfits y equipo en una
+// sola transacción. Las notificaciones y la auditoría salen recién después del commit.
+func (s *ServiceUsecase) CreateBundle(ctx context.Context, bundle *models.ServiceBundle) (*models.ServiceDetail, []models.AssignmentConflict, error) {
+	outbox := corenotification.NewOutbox(s.notifier)
+	audit := coreaudit.NewBuffer(s.audit)
+	created := bundle.Service
+	created.ID = 0
+	created.Version = 1
+
+	var conflicts []models.AssignmentConflict
+	err := s.tx.WithinTx(ctx, func(repos transactionports.Repositories) error {
+		if err := repos.Services.Create(&created); err != nil {
+			return err
+		}
+		audit.Record(ctx, serviceEntry(models.AuditCreate, nil, &created))
+		var err error
+		conflicts, err = applyBundle(ctx, repos, created.ID, bundle, outbox, audit)
+		if err != nil {
+			return err
+		}
+		return refreshVersion(repos, &created)
+	})
+	if err != nil {
+		return nil, conflicts, err
+	}
+
+	outbox.Flush()
+	audit.Flush()
+	detail, err := s.Details(&created)
+	return detail, conflicts, err
+}
+
+// SaveBundle actualiza el servicio y reemplaza las listas que vengan en bundle.
+// Cada guardado sube la versión del servicio, aunque solo cambien las listas;
+// bundle.Version es la versión que el cliente leyó (0 guarda sin comparar).
+func (s *ServiceUsecase) SaveBundle(ctx context.Context, id string, bundle *models.ServiceBundle) (*models.ServiceDetail, []models.AssignmentConflict, error) {
+	outbox := corenotification.NewOutbox(s.notifier)
+	audit := coreaudit.NewBuffer(s.audit)
+
+	var saved *models.Service
+	var changed bool
+	var conflicts []models.AssignmentConflict
+	err := s.tx.WithinTx(ctx, func(repos transactionports.Repositories) error {
+		existing, err := repos.Services.GetByID(id)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return models.ErrServiceNotFound
+		}
+		if bundle.Version != 0 && bundle.Version != existing.Version {
+			return models.ErrVersionMismatch
+		}
+
+		changed = existing.Name != bundle.Name ||
+			existing.StartTime != bundle.StartTime ||
+			existing.EndTime != bundle.EndTime
+		before := *existing
+		existing.Name = bundle.Name
+		existing.StartTime = bundle.StartTime
+		existing.EndTime = bundle.EndTime
+		if err := repos.Services.Update(existing); err != nil {
+			return err
+		}
+		if changed {
+			audit.Record(ctx, serviceEntry(models.AuditUpdate, &before, existing))
+		}
+		saved = existing
+
+		conflicts, err = applyBundle(ctx, repos, existing.ID, bundle, outbox, audit)
+		if err != nil {
+			return err
+		}
+		return refreshVersion(repos, saved)
+	})
+	if err != nil {
+		return nil, conflicts, err
+	}
+
+	outbox.Flush()
+	audit.Flush()
+	if changed {
+		s.notifyUpdated(saved)
+	}
+	detail, err := s.Details(saved)
+	return detail, conflicts, err
+}
+
+// patchFields son las claves que acepta Patch.
+var patchFields = []string{"name", "start_time", "end_time", "songs", "users", "outfits", "override"}
+
+// Patch aplica un JSON Merge Patch y lo guarda con SaveBundle. name, start_time
+// y end_time cambian solo si vienen; songs, users y outfits reemplazan la lista
+// completa y null la vacía. version es la del If-Match, como en SaveBundle.
+func (s *ServiceUsecase) Patch(ctx context.Context, id string, patch json.RawMessage, version uint) (*models.ServiceDetail, []models.AssignmentConflict, error) {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if existing == nil {
+		return nil, nil, models.ErrServiceNotFound
+	}
+
+	doc := map[string]string{
+		"name":       existing.Name,
+		"start_time": existing.StartTime,
+		"end_time":   existing.EndTime,
+	}
+	var bundle models.ServiceBundle
+	fields, err := models.ApplyMergePatch(doc, patch, patchFields, &bundle)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := fields["songs"]; ok && bundle.Songs == nil {
+		bundle.Songs = []uint{}
+	}
+	if _, ok := fields["users"]; ok && bundle.Users == nil {
+		bundle.Users = []models.UserAssignment{}
+	}
+	if _, ok := fields["outfits"]; ok && bundle.Outfits == nil {
+		bundle.Outfits = []uint{}
+	}
+	if err := validatePatch(&bundle.Service, fields); err != nil {
+		return nil, nil, err
+	}
+
+	bundle.Version = version
+	return s.SaveBundle(ctx, id, &bundle)
+}
+
+// validatePatch revisa el nombre y las horas que trae el patch.
+func validatePatch(svc *models.Service, fields map[string]json.RawMessage) error {
+	invalid := make(map[string]string)
+	if strings.TrimSpace(svc.Name) == "" {
+		invalid["name"] = "required"
+	}
+	if _, ok := fields["start_time"]; ok {
+		if _, err := models.ParseServiceTime(svc.StartTime); err != nil {
+			invalid["start_time"] = "invalid time"
+		}
+	}
+	if _, ok := fields["end_time"]; ok && svc.EndTime != "" {
+		if _, err := models.ParseServiceTime(svc.EndTime); err != nil {
+			invalid["end_time"] = "invalid time"
+		}
+	}
+	if len(invalid) > 0 {
+		return models.Validation(invalid)
+	}
+	return nil
+}
+
+// applyBundle reutiliza los casos de uso de cada lista sobre los repositorios
+// de la transacción. Primero repertorio y outfits, así al equipo nuevo no le
+// llega además un aviso de cambio de repertorio.
+func applyBundle(ctx context.Context, repos transactionports.Repositories, serviceID uint, bundle *models.ServiceBundle, outbox *corenotification.Outbox, audit *coreaudit.Buffer) ([]models.AssignmentConflict, error) {
+	if bundle.Songs != nil {
+		if err := replaceSongs(ctx, repos, serviceID, bundle.Songs, outbox, audit); err != nil {
+			return nil, err
+		}
+	}
+
+	if bundle.Outfits != nil {
+		outfits := coreserviceoutfit.NewService(repos.ServiceOutfits, repos.Outfits, repos.Services, audit)
+		if err := outfits.ReplaceOutfits(ctx, serviceID, bundle.Outfits); err != nil {
+			return nil, err
+		}
+	}
+
+	if bundle.Users == nil {
+		return nil, nil
+	}
+	team := coreserviceuser.NewService(repos.ServiceUsers, repos.Services, repos.Users, repos.Blackouts, repos.Positions, outbox, audit)
+	return team.AssignUsers(ctx, serviceID, bundle.Users, bundle.Override)
+}
+
+// refreshVersion relee la versión del servicio: los cambios de equipo,
+// repertorio, outfits y puestos también la suben (migración 0010).
+func refreshVersion(repos transactionports.Repositories, svc *models.Service) error {
+	current, err := repos.Services.GetByID(strconv.FormatUint(uint64(svc.ID), 10))
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		svc.Version = current.Version
+	}
+	return nil
+}
+
+// replaceSongs solo toca el repertorio si cambió, para no avisar al equipo de más.
+func replaceSongs(ctx context.Context, repos transactionports.Repositories, serviceID uint, songIDs []uint, outbox *corenotification.Outbox, audit *coreaudit.Buffer) error {
+	unique := make(map[uint]bool, len(songIDs))
+	for _, id := range songIDs {
+		unique[id] = true
+	}
+
+	current, err := repos.ServiceSongs.ListByService(serviceID)
+	if err != nil {
+		return err
+	}
+	same := len(current) == len(unique)
+	for _, ss := range current {
+		if !unique[ss.SongID] {
+			same = false
+			break
+		}
+	}
+	if same {
+		return nil
+	}
+
+	setlist := coreservicesong.NewService(repos.ServiceSongs, repos.Songs, repos.Services, repos.ServiceUsers, outbox, audit)
+	return setlist.AssignSongs(ctx, serviceID, songIDs)
+}
@@ -1,16 +1,60 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"log"
+
+	auditports "melodiapp/internal/ports/audit"
+	notificationports "melodiapp/internal/ports/notification"
+	positionports "melodiapp/internal/ports/position"
 	serviceports "melodiapp/internal/ports/service"
+	serviceoutfitports "melodiapp/internal/ports/serviceoutfit"
+	servicesongports "melodiapp/internal/ports/servicesong"
+	serviceuserports "melodiapp/internal/ports/serviceuser"
+	songports "melodiapp/internal/ports/song"
+	transactionports "melodiapp/internal/ports/transaction"
+	userports "melodiapp/internal/ports/user"
 	"melodiapp/models"
 )
 
 type ServiceUsecase struct {
-	repo serviceports.ServiceRepository
+	repo              serviceports.ServiceRepository
+	serviceUserRepo   serviceuserports.ServiceUserRepository
+	serviceSongRepo   servicesongports.ServiceSongRepository
+	serviceOutfitRepo serviceoutfitports.ServiceOutfitRepository
+	songRepo          songports.SongRepository
+	userRepo          userports.UserRepository
+	positionRepo      positionports.PositionRepository
+	tx                transactionports.Manager
+	notifier          notificationports.Notifier
+	audit             auditports.Recorder
 }
 
-func NewServiceUsecase(repo serviceports.ServiceRepository) *ServiceUsecase {
-	return &ServiceUsecase{repo: repo}
+func NewServiceUsecase(
+	repo serviceports.ServiceRepository,
+	serviceUserRepo serviceuserports.ServiceUserRepository,
+	serviceSongRepo servicesongports.ServiceSongRepository,
+	serviceOutfitRepo serviceoutfitports.ServiceOutfitRepository,
+	songRepo songports.SongRepository,
+	userRepo userports.UserRepository,
+	positionRepo positionports.PositionRepository,
+	tx transactionports.Manager,
+	notifier notificationports.Notifier,
+	audit auditports.Recorder,
+) *ServiceUsecase {
+	return &ServiceUsecase{
+		repo:              repo,
+		serviceUserRepo:   serviceUserRepo,
+		serviceSongRepo:   serviceSongRepo,
+		serviceOutfitRepo: serviceOutfitRepo,
+		songRepo:          songRepo,
+		userRepo:          userRepo,
+		positionRepo:      positionRepo,
+		tx:                tx,
+		notifier:          notifier,
+		audit:             audit,
+	}
 }
 
 func (s *ServiceUsecase) GetAll() ([]models.Service, error) {
@@ -21,22 +65,34 @@ func (s *ServiceUsecase) GetByID(id string) (*models.Service, error) {
 	return s.repo.GetByID(id)
 }
 
-func (s *ServiceUsecase) Create(svc *models.Service) (*models.Service, error) {
+func (s *ServiceUsecase) Create(ctx context.Context, svc *models.Service) (*models.Service, error) {
+	svc.Version = 1
 	if err := s.repo.Create(svc); err != nil {
 		return nil, err
 	}
+	s.audit.Record(ctx, serviceEntry(models.AuditCreate, nil, svc))
 	return svc, nil
 }
 
-func (s *ServiceUsecase) Update(id string, input *models.Service) (*models.Service, error) {
+// Update reemplaza los datos del servicio. input.Version es la versión que el
+// cliente leyó (If-Match); 0 guarda sin comparar.
+func (s *ServiceUsecase) Update(ctx context.Context, id string, input *models.Service) (*models.Service, error) {
 	existing, err := s.repo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
 	if existing == nil {
-		return nil, nil
+		return nil, models.ErrServiceNotFound
+	}
+	if input.Version != 0 && input.Version != existing.Version {
+		return nil, models.ErrVersionMismatch
 	}
 
+	changed := existing.Name != input.Name ||
+		existing.StartTime != input.StartTime ||
+		existing.EndTime != input.EndTime
+	before := *existing
+
 	existing.Name = input.Name
 	existing.StartTime = input.StartTime
 	existing.EndTime = input.EndTime
@@ -44,9 +100,190 @@ func (s *ServiceUsecase) Update(id string, input *models.Service) (*models.Servi
 	if err := s.repo.Update(existing); err != nil {
 		return nil, err
 	}
+	if changed {
+		s.audit.Record(ctx, serviceEntry(models.AuditUpdate, &before, existing))
+		s.notifyUpdated(existing)
+	}
 	return existing, nil
 }
 
-func (s *ServiceUsecase) Delete(id string) error {
-	return s.repo.DeleteByID(id)
+func serviceEntry(action string, before, after *models.Service) models.AuditEntry {
+	entry := models.AuditEntry{Action: action, EntityType: models.ResourceService}
+	if before != nil {
+		entry.EntityID = before.ID
+		entry.Before = before
+	}
+	if after != nil {
+		entry.EntityID = after.ID
+		entry.After = after
+	}
+	entry.ServiceID = entry.EntityID
+	return entry
+}
+
+func (s *ServiceUsecase) notifyUpdated(svc *models.Service) {
+	s.notifyTeam(svc, models.NotifyServiceUpdated, models.NotificationMessage{
+		Title: "Se actualizó " + svc.Name,
+		Body:  fmt.Sprintf("%s ahora es el %s.", svc.Name, svc.StartTime),
+	})
+}
+
+// Delete avisa al equipo antes de borrar, porque después ya no se puede saber quién servía.
+func (s *ServiceUsecase) Delete(ctx context.Context, id string) error {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		s.notifyTeam(existing, models.NotifyServiceCancelled, models.NotificationMessage{
+			Title: "Se canceló " + existing.Name,
+			Body:  fmt.Sprintf("El servicio %s del %s fue cancelado.", existing.Name, existing.StartTime),
+		})
+	}
+	if err := s.repo.DeleteByID(id); err != nil {
+		return err
+	}
+	if existing != nil {
+		s.audit.Record(ctx, serviceEntry(models.AuditDelete, existing, nil))
+	}
+	return nil
+}
+
+func (s *ServiceUsecase) notifyTeam(svc *models.Service, event string, message models.NotificationMessage) {
+	team, err := s.serviceUserRepo.ListByService(svc.ID)
+	if err != nil {
+		log.Printf("[Service] Error loading team for service %d: %v", svc.ID, err)
+		return
+	}
+	userIDs := make([]uint, 0, len(team))
+	for _, member := range team {
+		if member.IsActive() {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+	message.ServiceID = svc.ID
+	if err := s.notifier.Notify(event, userIDs, message); err != nil {
+		log.Printf("[Service] Error notifying %s for service %d: %v", event, svc.ID, err)
+	}
+}
+
+// Details junta canciones, equipo con su estado, outfits y cupos por puesto.
+func (s *ServiceUsecase) Details(svc *models.Service) (*models.ServiceDetail, error) {
+	details, err := s.DetailsList([]models.Service{*svc})
+	if err != nil {
+		return nil, err
+	}
+	return &details[0], nil
+}
+
+// DetailsList arma el detalle de cada servicio cargando canciones, equipo,
+// puestos y outfits de todos juntos, en el mismo orden que services.
+func (s *ServiceUsecase) DetailsList(services []models.Service) ([]models.ServiceDetail, error) {
+	serviceIDs := make([]uint, 0, len(services))
+	for _, svc := range services {
+		serviceIDs = append(serviceIDs, svc.ID)
+	}
+
+	serviceSongs, err := s.serviceSongRepo.ListByServices(serviceIDs)
+	if err != nil {
+		return nil, err
+	}
+	songIDs := make([]uint, 0, len(serviceSongs))
+	songsByService := make(map[uint]map[uint]bool)
+	for _, ss := range serviceSongs {
+		if songsByService[ss.ServiceID] == nil {
+			songsByService[ss.ServiceID] = make(map[uint]bool)
+		}
+		songsByService[ss.ServiceID][ss.SongID] = true
+		songIDs = append(songIDs, ss.SongID)
+	}
+	songs, err := s.songRepo.GetByIDs(songIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	team, err := s.serviceUserRepo.ListByServices(serviceIDs)
+	if err != nil {
+		return nil, err
+	}
+	userIDs := make([]uint, 0, len(team))
+	teamByService := make(map[uint][]models.ServiceUser)
+	for _, su := range team {
+		userIDs = append(userIDs, su.UserID)
+		teamByService[su.ServiceID] = append(teamByService[su.ServiceID], su)
+	}
+	users, err := s.userRepo.GetUsersByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := s.positionRepo.ListSlotsByServices(serviceIDs)
+	if err != nil {
+		return nil, err
+	}
+	slotsByService := make(map[uint][]models.ServicePosition)
+	for _, slot := range slots {
+		slotsByService[slot.ServiceID] = append(slotsByService[slot.ServiceID], slot)
+	}
+	positions, err := s.positionRepo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	serviceOutfits, err := s.serviceOutfitRepo.ListByServices(serviceIDs)
+	if err != nil {
+		return nil, err
+	}
+	outfitsByService := make(map[uint][]models.ServiceOutfitDetail)
+	for _, so := range serviceOutfits {
+		if so.Outfit == nil {
+			continue
+		}
+		outfitsByService[so.ServiceID] = append(outfitsByService[so.ServiceID], models.ServiceOutfitDetail{Outfit: *so.Outfit, OutfitID: so.OutfitID})
+	}
+
+	details := make([]models.ServiceDetail, 0, len(services))
+	for _, svc := range services {
+		serviceSongs := make([]models.Song, 0, len(songsByService[svc.ID]))
+		for _, song := range songs {
+			if songsByService[svc.ID][song.ID] {
+				serviceSongs = append(serviceSongs, song)
+			}
+		}
+
+		assignments := make(map[uint]models.ServiceUser, len(teamByService[svc.ID]))
+		for _, su := range teamByService[svc.ID] {
+			assignments[su.UserID] = su
+		}
+		members := make([]models.ServiceMember, 0, len(assignments))
+		for _, u := range users {
+			assignment, ok := assignments[u.ID]
+			if !ok {
+				continue
+			}
+			members = append(members, models.ServiceMember{
+				ID:            u.ID,
+				Username:      u.Username,
+				Email:         u.Email,
+				Role:          u.Role,
+				SecondaryRole: u.SecondaryRole,
+				Status:        assignment.Status,
+				PositionID:    assignment.PositionID,
+			})
+		}
+
+		outfits := outfitsByService[svc.ID]
+		if outfits == nil {
+			outfits = []models.ServiceOutfitDetail{}
+		}
+
+		details = append(details, models.ServiceDetail{
+			Service:   svc,
+			Songs:     serviceSongs,
+			Users:     members,
+			Outfits:   outfits,
+			Positions: models.BuildPositionSlots(positions, slotsByService[svc.ID], teamByService[svc.ID]),
+		})
+	}
+	return details, nil
 }
@@ -1,26 +1,143 @@
 package serviceoutfit
 
 import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+
+	auditports "melodiapp/internal/ports/audit"
+	outfitports "melodiapp/internal/ports/outfit"
+	serviceports "melodiapp/internal/ports/service"
 	serviceoutfitports "melodiapp/internal/ports/serviceoutfit"
 	"melodiapp/models"
 )
 
 type Service struct {
-	repo serviceoutfitports.ServiceOutfitRepository
+	repo        serviceoutfitports.ServiceOutfitRepository
+	outfitRepo  outfitports.OutfitRepository
+	serviceRepo serviceports.ServiceRepository
+	audit       auditports.Recorder
+}
+
+func NewService(
+	repo serviceoutfitports.ServiceOutfitRepository,
+	outfitRepo outfitports.OutfitRepository,
+	serviceRepo serviceports.ServiceRepository,
+	audit auditports.Recorder,
+) *Service {
+	return &Service{repo: repo, outfitRepo: outfitRepo, serviceRepo: serviceRepo, audit: audit}
 }
 
-func NewService(repo serviceoutfitports.ServiceOutfitRepository) *Service {
-	return &Service{repo: repo}
+// AssignOutfits solo acepta outfits que existan en el catálogo.
+func (s *Service) AssignOutfits(ctx context.Context, serviceID uint, outfitIDs []uint) error {
+	if err := s.ensureService(serviceID); err != nil {
+		return err
+	}
+	if err := s.ensureOutfits(outfitIDs); err != nil {
+		return err
+	}
+	before, err := s.outfits(serviceID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.AddOutfits(serviceID, outfitIDs); err != nil {
+		return err
+	}
+	s.recordChange(ctx, serviceID, before)
+	return nil
 }
 
-func (s *Service) AssignOutfits(serviceID uint, outfitIDs []uint) error {
-	return s.repo.AddOutfits(serviceID, outfitIDs)
+// ReplaceOutfits deja al servicio solo con outfitIDs.
+func (s *Service) ReplaceOutfits(ctx context.Context, serviceID uint, outfitIDs []uint) error {
+	if err := s.ensureService(serviceID); err != nil {
+		return err
+	}
+	if err := s.ensureOutfits(outfitIDs); err != nil {
+		return err
+	}
+	before, err := s.outfits(serviceID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.ReplaceOutfits(serviceID, outfitIDs); err != nil {
+		return err
+	}
+	s.recordChange(ctx, serviceID, before)
+	return nil
+}
+
+func (s *Service) ensureOutfits(outfitIDs []uint) error {
+	if len(outfitIDs) == 0 {
+		return nil
+	}
+	outfits, err := s.outfitRepo.GetByIDs(outfitIDs)
+	if err != nil {
+		return err
+	}
+	found := make([]uint, 0, len(outfits))
+	for _, outfit := range outfits {
+		found = append(found, outfit.ID)
+	}
+	if missing := models.MissingIDs(outfitIDs, found); len(missing) > 0 {
+		return models.MissingReferences("Outfit", missing)
+	}
+	return nil
+}
+
+func (s *Service) ensureService(serviceID uint) error {
+	svc, err := s.serviceRepo.GetByID(strconv.FormatUint(uint64(serviceID), 10))
+	if err != nil {
+		return err
+	}
+	if svc == nil {
+		return models.ErrServiceNotFound
+	}
+	return nil
 }
 
 func (s *Service) ListByService(serviceID uint) ([]models.ServiceOutfit, error) {
 	return s.repo.ListByService(serviceID)
 }
 
-func (s *Service) Remove(serviceID uint, outfitID uint) error {
-	return s.repo.Remove(serviceID, outfitID)
+func (s *Service) Remove(ctx context.Context, serviceID uint, outfitID uint) error {
+	before, err := s.outfits(serviceID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Remove(serviceID, outfitID); err != nil {
+		return err
+	}
+	s.recordChange(ctx, serviceID, before)
+	return nil
+}
+
+// outfits es la foto de los outfits del servicio que se guarda en la auditoría.
+func (s *Service) outfits(serviceID uint) (map[string][]uint, error) {
+	items, err := s.repo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.OutfitID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return map[string][]uint{"outfit_ids": ids}, nil
+}
+
+func (s *Service) recordChange(ctx context.Context, serviceID uint, before map[string][]uint) {
+	after, err := s.outfits(serviceID)
+	if err != nil {
+		log.Printf("[ServiceOutfit] Error loading outfits for audit of service %d: %v", serviceID, err)
+		return
+	}
+	s.audit.Record(ctx, models.AuditEntry{
+		Action:     models.AuditUpdate,
+		EntityType: models.AuditServiceOutfits,
+		EntityID:   serviceID,
+		ServiceID:  serviceID,
+		Before:     before,
+		After:      after,
+	})
 }
@@ -1,26 +1,203 @@
 package servicesong
 
 import (
+	"context"
+	"log"
+	"sort"
+	"strconv"
+
+	auditports "melodiapp/internal/ports/audit"
+	notificationports "melodiapp/internal/ports/notification"
+	serviceports "melodiapp/internal/ports/service"
 	servicesongports "melodiapp/internal/ports/servicesong"
+	serviceuserports "melodiapp/internal/ports/serviceuser"
+	songports "melodiapp/internal/ports/song"
 	"melodiapp/models"
 )
 
 type Service struct {
-	repo servicesongports.ServiceSongRepository
+	repo            servicesongports.ServiceSongRepository
+	songRepo        songports.SongRepository
+	serviceRepo     serviceports.ServiceRepository
+	serviceUserRepo serviceuserports.ServiceUserRepository
+	notifier        notificationports.Notifier
+	audit           auditports.Recorder
+}
+
+func NewService(
+	repo servicesongports.ServiceSongRepository,
+	songRepo songports.SongRepository,
+	serviceRepo serviceports.ServiceRepository,
+	serviceUserRepo serviceuserports.ServiceUserRepository,
+	notifier notificationports.Notifier,
+	audit auditports.Recorder,
+) *Service {
+	return &Service{
+		repo:            repo,
+		songRepo:        songRepo,
+		serviceRepo:     serviceRepo,
+		serviceUserRepo: serviceUserRepo,
+		notifier:        notifier,
+		audit:           audit,
+	}
+}
+
+func (s *Service) AssignSongs(ctx context.Context, serviceID uint, songIDs []uint) error {
+	if err := s.ensureReferences(serviceID, songIDs); err != nil {
+		return err
+	}
+	before, err := s.setlist(serviceID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.ReplaceSongs(serviceID, songIDs); err != nil {
+		return err
+	}
+	s.recordChange(ctx, serviceID, before)
+	s.notifySetlistChanged(serviceID)
+	return nil
+}
+
+func (s *Service) AddSong(ctx context.Context, serviceID uint, songID uint) error {
+	if err := s.ensureReferences(serviceID, []uint{songID}); err != nil {
+		return err
+	}
+	before, err := s.setlist(serviceID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.ApplySongDiff(serviceID, []uint{songID}, nil); err != nil {
+		return err
+	}
+	s.recordChange(ctx, serviceID, before)
+	s.notifySetlistChanged(serviceID)
+	return nil
 }
 
-func NewService(repo servicesongports.ServiceSongRepository) *Service {
-	return &Service{repo: repo}
+func (s *Service) UpdateSongs(ctx context.Context, serviceID uint, add []uint, remove []uint) error {
+	adding := make(map[uint]bool, len(add))
+	for _, sid := range add {
+		adding[sid] = true
+	}
+	for _, sid := range remove {
+		if adding[sid] {
+			return models.Invalid("song_in_add_and_remove", "Song in both add and remove")
+		}
+	}
+	if err := s.ensureReferences(serviceID, add); err != nil {
+		return err
+	}
+	before, err := s.setlist(serviceID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.ApplySongDiff(serviceID, add, remove); err != nil {
+		return err
+	}
+	s.recordChange(ctx, serviceID, before)
+	s.notifySetlistChanged(serviceID)
+	return nil
 }
 
-func (s *Service) AssignSongs(serviceID uint, songIDs []uint) error {
-	return s.repo.AddSongs(serviceID, songIDs)
+// ensureReferences valida que el servicio y todas las canciones existan antes
+// de escribir, para responder con los ids faltantes en vez de un error de la base.
+func (s *Service) ensureReferences(serviceID uint, songIDs []uint) error {
+	svc, err := s.serviceRepo.GetByID(strconv.FormatUint(uint64(serviceID), 10))
+	if err != nil {
+		return err
+	}
+	if svc == nil {
+		return models.ErrServiceNotFound
+	}
+	if len(songIDs) == 0 {
+		return nil
+	}
+
+	songs, err := s.songRepo.GetByIDs(songIDs)
+	if err != nil {
+		return err
+	}
+	found := make([]uint, 0, len(songs))
+	for _, song := range songs {
+		found = append(found, song.ID)
+	}
+	if missing := models.MissingIDs(songIDs, found); len(missing) > 0 {
+		return models.MissingReferences("Song", missing)
+	}
+	return nil
 }
 
 func (s *Service) ListByService(serviceID uint) ([]models.ServiceSong, error) {
 	return s.repo.ListByService(serviceID)
 }
 
-func (s *Service) Remove(serviceID uint, songID uint) error {
-	return s.repo.Remove(serviceID, songID)
+func (s *Service) Remove(ctx context.Context, serviceID uint, songID uint) error {
+	before, err := s.setlist(serviceID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Remove(serviceID, songID); err != nil {
+		return err
+	}
+	s.recordChange(ctx, serviceID, before)
+	s.notifySetlistChanged(serviceID)
+	return nil
+}
+
+// setlist es la foto del repertorio que se guarda en la auditoría.
+func (s *Service) setlist(serviceID uint) (map[string][]uint, error) {
+	items, err := s.repo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uint, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, item.SongID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return map[string][]uint{"song_ids": ids}, nil
+}
+
+func (s *Service) recordChange(ctx context.Context, serviceID uint, before map[string][]uint) {
+	after, err := s.setlist(serviceID)
+	if err != nil {
+		log.Printf("[ServiceSong] Error loading setlist for audit of service %d: %v", serviceID, err)
+		return
+	}
+	s.audit.Record(ctx, models.AuditEntry{
+		Action:     models.AuditUpdate,
+		EntityType: models.AuditServiceSongs,
+		EntityID:   serviceID,
+		ServiceID:  serviceID,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// notifySetlistChanged avisa al equipo activo que cambió el repertorio.
+func (s *Service) notifySetlistChanged(serviceID uint) {
+	svc, err := s.serviceRepo.GetByID(strconv.FormatUint(uint64(serviceID), 10))
+	if err != nil || svc == nil {
+		return
+	}
+	team, err := s.serviceUserRepo.ListByService(serviceID)
+	if err != nil {
+		log.Printf("[ServiceSong] Error loading team for service %d: %v", serviceID, err)
+		return
+	}
+	userIDs := make([]uint, 0, len(team))
+	for _, member := range team {
+		if member.IsActive() {
+			userIDs = append(userIDs, member.UserID)
+		}
+	}
+
+	message := models.NotificationMessage{
+		Title:     "Cambió el repertorio de " + svc.Name,
+		Body:      "Revisa las canciones actualizadas para " + svc.Name + ".",
+		ServiceID: serviceID,
+	}
+	if err := s.notifier.Notify(models.NotifySetlistChanged, userIDs, message); err != nil {
+		log.Printf("[ServiceSong] Error notifying setlist change for service %d: %v", serviceID, err)
+	}
 }
@@ -1,26 +1,469 @@
 package serviceuser
 
 import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	auditports "melodiapp/internal/ports/audit"
+	notificationports "melodiapp/internal/ports/notification"
+	positionports "melodiapp/internal/ports/position"
+	serviceports "melodiapp/internal/ports/service"
 	serviceuserports "melodiapp/internal/ports/serviceuser"
+	userports "melodiapp/internal/ports/user"
+	userblackoutports "melodiapp/internal/ports/userblackout"
 	"melodiapp/models"
 )
 
 type Service struct {
-	repo serviceuserports.ServiceUserRepository
+	repo         serviceuserports.ServiceUserRepository
+	serviceRepo  serviceports.ServiceRepository
+	userRepo     userports.UserRepository
+	blackoutRepo userblackoutports.UserBlackoutRepository
+	positionRepo positionports.PositionRepository
+	notifier     notificationports.Notifier
+	audit        auditports.Recorder
+}
+
+func NewService(
+	repo serviceuserports.ServiceUserRepository,
+	serviceRepo serviceports.ServiceRepository,
+	userRepo userports.UserRepository,
+	blackoutRepo userblackoutports.UserBlackoutRepository,
+	positionRepo positionports.PositionRepository,
+	notifier notificationports.Notifier,
+	audit auditports.Recorder,
+) *Service {
+	return &Service{
+		repo:         repo,
+		serviceRepo:  serviceRepo,
+		userRepo:     userRepo,
+		blackoutRepo: blackoutRepo,
+		positionRepo: positionRepo,
+		notifier:     notifier,
+		audit:        audit,
+	}
+}
+
+// AssignUsers reemplaza el equipo del servicio. Si hay conflictos y no se pide
+// override, no se asigna nada y se devuelven los conflictos encontrados.
+func (s *Service) AssignUsers(ctx context.Context, serviceID uint, assignments []models.UserAssignment, override bool) ([]models.AssignmentConflict, error) {
+	userIDs, err := s.validateAssignments(assignments)
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts, err := s.CheckConflicts(serviceID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 && !override {
+		return conflicts, models.ErrAssignmentConflicts.With("conflicts", conflicts)
+	}
+
+	added, err := s.newMembers(serviceID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.team(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceUsers(serviceID, assignments); err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, serviceID, before)
+	s.notifyAssigned(serviceID, added)
+	return conflicts, nil
+}
+
+// AddUser agrega un usuario al equipo sin tocar al resto. Es idempotente.
+func (s *Service) AddUser(ctx context.Context, serviceID uint, assignment models.UserAssignment, override bool) ([]models.AssignmentConflict, error) {
+	return s.UpdateTeam(ctx, serviceID, []models.UserAssignment{assignment}, nil, override)
+}
+
+func (s *Service) RemoveUser(ctx context.Context, serviceID uint, userID uint) error {
+	before, err := s.team(serviceID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.ApplyUserDiff(serviceID, nil, []uint{userID}); err != nil {
+		return err
+	}
+	s.recordChange(ctx, serviceID, before)
+	return nil
+}
+
+// UpdateTeam aplica un diff sobre el equipo: agrega los de add y quita los de
+// remove. Solo se revisan conflictos de los usuarios agregados.
+func (s *Service) UpdateTeam(ctx context.Context, serviceID uint, add []models.UserAssignment, remove []uint, override bool) ([]models.AssignmentConflict, error) {
+	addIDs, err := s.validateAssignments(add)
+	if err != nil {
+		return nil, err
+	}
+	adding := make(map[uint]bool, len(addIDs))
+	for _, uid := range addIDs {
+		adding[uid] = true
+	}
+	for _, uid := range remove {
+		if adding[uid] {
+			return nil, models.Invalid("user_in_add_and_remove", "User in both add and remove")
+		}
+	}
+
+	conflicts, err := s.CheckConflicts(serviceID, addIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 && !override {
+		return conflicts, models.ErrAssignmentConflicts.With("conflicts", conflicts)
+	}
+
+	added, err := s.newMembers(serviceID, addIDs)
+	if err != nil {
+		return nil, err
+	}
+	before, err := s.team(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ApplyUserDiff(serviceID, add, remove); err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, serviceID, before)
+	s.notifyAssigned(serviceID, added)
+	return conflicts, nil
+}
+
+// teamMember es lo que se guarda de cada integrante en la auditoría.
+type teamMember struct {
+	UserID     uint   `json:"user_id"`
+	Status     string `json:"status"`
+	PositionID uint   `json:"position_id"`
+}
+
+// team es la foto del equipo que se guarda en la auditoría.
+func (s *Service) team(serviceID uint) (map[string][]teamMember, error) {
+	items, err := s.repo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]teamMember, 0, len(items))
+	for _, item := range items {
+		members = append(members, teamMember{UserID: item.UserID, Status: item.Status, PositionID: item.PositionID})
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].UserID < members[j].UserID })
+	return map[string][]teamMember{"members": members}, nil
+}
+
+func (s *Service) recordChange(ctx context.Context, serviceID uint, before map[string][]teamMember) {
+	after, err := s.team(serviceID)
+	if err != nil {
+		log.Printf("[ServiceUser] Error loading team for audit of service %d: %v", serviceID, err)
+		return
+	}
+	s.audit.Record(ctx, models.AuditEntry{
+		Action:     models.AuditUpdate,
+		EntityType: models.AuditServiceUsers,
+		EntityID:   serviceID,
+		ServiceID:  serviceID,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// newMembers filtra de userIDs a quienes todavía no están en el equipo.
+func (s *Service) newMembers(serviceID uint, userIDs []uint) ([]uint, error) {
+	team, err := s.repo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[uint]bool, len(team))
+	for _, member := range team {
+		current[member.UserID] = true
+	}
+	added := make([]uint, 0, len(userIDs))
+	for _, uid := range userIDs {
+		if !current[uid] {
+			added = append(added, uid)
+		}
+	}
+	return added, nil
+}
+
+func (s *Service) notifyAssigned(serviceID uint, userIDs []uint) {
+	if len(userIDs) == 0 {
+		return
+	}
+	svc, err := s.serviceRepo.GetByID(strconv.FormatUint(uint64(serviceID), 10))
+	if err != nil || svc == nil {
+		return
+	}
+	message := models.NotificationMessage{
+		Title:     "Te asignaron a " + svc.Name,
+		Body:      fmt.Sprintf("Fuiste agregado al equipo de %s (%s). Confirma si puedes servir.", svc.Name, svc.StartTime),
+		ServiceID: serviceID,
+	}
+	if err := s.notifier.Notify(models.NotifyAssigned, userIDs, message); err != nil {
+		log.Printf("[ServiceUser] Error notifying assignment for service %d: %v", serviceID, err)
+	}
 }
 
-func NewService(repo serviceuserports.ServiceUserRepository) *Service {
-	return &Service{repo: repo}
+func (s *Service) validateAssignments(assignments []models.UserAssignment) ([]uint, error) {
+	userIDs := make([]uint, 0, len(assignments))
+	positionIDs := make([]uint, 0, len(assignments))
+	seen := make(map[uint]bool)
+	for _, a := range assignments {
+		if a.UserID == 0 {
+			return nil, models.Invalid("invalid_user_id", "Invalid user id")
+		}
+		if seen[a.UserID] {
+			return nil, models.Invalid("duplicated_user", "Duplicated user")
+		}
+		seen[a.UserID] = true
+		userIDs = append(userIDs, a.UserID)
+		if a.PositionID != 0 {
+			positionIDs = append(positionIDs, a.PositionID)
+		}
+	}
+	if err := s.ensureUsers(userIDs); err != nil {
+		return nil, err
+	}
+	if err := s.ensurePositions(positionIDs); err != nil {
+		return nil, err
+	}
+	return userIDs, nil
 }
 
-func (s *Service) AssignUsers(serviceID uint, userIDs []uint) error {
-	return s.repo.AddUsers(serviceID, userIDs)
+func (s *Service) ensurePositions(positionIDs []uint) error {
+	if len(positionIDs) == 0 {
+		return nil
+	}
+	positions, err := s.positionRepo.GetByIDs(positionIDs)
+	if err != nil {
+		return err
+	}
+	found := make([]uint, 0, len(positions))
+	for _, p := range positions {
+		found = append(found, p.ID)
+	}
+	if missing := models.MissingIDs(positionIDs, found); len(missing) > 0 {
+		return models.MissingReferences("Position", missing)
+	}
+	return nil
+}
+
+func (s *Service) ensureUsers(userIDs []uint) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	users, err := s.userRepo.GetUsersByIDs(userIDs)
+	if err != nil {
+		return err
+	}
+	found := make([]uint, 0, len(users))
+	for _, u := range users {
+		found = append(found, u.ID)
+	}
+	if missing := models.MissingIDs(userIDs, found); len(missing) > 0 {
+		return models.MissingReferences("User", missing)
+	}
+	return nil
+}
+
+// CheckConflicts revisa, para cada usuario, servicios que se cruzan en horario,
+// fechas bloqueadas y el máximo de servicios por mes.
+func (s *Service) CheckConflicts(serviceID uint, userIDs []uint) ([]models.AssignmentConflict, error) {
+	target, err := s.serviceRepo.GetByID(strconv.FormatUint(uint64(serviceID), 10))
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, models.ErrServiceNotFound
+	}
+
+	conflicts := []models.AssignmentConflict{}
+	if len(userIDs) == 0 {
+		return conflicts, nil
+	}
+	start, end, err := target.Window()
+	if err != nil {
+		// Sin horario válido no hay nada contra qué comparar.
+		return conflicts, nil
+	}
+
+	assignments, err := s.repo.ListByUsers(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	otherIDs := make([]uint, 0, len(assignments))
+	seen := make(map[uint]bool)
+	for _, a := range assignments {
+		if a.ServiceID != serviceID && !seen[a.ServiceID] {
+			seen[a.ServiceID] = true
+			otherIDs = append(otherIDs, a.ServiceID)
+		}
+	}
+	others, err := s.serviceRepo.GetByIDs(otherIDs)
+	if err != nil {
+		return nil, err
+	}
+	servicesByID := make(map[uint]*models.Service, len(others))
+	for i := range others {
+		servicesByID[others[i].ID] = &others[i]
+	}
+
+	blackouts, err := s.blackoutRepo.ListByUsers(userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := s.userRepo.GetUsersByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	usersByID := make(map[uint]*models.User, len(users))
+	for i := range users {
+		usersByID[users[i].ID] = &users[i]
+	}
+
+	for _, uid := range userIDs {
+		monthCount := 0
+		for _, a := range assignments {
+			if a.UserID != uid || a.ServiceID == serviceID || !a.IsActive() {
+				continue
+			}
+			other, ok := servicesByID[a.ServiceID]
+			if !ok {
+				continue
+			}
+			if target.Overlaps(other) {
+				conflicts = append(conflicts, models.AssignmentConflict{
+					UserID:             uid,
+					Type:               models.ConflictOverlap,
+					Message:            fmt.Sprintf("User is already assigned to service %q at an overlapping time", other.Name),
+					ConflictingService: other.ID,
+				})
+			}
+			if otherStart, _, err := other.Window(); err == nil &&
+				otherStart.Year() == start.Year() && otherStart.Month() == start.Month() {
+				monthCount++
+			}
+		}
+
+		for i := range blackouts {
+			b := &blackouts[i]
+			if b.UserID == uid && b.Covers(start, end) {
+				conflicts = append(conflicts, models.AssignmentConflict{
+					UserID:     uid,
+					Type:       models.ConflictBlackout,
+					Message:    "Service falls within a blackout period of the user",
+					BlackoutID: b.ID,
+				})
+			}
+		}
+
+		user := usersByID[uid]
+		if user != nil && user.MaxServicesPerMonth > 0 && monthCount+1 > user.MaxServicesPerMonth {
+			conflicts = append(conflicts, models.AssignmentConflict{
+				UserID:              uid,
+				Type:                models.ConflictMonthlyLimit,
+				Message:             "User would exceed the maximum services per month",
+				ServicesInMonth:     monthCount,
+				MaxServicesPerMonth: user.MaxServicesPerMonth,
+			})
+		}
+	}
+
+	return conflicts, nil
 }
 
 func (s *Service) ListByService(serviceID uint) ([]models.ServiceUser, error) {
 	return s.repo.ListByService(serviceID)
 }
 
-func (s *Service) ChangeStatus(serviceID uint, userID uint, status string) error {
-	return s.repo.UpdateStatus(serviceID, userID, status)
+// transitions define a qué estados puede pasar una asignación desde cada estado.
+var transitions = map[string][]string{
+	models.StatusPending:   {models.StatusAccepted, models.StatusDeclined},
+	models.StatusAccepted:  {models.StatusCancelled},
+	models.StatusDeclined:  {models.StatusAccepted},
+	models.StatusCancelled: {},
+}
+
+func canTransition(from, to string) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeStatus valida la transición y la registra con fecha. El motivo solo se
+// guarda al rechazar o cancelar.
+func (s *Service) ChangeStatus(ctx context.Context, serviceID uint, userID uint, status string, reason string) (*models.ServiceUser, error) {
+	if _, ok := transitions[status]; !ok {
+		return nil, models.Invalid("invalid_status", "Invalid status")
+	}
+
+	current, err := s.repo.GetAssignment(serviceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, models.ErrAssignmentNotFound
+	}
+	if !canTransition(current.Status, status) {
+		return nil, models.Conflict("invalid_status_transition", fmt.Sprintf("Invalid status transition from %s to %s", current.Status, status))
+	}
+
+	if status != models.StatusDeclined && status != models.StatusCancelled {
+		reason = ""
+	}
+
+	before, err := s.team(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	change := &models.ServiceUserStatusChange{
+		ServiceID:  serviceID,
+		UserID:     userID,
+		FromStatus: current.Status,
+		ToStatus:   status,
+		Reason:     reason,
+		ChangedAt:  now,
+	}
+	if err := s.repo.UpdateStatus(change); err != nil {
+		return nil, err
+	}
+	s.recordChange(ctx, serviceID, before)
+
+	current.Status = status
+	current.DeclineReason = reason
+	current.StatusChangedAt = &now
+	return current, nil
+}
+
+func (s *Service) StatusHistory(serviceID uint) ([]models.ServiceUserStatusChange, error) {
+	return s.repo.ListStatusHistory(serviceID)
+}
+
+func (s *Service) ChangePosition(ctx context.Context, serviceID uint, userID uint, positionID uint) error {
+	if err := s.ensurePositions([]uint{positionID}); err != nil {
+		return err
+	}
+	before, err := s.team(serviceID)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.SetPosition(serviceID, userID, positionID); err != nil {
+		return err
+	}
+	s.recordChange(ctx, serviceID, before)
+	return nil
 }
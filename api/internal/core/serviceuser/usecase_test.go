@@ -0,0 +1,33 @@
+package serviceuser
+
+import (
+	"testing"
+
+	"melodiapp/models"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{models.StatusPending, models.StatusAccepted, true},
+		{models.StatusPending, models.StatusDeclined, true},
+		{models.StatusPending, models.StatusCancelled, false},
+		{models.StatusPending, models.StatusPending, false},
+		{models.StatusAccepted, models.StatusCancelled, true},
+		{models.StatusAccepted, models.StatusDeclined, false},
+		{models.StatusAccepted, models.StatusPending, false},
+		{models.StatusDeclined, models.StatusAccepted, true},
+		{models.StatusDeclined, models.StatusCancelled, false},
+		{models.StatusCancelled, models.StatusAccepted, false},
+		{models.StatusCancelled, models.StatusPending, false},
+		{"rejected", models.StatusAccepted, false},
+		{"", models.StatusAccepted, false},
+	}
+	for _, tt := range tests {
+		if got := canTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("canTransition(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -1,16 +1,22 @@
 package song
 
 import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	auditports "melodiapp/internal/ports/audit"
 	songports "melodiapp/internal/ports/song"
 	"melodiapp/models"
 )
 
 type Service struct {
-	repo songports.SongRepository
+	repo  songports.SongRepository
+	audit auditports.Recorder
 }
 
-func NewService(repo songports.SongRepository) *Service {
-	return &Service{repo: repo}
+func NewService(repo songports.SongRepository, audit auditports.Recorder) *Service {
+	return &Service{repo: repo, audit: audit}
 }
 
 func (s *Service) GetAll() ([]models.Song, error) {
@@ -21,21 +27,56 @@ func (s *Service) GetByID(id string) (*models.Song, error) {
 	return s.repo.GetByID(id)
 }
 
-func (s *Service) Create(song *models.Song) (*models.Song, error) {
+// patchFields son los campos que se pueden cambiar con PATCH.
+var patchFields = []string{
+	"name", "author", "song_key", "bpm", "time_signature", "duration", "structure",
+	"has_sequence", "has_chart", "has_score",
+	"youtube_url", "voice_url", "guitar_url", "piano_url", "drums_url", "bass_url", "wind_url",
+}
+
+func validate(song *models.Song) error {
+	invalid := make(map[string]string)
+	if strings.TrimSpace(song.Name) == "" {
+		invalid["name"] = "required"
+	}
+	if song.BPM < 0 {
+		invalid["bpm"] = "must not be negative"
+	}
+	if len(invalid) > 0 {
+		return models.Validation(invalid)
+	}
+	return nil
+}
+
+func (s *Service) Create(ctx context.Context, song *models.Song) (*models.Song, error) {
+	if err := validate(song); err != nil {
+		return nil, err
+	}
+	song.Version = 1
 	if err := s.repo.Create(song); err != nil {
 		return nil, err
 	}
+	s.audit.Record(ctx, models.AuditEntry{Action: models.AuditCreate, EntityType: models.ResourceSong, EntityID: song.ID, After: song})
 	return song, nil
 }
 
-func (s *Service) Update(id string, input *models.Song) (*models.Song, error) {
+// Update reemplaza los datos de la canción. input.Version es la versión que el
+// cliente leyó (If-Match); 0 guarda sin comparar.
+func (s *Service) Update(ctx context.Context, id string, input *models.Song) (*models.Song, error) {
 	existing, err := s.repo.GetByID(id)
 	if err != nil {
 		return nil, err
 	}
 	if existing == nil {
-		return nil, nil
+		return nil, models.ErrSongNotFound
 	}
+	if input.Version != 0 && input.Version != existing.Version {
+		return nil, models.ErrVersionMismatch
+	}
+	if err := validate(input); err != nil {
+		return nil, err
+	}
+	before := *existing
 
 	existing.Name = input.Name
 	existing.Author = input.Author
@@ -58,9 +99,39 @@ func (s *Service) Update(id string, input *models.Song) (*models.Song, error) {
 	if err := s.repo.Update(existing); err != nil {
 		return nil, err
 	}
+	s.audit.Record(ctx, models.AuditEntry{Action: models.AuditUpdate, EntityType: models.ResourceSong, EntityID: existing.ID, Before: before, After: existing})
 	return existing, nil
 }
 
-func (s *Service) Delete(id string) error {
-	return s.repo.DeleteByID(id)
+// Patch aplica un JSON Merge Patch: solo cambian los campos que trae patch.
+// version es la del If-Match, como en Update.
+func (s *Service) Patch(ctx context.Context, id string, patch json.RawMessage, version uint) (*models.Song, error) {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, models.ErrSongNotFound
+	}
+
+	var input models.Song
+	if _, err := models.ApplyMergePatch(existing, patch, patchFields, &input); err != nil {
+		return nil, err
+	}
+	input.Version = version
+	return s.Update(ctx, id, &input)
+}
+
+func (s *Service) Delete(ctx context.Context, id string) error {
+	existing, err := s.repo.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteByID(id); err != nil {
+		return err
+	}
+	if existing != nil {
+		s.audit.Record(ctx, models.AuditEntry{Action: models.AuditDelete, EntityType: models.ResourceSong, EntityID: existing.ID, Before: existing})
+	}
+	return nil
 }
@@ -0,0 +1,290 @@
+package swap
+
+import (
+	"log"
+	"strings"
+
+	notificationports "melodiapp/internal/ports/notification"
+	positionports "melodiapp/internal/ports/position"
+	serviceuserports "melodiapp/internal/ports/serviceuser"
+	swapports "melodiapp/internal/ports/swap"
+	userports "melodiapp/internal/ports/user"
+	"melodiapp/models"
+)
+
+// Policy define cuándo un reclamo se aprueba sin pasar por un admin.
+type Policy struct {
+	AutoApprove bool
+}
+
+type Service struct {
+	repo               swapports.SwapRepository
+	serviceUserRepo    serviceuserports.ServiceUserRepository
+	positionRepo       positionports.PositionRepository
+	serviceUserService serviceuserports.ServiceUserService
+	userRepo           userports.UserRepository
+	notifier           notificationports.Notifier
+	policy             Policy
+}
+
+func NewService(
+	repo swapports.SwapRepository,
+	serviceUserRepo serviceuserports.ServiceUserRepository,
+	positionRepo positionports.PositionRepository,
+	serviceUserService serviceuserports.ServiceUserService,
+	userRepo userports.UserRepository,
+	notifier notificationports.Notifier,
+	policy Policy,
+) *Service {
+	return &Service{
+		repo:               repo,
+		serviceUserRepo:    serviceUserRepo,
+		positionRepo:       positionRepo,
+		serviceUserService: serviceUserService,
+		userRepo:           userRepo,
+		notifier:           notifier,
+		policy:             policy,
+	}
+}
+
+// Request ofrece el lugar del solicitante a targetIDs o, si está vacío, a todos
+// los que comparten su puesto (o su rol, si no tiene puesto asignado).
+func (s *Service) Request(serviceID uint, requester *models.User, targetIDs []uint, note string) (*models.SwapRequest, error) {
+	assignment, err := s.serviceUserRepo.GetAssignment(serviceID, requester.ID)
+	if err != nil {
+		return nil, err
+	}
+	if assignment == nil {
+		return nil, models.ErrAssignmentNotFound
+	}
+	if !assignment.IsActive() {
+		return nil, models.Conflict("assignment_not_active", "Only active assignments can be swapped")
+	}
+
+	existing, err := s.repo.ListByService(serviceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sw := range existing {
+		if sw.RequesterID == requester.ID && (sw.Status == models.SwapOpen || sw.Status == models.SwapClaimed) {
+			return nil, models.Conflict("swap_already_requested", "Swap already requested")
+		}
+	}
+
+	role := requester.Role
+	if assignment.PositionID != 0 {
+		position, err := s.positionRepo.GetByID(assignment.PositionID)
+		if err != nil {
+			return nil, err
+		}
+		if position != nil {
+			role = position.Name
+		}
+	}
+
+	swap := &models.SwapRequest{
+		ServiceID:   serviceID,
+		RequesterID: requester.ID,
+		PositionID:  assignment.PositionID,
+		Role:        role,
+		Note:        note,
+		Status:      models.SwapOpen,
+		Scope:       models.SwapScopeRole,
+	}
+
+	seen := make(map[uint]bool)
+	for _, uid := range targetIDs {
+		if uid == requester.ID || seen[uid] {
+			continue
+		}
+		seen[uid] = true
+		swap.Targets = append(swap.Targets, models.SwapRequestTarget{UserID: uid})
+	}
+	if len(swap.Targets) > 0 {
+		swap.Scope = models.SwapScopeUsers
+	} else if strings.TrimSpace(role) == "" {
+		return nil, models.Invalid("requester_without_role", "Requester has no role to match")
+	}
+
+	event := &models.SwapEvent{Action: "requested", ActorID: requester.ID, Note: note}
+	if err := s.repo.Create(swap, event); err != nil {
+		return nil, err
+	}
+	s.notifyRequested(swap, requester)
+	return s.repo.GetByID(swap.ID)
+}
+
+// notifyRequested avisa a quienes pueden reclamar la oferta.
+func (s *Service) notifyRequested(swap *models.SwapRequest, requester *models.User) {
+	users, err := s.userRepo.GetAllUsers()
+	if err != nil {
+		log.Printf("[Swap] Error loading users for swap %d: %v", swap.ID, err)
+		return
+	}
+	var userIDs []uint
+	for i := range users {
+		if users[i].ID != requester.ID && qualifies(swap, &users[i]) {
+			userIDs = append(userIDs, users[i].ID)
+		}
+	}
+
+	body := requester.Username + " busca reemplazo"
+	if swap.Role != "" {
+		body += " como " + swap.Role
+	}
+	if swap.Note != "" {
+		body += ": " + swap.Note
+	}
+	message := models.NotificationMessage{
+		Title:     "Nuevo pedido de reemplazo",
+		Body:      body,
+		ServiceID: swap.ServiceID,
+	}
+	if err := s.notifier.Notify(models.NotifySwapRequested, userIDs, message); err != nil {
+		log.Printf("[Swap] Error notifying swap %d: %v", swap.ID, err)
+	}
+}
+
+func (s *Service) GetByID(id uint) (*models.SwapRequest, error) {
+	return s.repo.GetByID(id)
+}
+
+func (s *Service) ListByService(serviceID uint) ([]models.SwapRequest, error) {
+	return s.repo.ListByService(serviceID)
+}
+
+// ListAvailable devuelve las ofertas abiertas que el usuario puede reclamar.
+func (s *Service) ListAvailable(user *models.User) ([]models.SwapRequest, error) {
+	open, err := s.repo.ListOpen()
+	if err != nil {
+		return nil, err
+	}
+	available := []models.SwapRequest{}
+	for i := range open {
+		if open[i].RequesterID != user.ID && qualifies(&open[i], user) {
+			available = append(available, open[i])
+		}
+	}
+	return available, nil
+}
+
+func qualifies(swap *models.SwapRequest, user *models.User) bool {
+	if swap.Scope == models.SwapScopeUsers {
+		return swap.IsTarget(user.ID)
+	}
+	return sameRole(user.Role, swap.Role) || sameRole(user.SecondaryRole, swap.Role)
+}
+
+func sameRole(userRole, role string) bool {
+	return userRole != "" && strings.EqualFold(strings.TrimSpace(userRole), strings.TrimSpace(role))
+}
+
+// Claim reserva la oferta para el usuario. Según la política, el intercambio
+// se ejecuta de inmediato o queda esperando la aprobación de un admin.
+func (s *Service) Claim(id uint, user *models.User) (*models.SwapRequest, error) {
+	swap, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if swap == nil {
+		return nil, models.ErrSwapNotFound
+	}
+	if swap.Status != models.SwapOpen {
+		return nil, models.Conflict("swap_request_not_open", "Swap request is not open")
+	}
+	if swap.RequesterID == user.ID || !qualifies(swap, user) {
+		return nil, models.ErrForbidden
+	}
+
+	assigned, err := s.serviceUserRepo.GetAssignment(swap.ServiceID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if assigned != nil {
+		return nil, models.ErrAlreadyAssigned
+	}
+
+	conflicts, err := s.serviceUserService.CheckConflicts(swap.ServiceID, []uint{user.ID})
+	if err != nil {
+		return nil, err
+	}
+	if len(conflicts) > 0 {
+		return nil, models.ErrAssignmentConflicts.With("conflicts", conflicts)
+	}
+
+	swap.Status = models.SwapClaimed
+	swap.ClaimedBy = user.ID
+	if err := s.repo.Update(swap, models.SwapOpen, &models.SwapEvent{Action: "claimed", ActorID: user.ID}); err != nil {
+		return nil, err
+	}
+
+	if s.policy.AutoApprove {
+		if err := s.repo.Execute(swap, &models.SwapEvent{Action: "auto_approved"}); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *Service) Approve(id uint, admin *models.User) (*models.SwapRequest, error) {
+	swap, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if swap == nil {
+		return nil, models.ErrSwapNotFound
+	}
+	if swap.Status != models.SwapClaimed {
+		return nil, models.ErrSwapNotClaimed
+	}
+
+	if err := s.repo.Execute(swap, &models.SwapEvent{Action: "approved", ActorID: admin.ID}); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// Reject descarta el reclamo y deja la oferta abierta para otra persona.
+func (s *Service) Reject(id uint, admin *models.User, note string) (*models.SwapRequest, error) {
+	swap, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if swap == nil {
+		return nil, models.ErrSwapNotFound
+	}
+	if swap.Status != models.SwapClaimed {
+		return nil, models.ErrSwapNotClaimed
+	}
+
+	swap.Status = models.SwapOpen
+	swap.ClaimedBy = 0
+	event := &models.SwapEvent{Action: models.SwapRejected, ActorID: admin.ID, Note: note}
+	if err := s.repo.Update(swap, models.SwapClaimed, event); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+func (s *Service) Cancel(id uint, user *models.User) (*models.SwapRequest, error) {
+	swap, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if swap == nil {
+		return nil, models.ErrSwapNotFound
+	}
+	if swap.RequesterID != user.ID && user.Role != "admin" {
+		return nil, models.ErrForbidden
+	}
+	if swap.Status != models.SwapOpen && swap.Status != models.SwapClaimed {
+		return nil, models.Conflict("swap_request_closed", "Swap request is already closed")
+	}
+
+	from := swap.Status
+	swap.Status = models.SwapCancelled
+	if err := s.repo.Update(swap, from, &models.SwapEvent{Action: models.SwapCancelled, ActorID: user.ID}); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
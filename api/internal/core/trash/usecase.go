@@ -0,0 +1,245 @@
+package trash
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	auditports "melodiapp/internal/ports/audit"
+	reminderports "melodiapp/internal/ports/reminder"
+	serviceports "melodiapp/internal/ports/service"
+	songports "melodiapp/internal/ports/song"
+	userports "melodiapp/internal/ports/user"
+	"melodiapp/models"
+)
+
+// lockKey evita que dos instancias purguen a la vez.
+const lockKey int64 = 0x6d656c6f03
+
+var errItemNotFound = models.NotFound("trash_item_not_found", "Item not found")
+
+type Config struct {
+	// Interval es cada cuánto se buscan elementos vencidos.
+	Interval time.Duration
+	// Retention es cuánto queda un elemento en la papelera antes de purgarse.
+	Retention time.Duration
+}
+
+// ConfigFromEnv lee TRASH_PURGE_INTERVAL (duración de Go, p. ej. "1h") y TRASH_RETENTION_DAYS.
+func ConfigFromEnv() Config {
+	config := Config{
+		Interval:  time.Hour,
+		Retention: 30 * 24 * time.Hour,
+	}
+	if d, err := time.ParseDuration(os.Getenv("TRASH_PURGE_INTERVAL")); err == nil && d > 0 {
+		config.Interval = d
+	}
+	if n, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS")); err == nil && n > 0 {
+		config.Retention = time.Duration(n) * 24 * time.Hour
+	}
+	return config
+}
+
+// bin son las operaciones de papelera de un recurso.
+type bin struct {
+	list    func() ([]models.TrashItem, error)
+	restore func(id uint) (bool, error)
+	purge   func(id uint) (bool, error)
+}
+
+type Service struct {
+	config Config
+	locker reminderports.Locker
+	bins   map[string]bin
+	audit  auditports.Recorder
+}
+
+func NewService(
+	config Config,
+	locker reminderports.Locker,
+	songRepo songports.SongRepository,
+	serviceRepo serviceports.ServiceRepository,
+	userRepo userports.UserRepository,
+	audit auditports.Recorder,
+) *Service {
+	s := &Service{config: config, locker: locker, audit: audit}
+	s.bins = map[string]bin{
+		models.ResourceSong: {
+			list: func() ([]models.TrashItem, error) {
+				songs, err := songRepo.ListDeleted()
+				if err != nil {
+					return nil, err
+				}
+				items := make([]models.TrashItem, 0, len(songs))
+				for _, song := range songs {
+					items = append(items, s.item(models.ResourceSong, song.ID, song.Name, song.DeletedAt.Time))
+				}
+				return items, nil
+			},
+			restore: songRepo.Restore,
+			purge:   songRepo.Purge,
+		},
+		models.ResourceService: {
+			list: func() ([]models.TrashItem, error) {
+				services, err := serviceRepo.ListDeleted()
+				if err != nil {
+					return nil, err
+				}
+				items := make([]models.TrashItem, 0, len(services))
+				for _, svc := range services {
+					items = append(items, s.item(models.ResourceService, svc.ID, svc.Name, svc.DeletedAt.Time))
+				}
+				return items, nil
+			},
+			restore: serviceRepo.Restore,
+			purge:   serviceRepo.Purge,
+		},
+		models.ResourceUser: {
+			list: func() ([]models.TrashItem, error) {
+				users, err := userRepo.ListDeleted()
+				if err != nil {
+					return nil, err
+				}
+				items := make([]models.TrashItem, 0, len(users))
+				for _, u := range users {
+					items = append(items, s.item(models.ResourceUser, u.ID, strings.TrimSpace(u.Username+" "+u.Lastname), u.DeletedAt.Time))
+				}
+				return items, nil
+			},
+			restore: userRepo.Restore,
+			purge:   userRepo.Purge,
+		},
+	}
+	return s
+}
+
+func (s *Service) item(resource string, id uint, name string, deletedAt time.Time) models.TrashItem {
+	return models.TrashItem{
+		Resource:  resource,
+		ID:        id,
+		Name:      name,
+		DeletedAt: deletedAt,
+		PurgeAt:   deletedAt.Add(s.config.Retention),
+	}
+}
+
+// List devuelve la papelera de un recurso, o de todos si resource está vacío,
+// con lo borrado más recientemente primero.
+func (s *Service) List(resource string) ([]models.TrashItem, error) {
+	resources := models.TrashResources
+	if resource != "" {
+		if !models.IsTrashResource(resource) {
+			return nil, models.ErrInvalidResource
+		}
+		resources = []string{resource}
+	}
+
+	items := []models.TrashItem{}
+	for _, r := range resources {
+		found, err := s.bins[r].list()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, found...)
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DeletedAt.After(items[j].DeletedAt) })
+	return items, nil
+}
+
+func (s *Service) Restore(ctx context.Context, resource string, id uint) error {
+	b, ok := s.bins[resource]
+	if !ok {
+		return models.ErrInvalidResource
+	}
+	restored, err := b.restore(id)
+	if err != nil {
+		return err
+	}
+	if !restored {
+		return errItemNotFound
+	}
+	s.record(ctx, models.AuditRestore, resource, id)
+	return nil
+}
+
+// Purge borra definitivamente un elemento de la papelera sin esperar la retención.
+func (s *Service) Purge(ctx context.Context, resource string, id uint) error {
+	b, ok := s.bins[resource]
+	if !ok {
+		return models.ErrInvalidResource
+	}
+	purged, err := b.purge(id)
+	if err != nil {
+		return err
+	}
+	if !purged {
+		return errItemNotFound
+	}
+	s.record(ctx, models.AuditPurge, resource, id)
+	return nil
+}
+
+func (s *Service) record(ctx context.Context, action string, resource string, id uint) {
+	entry := models.AuditEntry{Action: action, EntityType: resource, EntityID: id}
+	if resource == models.ResourceService {
+		entry.ServiceID = id
+	}
+	s.audit.Record(ctx, entry)
+}
+
+// Start corre PurgeExpired cada Interval hasta que se cancele ctx.
+func (s *Service) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		if err := s.PurgeExpired(ctx, time.Now()); err != nil {
+			log.Printf("[Trash] Purge failed: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeExpired borra lo que lleva en la papelera más que la retención. Solo
+// una instancia a la vez la ejecuta. En la auditoría queda sin actor (sistema).
+func (s *Service) PurgeExpired(ctx context.Context, now time.Time) error {
+	release, acquired, err := s.locker.TryLock(ctx, lockKey)
+	if err != nil {
+		return err
+	}
+	if !acquired {
+		return nil
+	}
+	defer release()
+
+	items, err := s.List("")
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.PurgeAt.After(now) {
+			continue
+		}
+		purged, err := s.bins[item.Resource].purge(item.ID)
+		if errors.Is(err, models.ErrSongInSetlists) {
+			// Queda en la papelera mientras esté en algún repertorio.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if purged {
+			s.record(ctx, models.AuditPurge, item.Resource, item.ID)
+		}
+		log.Printf("[Trash] Purged %s %d", item.Resource, item.ID)
+	}
+	return nil
+}
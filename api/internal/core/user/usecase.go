@@ -1,16 +1,22 @@
 package user
 
 import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	auditports "melodiapp/internal/ports/audit"
 	userports "melodiapp/internal/ports/user"
 	"melodiapp/models"
 )
 
 type Service struct {
-	repo userports.UserRepository
+	repo  userports.UserRepository
+	audit auditports.Recorder
 }
 
-func NewService(repo userports.UserRepository) *Service {
-	return &Service{repo: repo}
+func NewService(repo userports.UserRepository, audit auditports.Recorder) *Service {
+	return &Service{repo: repo, audit: audit}
 }
 
 func (s *Service) GetAllUsers() ([]models.User, error) {
@@ -25,32 +31,125 @@ func (s *Service) GetUserByUintID(id uint) (*models.User, error) {
 	return s.repo.GetUserByUintID(id)
 }
 
-func (s *Service) CreateUser(user *models.User) (*models.User, error) {
+func (s *Service) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
 	if err := s.repo.CreateUser(user); err != nil {
 		return nil, err
 	}
+	s.audit.Record(ctx, models.AuditEntry{Action: models.AuditCreate, EntityType: models.ResourceUser, EntityID: user.ID, After: user})
 	return user, nil
 }
 
-func (s *Service) UpdateUser(id string, updated *models.User) (*models.User, error) {
+func (s *Service) UpdateUser(ctx context.Context, id string, updated *models.User) (*models.User, error) {
 	existing, err := s.repo.GetUserByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if existing == nil {
+		return nil, models.ErrUserNotFound
+	}
+	before := *existing
 
 	existing.Username = updated.Username
 	existing.Email = updated.Email
 	if updated.Password != "" {
-		existing.Password = updated.Password
+		if err := existing.SetPassword(updated.Password); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.repo.UpdateUser(existing); err != nil {
 		return nil, err
 	}
+	s.audit.Record(ctx, models.AuditEntry{Action: models.AuditUpdate, EntityType: models.ResourceUser, EntityID: existing.ID, Before: before, After: existing})
 
 	return existing, nil
 }
 
-func (s *Service) DeleteUser(id string) error {
-	return s.repo.DeleteUserByID(id)
+// selfPatchFields los puede cambiar el propio usuario; adminPatchFields solo un
+// admin. secondary_role son los instrumentos, que cada uno elige en su perfil.
+var (
+	selfPatchFields  = []string{"username", "lastname", "email", "celphone", "password", "secondary_role", "preferred_weekdays", "preferred_services_per_month"}
+	adminPatchFields = append([]string{"role", "max_services_per_month"}, selfPatchFields...)
+)
+
+// PatchUser aplica un JSON Merge Patch sobre el usuario. asAdmin habilita los
+// campos de rol y de límite de servicios.
+func (s *Service) PatchUser(ctx context.Context, id string, patch json.RawMessage, asAdmin bool) (*models.User, error) {
+	return s.EditUser(ctx, id, patch, "", asAdmin)
+}
+
+// EditUser es PatchUser que además puede cambiar la foto de perfil (vacía = no
+// se toca). Lo usa el PUT con formulario.
+func (s *Service) EditUser(ctx context.Context, id string, patch json.RawMessage, pictureURL string, asAdmin bool) (*models.User, error) {
+	existing, err := s.repo.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, models.ErrUserNotFound
+	}
+
+	editable := selfPatchFields
+	if asAdmin {
+		editable = adminPatchFields
+	}
+	var patched models.User
+	fields, err := models.ApplyMergePatch(existing, patch, editable, &patched)
+	if err != nil {
+		return nil, err
+	}
+
+	invalid := make(map[string]string)
+	if strings.TrimSpace(patched.Username) == "" {
+		invalid["username"] = "required"
+	}
+	if !strings.Contains(patched.Email, "@") {
+		invalid["email"] = "invalid email"
+	}
+	if strings.TrimSpace(patched.Role) == "" {
+		invalid["role"] = "required"
+	}
+	if patched.MaxServicesPerMonth < 0 {
+		invalid["max_services_per_month"] = "must not be negative"
+	}
+	if !patched.PreferredWeekdays.Valid() {
+		invalid["preferred_weekdays"] = "must be days from 0 (sunday) to 6"
+	}
+	if patched.PreferredServicesPerMonth < 0 {
+		invalid["preferred_services_per_month"] = "must not be negative"
+	}
+	if _, ok := fields["password"]; ok && patched.Password == "" {
+		invalid["password"] = "required"
+	}
+	if len(invalid) > 0 {
+		return nil, models.Validation(invalid)
+	}
+	if _, ok := fields["password"]; ok {
+		if err := patched.SetPassword(patched.Password); err != nil {
+			return nil, err
+		}
+	}
+	if pictureURL != "" {
+		patched.ProfilePictureUrl = pictureURL
+	}
+
+	if err := s.repo.UpdateUser(&patched); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, models.AuditEntry{Action: models.AuditUpdate, EntityType: models.ResourceUser, EntityID: patched.ID, Before: existing, After: &patched})
+	return &patched, nil
+}
+
+func (s *Service) DeleteUser(ctx context.Context, id string) error {
+	existing, err := s.repo.GetUserByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteUserByID(id); err != nil {
+		return err
+	}
+	if existing != nil {
+		s.audit.Record(ctx, models.AuditEntry{Action: models.AuditDelete, EntityType: models.ResourceUser, EntityID: existing.ID, Before: existing})
+	}
+	return nil
 }
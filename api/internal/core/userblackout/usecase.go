@@ -0,0 +1,35 @@
+package userblackout
+
+import (
+	userblackoutports "melodiapp/internal/ports/userblackout"
+	"melodiapp/models"
+)
+
+type Service struct {
+	repo userblackoutports.UserBlackoutRepository
+}
+
+func NewService(repo userblackoutports.UserBlackoutRepository) *Service {
+	return &Service{repo: repo}
+}
+
+func (s *Service) ListByUser(userID uint) ([]models.UserBlackout, error) {
+	return s.repo.ListByUser(userID)
+}
+
+func (s *Service) Create(blackout *models.UserBlackout) (*models.UserBlackout, error) {
+	if blackout.StartDate.IsZero() || blackout.EndDate.IsZero() {
+		return nil, models.ErrIncompleteFields
+	}
+	if !blackout.EndDate.After(blackout.StartDate) {
+		return nil, models.Invalid("invalid_date_range", "end_date must be after start_date")
+	}
+	if err := s.repo.Create(blackout); err != nil {
+		return nil, err
+	}
+	return blackout, nil
+}
+
+func (s *Service) Delete(userID uint, blackoutID uint) error {
+	return s.repo.Delete(userID, blackoutID)
+}
@@ -0,0 +1,10 @@
+package attendance
+
+import "melodiapp/models"
+
+type AttendanceRepository interface {
+	Get(eventType string, eventID uint, userID uint) (*models.Attendance, error)
+	Save(record *models.Attendance) error
+	ListByEvent(eventType string, eventID uint) ([]models.Attendance, error)
+	ListByUsers(userIDs []uint) ([]models.Attendance, error)
+}
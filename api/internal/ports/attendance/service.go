@@ -0,0 +1,11 @@
+package attendance
+
+import "melodiapp/models"
+
+type AttendanceService interface {
+	CheckIn(eventType string, eventID uint, user *models.User) (*models.Attendance, error)
+	Mark(eventType string, eventID uint, userID uint, status string, note string, admin *models.User) (*models.Attendance, error)
+	Close(eventType string, eventID uint, admin *models.User) ([]models.Attendance, error)
+	ListByEvent(eventType string, eventID uint) ([]models.Attendance, error)
+	Reliability(userIDs []uint) ([]models.MemberReliability, error)
+}
@@ -0,0 +1,9 @@
+package audit
+
+import "melodiapp/models"
+
+type AuditRepository interface {
+	Create(entry *models.AuditLog) error
+	// List devuelve la página pedida, de la más reciente a la más antigua, y el total.
+	List(filter models.AuditFilter) ([]models.AuditLog, int64, error)
+}
@@ -0,0 +1,18 @@
+package audit
+
+import (
+	"context"
+
+	"melodiapp/models"
+)
+
+// Recorder es lo que usan los casos de uso para dejar constancia de un cambio.
+// El actor y la IP salen de ctx (models.WithAuditActor).
+type Recorder interface {
+	Record(ctx context.Context, entry models.AuditEntry)
+}
+
+type AuditService interface {
+	Recorder
+	List(filter models.AuditFilter) (*models.AuditPage, error)
+}
@@ -0,0 +1,11 @@
+package calendar
+
+import "melodiapp/models"
+
+type CalendarTokenRepository interface {
+	// GetByHash devuelve nil si ningún usuario tiene ese token.
+	GetByHash(tokenHash string) (*models.CalendarToken, error)
+	// Save reemplaza el token del usuario, si tenía uno.
+	Save(token *models.CalendarToken) error
+	Delete(userID uint) error
+}
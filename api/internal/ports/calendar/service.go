@@ -0,0 +1,10 @@
+package calendar
+
+import "melodiapp/models"
+
+type CalendarService interface {
+	EventsForUser(userID uint) ([]models.CalendarEvent, error)
+	EventsForToken(token string) ([]models.CalendarEvent, error)
+	IssueToken(userID uint) (*models.CalendarFeed, error)
+	RevokeToken(userID uint) error
+}
@@ -0,0 +1,8 @@
+package dresscode
+
+import "melodiapp/models"
+
+type DressCodeRepository interface {
+	ListByService(serviceID uint) ([]models.ServiceDressCode, error)
+	ReplaceForService(serviceID uint, codes []models.ServiceDressCode) error
+}
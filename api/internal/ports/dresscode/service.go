@@ -0,0 +1,9 @@
+package dresscode
+
+import "melodiapp/models"
+
+type DressCodeService interface {
+	ListByService(serviceID uint) ([]models.ServiceDressCode, error)
+	SetForService(serviceID uint, codes []models.ServiceDressCode) ([]models.ServiceDressCode, error)
+	SuggestPalettes(base string, scheme string, serviceID uint, recent int) ([]models.PaletteSuggestion, error)
+}
@@ -0,0 +1,10 @@
+package impact
+
+import "melodiapp/models"
+
+type ImpactRepository interface {
+	Exists(resource string, id uint) (bool, error)
+	Count(ref models.Reference, id uint) (int64, error)
+	// ServiceIDs devuelve los servicios de las filas que referencian id.
+	ServiceIDs(ref models.Reference, id uint) ([]uint, error)
+}
@@ -0,0 +1,7 @@
+package impact
+
+import "melodiapp/models"
+
+type ImpactService interface {
+	Preview(resource string, id uint) (*models.DeleteImpact, error)
+}
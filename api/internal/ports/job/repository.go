@@ -0,0 +1,29 @@
+package job
+
+import (
+	"time"
+
+	"melodiapp/models"
+)
+
+type JobRepository interface {
+	Create(job *models.Job) error
+	GetByID(id uint) (*models.Job, error)
+	List(filter models.JobFilter) ([]models.Job, error)
+	CountByStatus() (map[string]int64, error)
+
+	// Claim toma el próximo trabajo vencido sin bloquear a otros workers
+	// (FOR UPDATE SKIP LOCKED) y lo marca como running.
+	Claim(worker string, now time.Time) (*models.Job, error)
+	// Complete y Fail solo tocan el trabajo si worker todavía lo tiene tomado;
+	// si no, devuelven ErrJobLockLost.
+	Complete(id uint, worker string, now time.Time) error
+	// Fail guarda el error; con retryAt vuelve a la cola, sin él queda dead.
+	Fail(id uint, worker string, message string, retryAt *time.Time) error
+	// Requeue devuelve a la cola los trabajos running tomados antes de staleBefore
+	// (un worker que murió sin terminarlos). Los que ya no tienen intentos
+	// quedan dead.
+	Requeue(staleBefore time.Time, now time.Time) (requeued int64, dead int64, err error)
+	// Retry vuelve a encolar un trabajo queued o dead; false si estaba en otro estado.
+	Retry(id uint, now time.Time) (bool, error)
+}
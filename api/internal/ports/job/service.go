@@ -0,0 +1,23 @@
+package job
+
+import (
+	"context"
+
+	"melodiapp/models"
+)
+
+// Handler procesa el payload de un trabajo. Un error provoca un reintento.
+type Handler func(ctx context.Context, payload []byte) error
+
+// Enqueuer es lo que usan los demás casos de uso para mandar trabajo a segundo plano.
+type Enqueuer interface {
+	Enqueue(jobType string, payload any, opts models.JobOptions) (*models.Job, error)
+}
+
+type JobService interface {
+	Enqueuer
+	List(filter models.JobFilter) ([]models.Job, error)
+	GetByID(id uint) (*models.Job, error)
+	Stats() (map[string]int64, error)
+	Retry(id uint) (*models.Job, error)
+}
@@ -0,0 +1,23 @@
+package live
+
+import (
+	"context"
+
+	"melodiapp/models"
+)
+
+type LiveSessionRepository interface {
+	GetByService(serviceID uint) (*models.LiveSession, error)
+	Save(session *models.LiveSession) error
+	// Update guarda la sesión solo si su versión sigue siendo la que se leyó.
+	Update(session *models.LiveSession) error
+}
+
+// LiveBus avisa a todas las instancias de la API que cambió el estado en vivo
+// de un servicio.
+type LiveBus interface {
+	Publish(serviceID uint) error
+	// Listen llama a changed con cada aviso de las otras instancias hasta que
+	// ctx termine o se corte la conexión.
+	Listen(ctx context.Context, changed func(serviceID uint)) error
+}
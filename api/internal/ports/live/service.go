@@ -0,0 +1,14 @@
+package live
+
+import "melodiapp/models"
+
+type LiveService interface {
+	Start(serviceID uint, operator *models.User) (*models.LiveState, error)
+	Advance(serviceID uint, operator *models.User) (*models.LiveState, error)
+	Rewind(serviceID uint, operator *models.User) (*models.LiveState, error)
+	GoTo(serviceID uint, index int, operator *models.User) (*models.LiveState, error)
+	End(serviceID uint, operator *models.User) (*models.LiveState, error)
+	State(serviceID uint) (*models.LiveState, error)
+	// Subscribe devuelve un canal con cada cambio de estado y una función para dejar de escuchar.
+	Subscribe(serviceID uint) (<-chan *models.LiveState, func())
+}
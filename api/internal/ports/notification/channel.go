@@ -0,0 +1,11 @@
+package notification
+
+import "melodiapp/models"
+
+// Channel entrega una notificación fuera de la app (email, bots, etc.).
+type Channel interface {
+	Name() string
+	// Enabled es false cuando al canal le falta configuración.
+	Enabled() bool
+	Send(user *models.User, notification *models.Notification) error
+}
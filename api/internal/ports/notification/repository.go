@@ -0,0 +1,14 @@
+package notification
+
+import "melodiapp/models"
+
+type NotificationRepository interface {
+	Create(notifications []models.Notification) error
+	ListByUser(userID uint, unreadOnly bool, limit int) ([]models.Notification, error)
+	CountUnread(userID uint) (int64, error)
+	// MarkRead marca como leídas ids del usuario; si ids está vacío, todas.
+	MarkRead(userID uint, ids []uint) error
+
+	ListPreferences(userIDs []uint) ([]models.NotificationPreference, error)
+	SavePreferences(userID uint, prefs []models.NotificationPreference) error
+}
@@ -0,0 +1,17 @@
+package notification
+
+import "melodiapp/models"
+
+// Notifier es lo que usan los demás casos de uso para avisar a los usuarios.
+type Notifier interface {
+	Notify(event string, userIDs []uint, message models.NotificationMessage) error
+}
+
+type NotificationService interface {
+	Notifier
+	List(userID uint, unreadOnly bool, limit int) ([]models.Notification, error)
+	CountUnread(userID uint) (int64, error)
+	MarkRead(userID uint, ids []uint) error
+	Preferences(userID uint) ([]models.NotificationPreference, error)
+	UpdatePreferences(userID uint, prefs []models.NotificationPreference) ([]models.NotificationPreference, error)
+}
@@ -0,0 +1,13 @@
+package outfit
+
+import "melodiapp/models"
+
+type OutfitRepository interface {
+	GetAll() ([]models.Outfit, error)
+	GetByID(id uint) (*models.Outfit, error)
+	GetByIDs(ids []uint) ([]models.Outfit, error)
+	Create(outfit *models.Outfit) error
+	Update(outfit *models.Outfit) error
+	DeleteByID(id uint) error
+	CountServices(id uint) (int64, error)
+}
@@ -0,0 +1,12 @@
+package outfit
+
+import "melodiapp/models"
+
+type OutfitService interface {
+	GetAll() ([]models.Outfit, error)
+	GetByID(id uint) (*models.Outfit, error)
+	Create(outfit *models.Outfit) (*models.Outfit, error)
+	Update(id uint, input *models.Outfit) (*models.Outfit, error)
+	SetImage(id uint, imageURL string) (*models.Outfit, error)
+	Delete(id uint) error
+}
@@ -0,0 +1,17 @@
+package position
+
+import "melodiapp/models"
+
+type PositionRepository interface {
+	GetAll() ([]models.Position, error)
+	GetByID(id uint) (*models.Position, error)
+	GetByIDs(ids []uint) ([]models.Position, error)
+	Create(position *models.Position) error
+	Update(position *models.Position) error
+	DeleteByID(id uint) error
+	CountUsage(id uint) (int64, error)
+
+	ListSlotsByService(serviceID uint) ([]models.ServicePosition, error)
+	ListSlotsByServices(serviceIDs []uint) ([]models.ServicePosition, error)
+	ReplaceSlots(serviceID uint, slots []models.ServicePosition) error
+}
@@ -0,0 +1,14 @@
+package position
+
+import "melodiapp/models"
+
+type PositionService interface {
+	GetAll() ([]models.Position, error)
+	GetByID(id uint) (*models.Position, error)
+	Create(position *models.Position) (*models.Position, error)
+	Update(id uint, input *models.Position) (*models.Position, error)
+	Delete(id uint) error
+
+	SetServiceSlots(serviceID uint, slots []models.ServicePosition) error
+	ServiceBreakdown(serviceID uint) ([]models.PositionSlot, error)
+}
@@ -0,0 +1,22 @@
+package rehearsal
+
+import (
+	"time"
+
+	"melodiapp/models"
+)
+
+type RehearsalRepository interface {
+	List(from, to time.Time) ([]models.Rehearsal, error)
+	ListByUser(userID uint) ([]models.Rehearsal, error)
+	GetByID(id uint) (*models.Rehearsal, error)
+	Create(rehearsal *models.Rehearsal, serviceIDs []uint) error
+	Update(rehearsal *models.Rehearsal, serviceIDs []uint) error
+	DeleteByID(id uint) error
+
+	// SyncAttendees deja como asistentes exactamente a userIDs, conservando
+	// las respuestas de quienes ya estaban.
+	SyncAttendees(rehearsalID uint, userIDs []uint) error
+	GetAttendee(rehearsalID uint, userID uint) (*models.RehearsalAttendee, error)
+	UpdateAttendee(attendee *models.RehearsalAttendee) error
+}
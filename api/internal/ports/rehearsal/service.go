@@ -0,0 +1,18 @@
+package rehearsal
+
+import (
+	"time"
+
+	"melodiapp/models"
+)
+
+type RehearsalService interface {
+	List(from, to time.Time) ([]models.Rehearsal, error)
+	ListByUser(userID uint) ([]models.Rehearsal, error)
+	GetByID(id uint) (*models.RehearsalDetail, error)
+	Create(rehearsal *models.Rehearsal, serviceIDs []uint) (*models.RehearsalDetail, error)
+	Update(id uint, rehearsal *models.Rehearsal, serviceIDs []uint) (*models.RehearsalDetail, error)
+	Delete(id uint) error
+	SyncAttendees(id uint) (*models.RehearsalDetail, error)
+	RSVP(id uint, userID uint, response string, note string) (*models.RehearsalAttendee, error)
+}
@@ -0,0 +1,18 @@
+package reminder
+
+import (
+	"context"
+
+	"melodiapp/models"
+)
+
+type ReminderLogRepository interface {
+	// Record guarda el envío y devuelve false si ya estaba registrado.
+	Record(entry *models.ReminderLog) (bool, error)
+}
+
+// Locker da exclusión entre instancias de la API.
+type Locker interface {
+	// TryLock no espera: si otra instancia tiene la llave devuelve acquired = false.
+	TryLock(ctx context.Context, key int64) (release func(), acquired bool, err error)
+}
@@ -0,0 +1,14 @@
+package revision
+
+import "melodiapp/models"
+
+type RevisionRepository interface {
+	Create(revision *models.Revision) error
+	// CreateNext guarda la revisión con la versión siguiente de la entidad, salvo
+	// que la última tenga la misma foto (devuelve false).
+	CreateNext(revision *models.Revision) (bool, error)
+	// Latest devuelve nil, nil si la entidad todavía no tiene revisiones.
+	Latest(entityType string, entityID uint) (*models.Revision, error)
+	GetByVersion(entityType string, entityID uint, version int) (*models.Revision, error)
+	List(entityType string, entityID uint) ([]models.Revision, error)
+}
@@ -0,0 +1,16 @@
+package revision
+
+import (
+	"context"
+
+	"melodiapp/models"
+)
+
+type RevisionService interface {
+	List(entityType string, entityID uint) ([]models.Revision, error)
+	Diff(entityType string, entityID uint, from int, to int) (*models.RevisionDiff, error)
+	// RevertSong y RevertService reciben en expected la versión actual que leyó
+	// el cliente (If-Match).
+	RevertSong(ctx context.Context, songID uint, version int, expected uint) (*models.Song, error)
+	RevertService(ctx context.Context, serviceID uint, version int, expected uint, override bool) (*models.ServiceDetail, []models.AssignmentConflict, error)
+}
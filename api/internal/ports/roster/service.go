@@ -0,0 +1,12 @@
+package roster
+
+import (
+	"context"
+
+	"melodiapp/models"
+)
+
+type RosterService interface {
+	Generate(options models.RosterOptions) (*models.RosterDraft, error)
+	Commit(ctx context.Context, draft *models.RosterDraft) error
+}
@@ -0,0 +1,13 @@
+package runsheet
+
+import "melodiapp/models"
+
+type RunSheetRepository interface {
+	ListByService(serviceID uint) ([]models.RunSheetItem, error)
+	GetItem(serviceID uint, itemID uint) (*models.RunSheetItem, error)
+	CreateItem(item *models.RunSheetItem) error
+	UpdateItem(item *models.RunSheetItem) error
+	DeleteItem(serviceID uint, itemID uint) error
+	Reorder(serviceID uint, itemIDs []uint) error
+	ReplaceItems(serviceID uint, items []models.RunSheetItem) error
+}
@@ -0,0 +1,12 @@
+package runsheet
+
+import "melodiapp/models"
+
+type RunSheetService interface {
+	Get(serviceID uint) (*models.RunSheet, error)
+	Replace(serviceID uint, items []models.RunSheetItem) (*models.RunSheet, error)
+	AddItem(serviceID uint, item *models.RunSheetItem) (*models.RunSheet, error)
+	UpdateItem(serviceID uint, itemID uint, item *models.RunSheetItem) (*models.RunSheet, error)
+	RemoveItem(serviceID uint, itemID uint) (*models.RunSheet, error)
+	Reorder(serviceID uint, itemIDs []uint) (*models.RunSheet, error)
+}
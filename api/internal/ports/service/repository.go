@@ -5,7 +5,12 @@ import "melodiapp/models"
 type ServiceRepository interface {
 	GetAll() ([]models.Service, error)
 	GetByID(id string) (*models.Service, error)
+	GetByIDs(ids []uint) ([]models.Service, error)
 	Create(svc *models.Service) error
 	Update(svc *models.Service) error
 	DeleteByID(id string) error
+
+	ListDeleted() ([]models.Service, error)
+	Restore(id uint) (bool, error)
+	Purge(id uint) (bool, error)
 }
@@ -1,11 +1,25 @@
 package service
 
-import "melodiapp/models"
+import (
+	"context"
+	"encoding/json"
+
+	"melodiapp/models"
+)
 
 type ServiceService interface {
 	GetAll() ([]models.Service, error)
 	GetByID(id string) (*models.Service, error)
-	Create(svc *models.Service) (*models.Service, error)
-	Update(id string, input *models.Service) (*models.Service, error)
-	Delete(id string) error
+	Create(ctx context.Context, svc *models.Service) (*models.Service, error)
+	Update(ctx context.Context, id string, input *models.Service) (*models.Service, error)
+	Delete(ctx context.Context, id string) error
+	Details(svc *models.Service) (*models.ServiceDetail, error)
+	// DetailsList es Details para varios servicios con una consulta por tabla.
+	DetailsList(services []models.Service) ([]models.ServiceDetail, error)
+
+	// CreateBundle y SaveBundle guardan el servicio con sus listas de forma atómica.
+	CreateBundle(ctx context.Context, bundle *models.ServiceBundle) (*models.ServiceDetail, []models.AssignmentConflict, error)
+	SaveBundle(ctx context.Context, id string, bundle *models.ServiceBundle) (*models.ServiceDetail, []models.AssignmentConflict, error)
+	// Patch cambia solo lo que trae el JSON Merge Patch.
+	Patch(ctx context.Context, id string, patch json.RawMessage, version uint) (*models.ServiceDetail, []models.AssignmentConflict, error)
 }
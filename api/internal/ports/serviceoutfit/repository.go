@@ -4,8 +4,10 @@ import "melodiapp/models"
 
 type ServiceOutfitRepository interface {
 	AddOutfits(serviceID uint, outfitIDs []uint) error
+	ReplaceOutfits(serviceID uint, outfitIDs []uint) error
 
 	ListByService(serviceID uint) ([]models.ServiceOutfit, error)
+	ListByServices(serviceIDs []uint) ([]models.ServiceOutfit, error)
 
 	Remove(serviceID uint, outfitID uint) error
 }
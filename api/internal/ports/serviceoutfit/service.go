@@ -1,9 +1,14 @@
 package serviceoutfit
 
-import "melodiapp/models"
+import (
+	"context"
+
+	"melodiapp/models"
+)
 
 type ServiceOutfitService interface {
-	AssignOutfits(serviceID uint, outfitIDs []uint) error
+	AssignOutfits(ctx context.Context, serviceID uint, outfitIDs []uint) error
+	ReplaceOutfits(ctx context.Context, serviceID uint, outfitIDs []uint) error
 	ListByService(serviceID uint) ([]models.ServiceOutfit, error)
-	Remove(serviceID uint, outfitID uint) error
+	Remove(ctx context.Context, serviceID uint, outfitID uint) error
 }
@@ -3,7 +3,9 @@ package servicesong
 import "melodiapp/models"
 
 type ServiceSongRepository interface {
-	AddSongs(serviceID uint, songIDs []uint) error
+	ReplaceSongs(serviceID uint, songIDs []uint) error
+	ApplySongDiff(serviceID uint, add []uint, remove []uint) error
 	ListByService(serviceID uint) ([]models.ServiceSong, error)
+	ListByServices(serviceIDs []uint) ([]models.ServiceSong, error)
 	Remove(serviceID uint, songID uint) error
 }
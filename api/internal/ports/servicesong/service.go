@@ -1,9 +1,15 @@
 package servicesong
 
-import "melodiapp/models"
+import (
+	"context"
+
+	"melodiapp/models"
+)
 
 type ServiceSongService interface {
-	AssignSongs(serviceID uint, songIDs []uint) error
+	AssignSongs(ctx context.Context, serviceID uint, songIDs []uint) error
+	AddSong(ctx context.Context, serviceID uint, songID uint) error
+	UpdateSongs(ctx context.Context, serviceID uint, add []uint, remove []uint) error
 	ListByService(serviceID uint) ([]models.ServiceSong, error)
-	Remove(serviceID uint, songID uint) error
+	Remove(ctx context.Context, serviceID uint, songID uint) error
 }
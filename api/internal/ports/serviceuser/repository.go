@@ -3,7 +3,13 @@ package serviceuser
 import "melodiapp/models"
 
 type ServiceUserRepository interface {
-	AddUsers(serviceID uint, userIDs []uint) error
+	ReplaceUsers(serviceID uint, assignments []models.UserAssignment) error
+	ApplyUserDiff(serviceID uint, add []models.UserAssignment, remove []uint) error
+	GetAssignment(serviceID uint, userID uint) (*models.ServiceUser, error)
 	ListByService(serviceID uint) ([]models.ServiceUser, error)
-	UpdateStatus(serviceID uint, userID uint, status string) error
+	ListByServices(serviceIDs []uint) ([]models.ServiceUser, error)
+	ListByUsers(userIDs []uint) ([]models.ServiceUser, error)
+	UpdateStatus(change *models.ServiceUserStatusChange) error
+	ListStatusHistory(serviceID uint) ([]models.ServiceUserStatusChange, error)
+	SetPosition(serviceID uint, userID uint, positionID uint) error
 }
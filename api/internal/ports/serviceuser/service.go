@@ -1,9 +1,19 @@
 package serviceuser
 
-import "melodiapp/models"
+import (
+	"context"
+
+	"melodiapp/models"
+)
 
 type ServiceUserService interface {
-	AssignUsers(serviceID uint, userIDs []uint) error
+	AssignUsers(ctx context.Context, serviceID uint, assignments []models.UserAssignment, override bool) ([]models.AssignmentConflict, error)
+	AddUser(ctx context.Context, serviceID uint, assignment models.UserAssignment, override bool) ([]models.AssignmentConflict, error)
+	RemoveUser(ctx context.Context, serviceID uint, userID uint) error
+	UpdateTeam(ctx context.Context, serviceID uint, add []models.UserAssignment, remove []uint, override bool) ([]models.AssignmentConflict, error)
+	CheckConflicts(serviceID uint, userIDs []uint) ([]models.AssignmentConflict, error)
 	ListByService(serviceID uint) ([]models.ServiceUser, error)
-	ChangeStatus(serviceID uint, userID uint, status string) error
+	ChangeStatus(ctx context.Context, serviceID uint, userID uint, status string, reason string) (*models.ServiceUser, error)
+	StatusHistory(serviceID uint) ([]models.ServiceUserStatusChange, error)
+	ChangePosition(ctx context.Context, serviceID uint, userID uint, positionID uint) error
 }
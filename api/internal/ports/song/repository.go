@@ -5,7 +5,12 @@ import "melodiapp/models"
 type SongRepository interface {
 	GetAll() ([]models.Song, error)
 	GetByID(id string) (*models.Song, error)
+	GetByIDs(ids []uint) ([]models.Song, error)
 	Create(song *models.Song) error
 	Update(song *models.Song) error
 	DeleteByID(id string) error
+
+	ListDeleted() ([]models.Song, error)
+	Restore(id uint) (bool, error)
+	Purge(id uint) (bool, error)
 }
@@ -1,11 +1,17 @@
 package song
 
-import "melodiapp/models"
+import (
+	"context"
+	"encoding/json"
+
+	"melodiapp/models"
+)
 
 type SongService interface {
 	GetAll() ([]models.Song, error)
 	GetByID(id string) (*models.Song, error)
-	Create(song *models.Song) (*models.Song, error)
-	Update(id string, input *models.Song) (*models.Song, error)
-	Delete(id string) error
+	Create(ctx context.Context, song *models.Song) (*models.Song, error)
+	Update(ctx context.Context, id string, input *models.Song) (*models.Song, error)
+	Patch(ctx context.Context, id string, patch json.RawMessage, version uint) (*models.Song, error)
+	Delete(ctx context.Context, id string) error
 }
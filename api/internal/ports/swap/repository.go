@@ -0,0 +1,15 @@
+package swap
+
+import "melodiapp/models"
+
+type SwapRepository interface {
+	Create(swap *models.SwapRequest, event *models.SwapEvent) error
+	GetByID(id uint) (*models.SwapRequest, error)
+	ListByService(serviceID uint) ([]models.SwapRequest, error)
+	ListOpen() ([]models.SwapRequest, error)
+	// Update guarda el nuevo estado solo si el intercambio sigue en fromStatus.
+	Update(swap *models.SwapRequest, fromStatus string, event *models.SwapEvent) error
+	// Execute mueve la asignación del solicitante al nuevo usuario y marca el
+	// intercambio como aprobado, todo en una sola transacción.
+	Execute(swap *models.SwapRequest, event *models.SwapEvent) error
+}
@@ -0,0 +1,14 @@
+package swap
+
+import "melodiapp/models"
+
+type SwapService interface {
+	Request(serviceID uint, requester *models.User, targetIDs []uint, note string) (*models.SwapRequest, error)
+	GetByID(id uint) (*models.SwapRequest, error)
+	ListByService(serviceID uint) ([]models.SwapRequest, error)
+	ListAvailable(user *models.User) ([]models.SwapRequest, error)
+	Claim(id uint, user *models.User) (*models.SwapRequest, error)
+	Approve(id uint, admin *models.User) (*models.SwapRequest, error)
+	Reject(id uint, admin *models.User, note string) (*models.SwapRequest, error)
+	Cancel(id uint, user *models.User) (*models.SwapRequest, error)
+}
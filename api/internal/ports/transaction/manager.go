@@ -0,0 +1,34 @@
+package transaction
+
+import (
+	"context"
+
+	outfitports "melodiapp/internal/ports/outfit"
+	positionports "melodiapp/internal/ports/position"
+	serviceports "melodiapp/internal/ports/service"
+	serviceoutfitports "melodiapp/internal/ports/serviceoutfit"
+	servicesongports "melodiapp/internal/ports/servicesong"
+	serviceuserports "melodiapp/internal/ports/serviceuser"
+	songports "melodiapp/internal/ports/song"
+	userports "melodiapp/internal/ports/user"
+	userblackoutports "melodiapp/internal/ports/userblackout"
+)
+
+// Repositories son los repositorios ligados a una misma transacción.
+type Repositories struct {
+	Services       serviceports.ServiceRepository
+	ServiceUsers   serviceuserports.ServiceUserRepository
+	ServiceSongs   servicesongports.ServiceSongRepository
+	ServiceOutfits serviceoutfitports.ServiceOutfitRepository
+	Songs          songports.SongRepository
+	Outfits        outfitports.OutfitRepository
+	Users          userports.UserRepository
+	Positions      positionports.PositionRepository
+	Blackouts      userblackoutports.UserBlackoutRepository
+}
+
+// Manager corre fn dentro de una transacción: si fn devuelve un error se
+// revierte todo lo que hizo con repos.
+type Manager interface {
+	WithinTx(ctx context.Context, fn func(repos Repositories) error) error
+}
@@ -0,0 +1,13 @@
+package trash
+
+import (
+	"context"
+
+	"melodiapp/models"
+)
+
+type TrashService interface {
+	List(resource string) ([]models.TrashItem, error)
+	Restore(ctx context.Context, resource string, id uint) error
+	Purge(ctx context.Context, resource string, id uint) error
+}
@@ -6,8 +6,13 @@ type UserRepository interface {
 	GetAllUsers() ([]models.User, error)
 	GetUserByID(id string) (*models.User, error)
 	GetUserByUintID(id uint) (*models.User, error)
+	GetUsersByIDs(ids []uint) ([]models.User, error)
 	GetUserByEmail(email string) (*models.User, error)
 	CreateUser(user *models.User) error
 	UpdateUser(user *models.User) error
 	DeleteUserByID(id string) error
+
+	ListDeleted() ([]models.User, error)
+	Restore(id uint) (bool, error)
+	Purge(id uint) (bool, error)
 }
@@ -1,12 +1,19 @@
 package user
 
-import "melodiapp/models"
+import (
+	"context"
+	"encoding/json"
+
+	"melodiapp/models"
+)
 
 type UserService interface {
 	GetAllUsers() ([]models.User, error)
 	GetUserByID(id string) (*models.User, error)
 	GetUserByUintID(id uint) (*models.User, error)
-	CreateUser(user *models.User) (*models.User, error)
-	UpdateUser(id string, updated *models.User) (*models.User, error)
-	DeleteUser(id string) error
+	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
+	UpdateUser(ctx context.Context, id string, updated *models.User) (*models.User, error)
+	PatchUser(ctx context.Context, id string, patch json.RawMessage, asAdmin bool) (*models.User, error)
+	EditUser(ctx context.Context, id string, patch json.RawMessage, pictureURL string, asAdmin bool) (*models.User, error)
+	DeleteUser(ctx context.Context, id string) error
 }
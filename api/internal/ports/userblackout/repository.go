@@ -0,0 +1,10 @@
+package userblackout
+
+import "melodiapp/models"
+
+type UserBlackoutRepository interface {
+	ListByUser(userID uint) ([]models.UserBlackout, error)
+	ListByUsers(userIDs []uint) ([]models.UserBlackout, error)
+	Create(blackout *models.UserBlackout) error
+	Delete(userID uint, blackoutID uint) error
+}
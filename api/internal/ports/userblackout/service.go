@@ -0,0 +1,9 @@
+package userblackout
+
+import "melodiapp/models"
+
+type UserBlackoutService interface {
+	ListByUser(userID uint) ([]models.UserBlackout, error)
+	Create(blackout *models.UserBlackout) (*models.UserBlackout, error)
+	Delete(userID uint, blackoutID uint) error
+}
@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+// Tipos de evento con asistencia.
+const (
+	EventService   = "service"
+	EventRehearsal = "rehearsal"
+)
+
+// Estados de asistencia. A diferencia de ServiceUser.Status (intención),
+// registran si la persona realmente estuvo.
+const (
+	AttendancePresent = "present"
+	AttendanceLate    = "late"
+	AttendanceNoShow  = "no_show"
+	AttendanceExcused = "excused"
+)
+
+const (
+	CheckInSelf  = "self"
+	CheckInAdmin = "admin"
+)
+
+type Attendance struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	EventType   string     `json:"event_type" gorm:"column:event_type;uniqueIndex:idx_attendance_event_user"`
+	EventID     uint       `json:"event_id" gorm:"column:event_id;uniqueIndex:idx_attendance_event_user"`
+	UserID      uint       `json:"user_id" gorm:"column:user_id;uniqueIndex:idx_attendance_event_user;index"`
+	Status      string     `json:"status"`
+	CheckedInAt *time.Time `json:"checked_in_at" gorm:"column:checked_in_at"`
+	LateMinutes int        `json:"late_minutes" gorm:"column:late_minutes"`
+	Source      string     `json:"source"`
+	MarkedBy    uint       `json:"marked_by" gorm:"column:marked_by"`
+	Note        string     `json:"note"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+func IsAttendanceStatus(value string) bool {
+	switch value {
+	case AttendancePresent, AttendanceLate, AttendanceNoShow, AttendanceExcused:
+		return true
+	}
+	return false
+}
+
+// MemberReliability resume la asistencia de un integrante. Las ausencias
+// justificadas no cuentan; una llegada tarde vale medio punto.
+type MemberReliability struct {
+	UserID  uint    `json:"user_id"`
+	Total   int     `json:"total"`
+	Present int     `json:"present"`
+	Late    int     `json:"late"`
+	NoShow  int     `json:"no_show"`
+	Excused int     `json:"excused"`
+	Score   float64 `json:"score"`
+}
+
+// ComputeReliability agrupa los registros por usuario. Sin historial el puntaje es 1.
+func ComputeReliability(userIDs []uint, records []Attendance) map[uint]*MemberReliability {
+	stats := make(map[uint]*MemberReliability, len(userIDs))
+	for _, uid := range userIDs {
+		stats[uid] = &MemberReliability{UserID: uid, Score: 1}
+	}
+	for _, r := range records {
+		st, ok := stats[r.UserID]
+		if !ok {
+			continue
+		}
+		st.Total++
+		switch r.Status {
+		case AttendancePresent:
+			st.Present++
+		case AttendanceLate:
+			st.Late++
+		case AttendanceNoShow:
+			st.NoShow++
+		case AttendanceExcused:
+			st.Excused++
+		}
+	}
+	for _, st := range stats {
+		counted := st.Total - st.Excused
+		if counted > 0 {
+			st.Score = (float64(st.Present) + float64(st.Late)*0.5) / float64(counted)
+		}
+	}
+	return stats
+}
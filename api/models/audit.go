@@ -0,0 +1,86 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Acciones que quedan en la auditoría.
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditDelete  = "delete"
+	AuditRestore = "restore"
+	AuditPurge   = "purge"
+)
+
+// Entidades auditadas además de los recursos (ResourceSong, ResourceUser, ...).
+const (
+	AuditServiceSongs   = "service_songs"
+	AuditServiceUsers   = "service_users"
+	AuditServiceOutfits = "service_outfits"
+)
+
+// AuditLog es una acción registrada. Changes tiene, por cada campo que cambió,
+// {"from": valor anterior, "to": valor nuevo}.
+type AuditLog struct {
+	ID         uint            `json:"id" gorm:"primaryKey"`
+	ActorID    uint            `json:"actor_id" gorm:"column:actor_id;index"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type" gorm:"column:entity_type;index:idx_audit_entity,priority:1"`
+	EntityID   uint            `json:"entity_id" gorm:"column:entity_id;index:idx_audit_entity,priority:2"`
+	ServiceID  uint            `json:"service_id" gorm:"column:service_id;index"`
+	Before     json.RawMessage `json:"before" gorm:"type:jsonb"`
+	After      json.RawMessage `json:"after" gorm:"type:jsonb"`
+	Changes    json.RawMessage `json:"changes" gorm:"type:jsonb"`
+	IP         string          `json:"ip"`
+	CreatedAt  time.Time       `json:"created_at" gorm:"index"`
+}
+
+// AuditEntry es lo que registra un caso de uso. Before y After se guardan como
+// JSON; ServiceID agrupa la entrada en la actividad del servicio.
+type AuditEntry struct {
+	Action     string
+	EntityType string
+	EntityID   uint
+	ServiceID  uint
+	Before     any
+	After      any
+}
+
+// AuditActor es quién hizo la petición. UserID 0 es el sistema (p. ej. la purga programada).
+type AuditActor struct {
+	UserID uint
+	IP     string
+}
+
+type auditActorKey struct{}
+
+func WithAuditActor(ctx context.Context, actor AuditActor) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+func AuditActorFrom(ctx context.Context) AuditActor {
+	actor, _ := ctx.Value(auditActorKey{}).(AuditActor)
+	return actor
+}
+
+type AuditFilter struct {
+	ActorID    uint
+	Action     string
+	EntityType string
+	EntityID   uint
+	ServiceID  uint
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	PageSize   int
+}
+
+type AuditPage struct {
+	Items    []AuditLog `json:"items"`
+	Total    int64      `json:"total"`
+	Page     int        `json:"page"`
+	PageSize int        `json:"page_size"`
+}
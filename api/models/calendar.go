@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+const (
+	CalendarService   = "service"
+	CalendarRehearsal = "rehearsal"
+)
+
+// CalendarEvent es una entrada del calendario de un usuario.
+type CalendarEvent struct {
+	UID         string    `json:"uid"`
+	Kind        string    `json:"kind"`
+	RefID       uint      `json:"ref_id"`
+	Title       string    `json:"title"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	Location    string    `json:"location"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+}
+
+// CalendarToken da acceso de solo lectura al feed .ics de un usuario desde
+// apps de calendario, que no pueden mandar headers. No vence: se revoca o se
+// rota. Solo se guarda el hash, el token se muestra una vez al generarlo.
+type CalendarToken struct {
+	UserID    uint      `json:"user_id" gorm:"primaryKey;column:user_id"`
+	TokenHash string    `json:"-" gorm:"column:token_hash;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CalendarFeed es lo que recibe el usuario al generar su token.
+type CalendarFeed struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
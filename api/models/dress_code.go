@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// ServiceDressCode es el código de vestimenta de un grupo dentro del servicio.
+// Group puede ser un puesto ("Voces") o un grupo como "hombres"/"mujeres";
+// si PositionID está definido, el grupo es ese puesto.
+type ServiceDressCode struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ServiceID  uint      `json:"service_id" gorm:"column:service_id;index"`
+	Group      string    `json:"group" gorm:"column:group_name"`
+	PositionID uint      `json:"position_id" gorm:"column:position_id"`
+	OutfitID   uint      `json:"outfit_id" gorm:"column:outfit_id"`
+	Colors     HexColors `json:"colors" gorm:"type:text"`
+	Notes      string    `json:"notes"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+const (
+	PaletteComplementary = "complementary"
+	PaletteAnalogous     = "analogous"
+)
+
+// PaletteMatch indica que un color sugerido se parece a uno usado hace poco.
+type PaletteMatch struct {
+	Color     string `json:"color"`
+	UsedColor string `json:"used_color"`
+	OutfitID  uint   `json:"outfit_id"`
+	ServiceID uint   `json:"service_id"`
+}
+
+type PaletteSuggestion struct {
+	Scheme        string         `json:"scheme"`
+	Colors        HexColors      `json:"colors"`
+	RecentMatches []PaletteMatch `json:"recent_matches"`
+	// Fresh es true cuando ningún color se parece a los outfits recientes.
+	Fresh bool `json:"fresh"`
+}
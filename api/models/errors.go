@@ -0,0 +1,123 @@
+package models
+
+import "strings"
+
+// ErrorKind clasifica un error del dominio. El núcleo no sabe de HTTP: el
+// middleware de errores traduce cada tipo a un status.
+type ErrorKind string
+
+const (
+	// KindInvalid es una petición mal armada: faltan datos o un valor no es válido.
+	KindInvalid ErrorKind = "invalid"
+	// KindValidation trae en Fields el motivo de cada campo rechazado.
+	KindValidation   ErrorKind = "validation"
+	KindUnauthorized ErrorKind = "unauthorized"
+	KindForbidden    ErrorKind = "forbidden"
+	KindNotFound     ErrorKind = "not_found"
+	// KindConflict es un cambio que el estado actual del recurso no permite.
+	KindConflict ErrorKind = "conflict"
+	// KindStale es una edición hecha sobre una versión que ya no es la actual.
+	KindStale ErrorKind = "stale"
+)
+
+// Error es un error del dominio con un código estable para los clientes: Code
+// no cambia aunque cambie Message. Details lleva datos extra para el cliente
+// (conflictos, ids que faltan, la versión actual).
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Fields  map[string]string
+	Details map[string]any
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is compara por tipo y código, así errors.Is también reconoce las copias de With.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+// With devuelve una copia del error con un dato extra.
+func (e *Error) With(key string, value any) *Error {
+	clone := *e
+	clone.Details = make(map[string]any, len(e.Details)+1)
+	for k, v := range e.Details {
+		clone.Details[k] = v
+	}
+	clone.Details[key] = value
+	return &clone
+}
+
+func Invalid(code, message string) *Error {
+	return &Error{Kind: KindInvalid, Code: code, Message: message}
+}
+
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func Forbidden(code, message string) *Error {
+	return &Error{Kind: KindForbidden, Code: code, Message: message}
+}
+
+// Validation es un cambio que no pasa la validación. fields tiene, por cada
+// campo rechazado, el motivo.
+func Validation(fields map[string]string) *Error {
+	return &Error{Kind: KindValidation, Code: "invalid_fields", Message: "Invalid fields", Fields: fields}
+}
+
+// MissingReferences se devuelve cuando una petición referencia ids de resource
+// que no existen. El mensaje se mantiene como "<Recurso> not found".
+func MissingReferences(resource string, ids []uint) *Error {
+	return &Error{
+		Kind:    KindValidation,
+		Code:    "missing_references",
+		Message: resource + " not found",
+		Details: map[string]any{"resource": strings.ToLower(resource), "missing_ids": ids},
+	}
+}
+
+// Errores que se repiten en varios casos de uso.
+var (
+	ErrIncompleteFields = Invalid("incomplete_fields", "Incomplete fields")
+	ErrInvalidResource  = Invalid("invalid_resource", "Invalid resource")
+	ErrInvalidPatch     = Invalid("invalid_patch", "Invalid patch")
+	ErrInvalidHexColor  = Invalid("invalid_hex_color", "Invalid hex color")
+	ErrInvalidDateRange = Invalid("invalid_date_range", "Invalid date range")
+	ErrInvalidColors    = Validation(map[string]string{"colors": "must be a list of hex colors"})
+	ErrInvalidWeekdays  = Validation(map[string]string{"preferred_weekdays": "must be a list of days from 0 (sunday) to 6"})
+
+	ErrInvalidToken = &Error{Kind: KindUnauthorized, Code: "invalid_token", Message: "invalid token"}
+	ErrUnauthorized = &Error{Kind: KindUnauthorized, Code: "unauthorized", Message: "You don't have permission"}
+	ErrForbidden    = Forbidden("forbidden", "You don't have permission")
+
+	ErrServiceNotFound      = NotFound("service_not_found", "Service not found")
+	ErrSongNotFound         = NotFound("song_not_found", "Song not found")
+	ErrUserNotFound         = NotFound("user_not_found", "User not found")
+	ErrPositionNotFound     = NotFound("position_not_found", "Position not found")
+	ErrOutfitNotFound       = NotFound("outfit_not_found", "Outfit not found")
+	ErrAssignmentNotFound   = NotFound("assignment_not_found", "Assignment not found")
+	ErrRehearsalNotFound    = NotFound("rehearsal_not_found", "Rehearsal not found")
+	ErrSwapNotFound         = NotFound("swap_request_not_found", "Swap request not found")
+	ErrRunSheetItemNotFound = NotFound("run_sheet_item_not_found", "Run sheet item not found")
+	ErrJobNotFound          = NotFound("job_not_found", "Job not found")
+
+	ErrAssignmentConflicts = Conflict("assignment_conflicts", "Assignment conflicts")
+	ErrAlreadyAssigned     = Conflict("user_already_assigned", "User already assigned to service")
+	ErrSwapModified        = Conflict("swap_request_modified", "Swap request was modified, try again")
+	ErrSwapNotClaimed      = Conflict("swap_request_not_claimed", "Swap request is not claimed")
+	ErrStatusChanged       = Conflict("assignment_status_changed", "Assignment status was modified, try again")
+	ErrLiveSessionModified = Conflict("live_session_modified", "Live session was modified, try again")
+	ErrJobLockLost         = Conflict("job_lock_lost", "Job is no longer locked by this worker")
+	ErrSongInSetlists      = Conflict("song_in_setlists", "Song is still in service setlists")
+
+	ErrVersionMismatch = &Error{Kind: KindStale, Code: "version_mismatch", Message: "Version mismatch"}
+)
@@ -0,0 +1,125 @@
+package models
+
+// Recursos que se pueden borrar y cuyo impacto se puede previsualizar.
+const (
+	ResourceSong     = "song"
+	ResourceUser     = "user"
+	ResourceOutfit   = "outfit"
+	ResourcePosition = "position"
+	ResourceService  = "service"
+)
+
+// Qué pasa con las filas que apuntan a un recurso cuando se borra.
+const (
+	// OnDeleteCascade borra las filas que lo referencian.
+	OnDeleteCascade = "cascade"
+	// OnDeleteRestrict impide el borrado mientras existan referencias.
+	OnDeleteRestrict = "restrict"
+	// OnDeleteClear deja la referencia opcional en 0 (sin asignar).
+	OnDeleteClear = "clear"
+	// OnDeleteKeep conserva las filas: el recurso puede ir a la papelera pero no
+	// se purga mientras existan referencias.
+	OnDeleteKeep = "keep"
+)
+
+// Reference es una columna que apunta a un recurso.
+type Reference struct {
+	Table    string `json:"table"`
+	Column   string `json:"column"`
+	OnDelete string `json:"on_delete"`
+	// ByService indica que la tabla tiene service_id, para listar los servicios afectados.
+	ByService bool `json:"-"`
+}
+
+// References describe las reglas de borrado de cada recurso. Las de cascade,
+// restrict y keep son foreign keys (migraciones 0002 y 0012); las de clear las
+// aplican los repositorios al borrar, porque esas columnas usan 0 como "sin asignar".
+var References = map[string][]Reference{
+	ResourceSong: {
+		{Table: "service_songs", Column: "song_id", OnDelete: OnDeleteKeep, ByService: true},
+		{Table: "run_sheet_items", Column: "song_id", OnDelete: OnDeleteClear, ByService: true},
+	},
+	ResourceUser: {
+		{Table: "service_users", Column: "user_id", OnDelete: OnDeleteCascade, ByService: true},
+		{Table: "service_user_status_changes", Column: "user_id", OnDelete: OnDeleteCascade, ByService: true},
+		{Table: "swap_requests", Column: "requester_id", OnDelete: OnDeleteCascade, ByService: true},
+		{Table: "swap_requests", Column: "claimed_by", OnDelete: OnDeleteClear, ByService: true},
+		{Table: "swap_request_targets", Column: "user_id", OnDelete: OnDeleteCascade},
+		{Table: "run_sheet_items", Column: "responsible_id", OnDelete: OnDeleteClear, ByService: true},
+		{Table: "user_blackouts", Column: "user_id", OnDelete: OnDeleteCascade},
+		{Table: "rehearsal_attendees", Column: "user_id", OnDelete: OnDeleteCascade},
+		{Table: "attendances", Column: "user_id", OnDelete: OnDeleteCascade},
+		{Table: "notifications", Column: "user_id", OnDelete: OnDeleteCascade},
+		{Table: "notification_preferences", Column: "user_id", OnDelete: OnDeleteCascade},
+		{Table: "calendar_tokens", Column: "user_id", OnDelete: OnDeleteCascade},
+	},
+	ResourceOutfit: {
+		{Table: "service_outfits", Column: "outfit_id", OnDelete: OnDeleteRestrict, ByService: true},
+		{Table: "service_dress_codes", Column: "outfit_id", OnDelete: OnDeleteRestrict, ByService: true},
+	},
+	ResourcePosition: {
+		{Table: "service_positions", Column: "position_id", OnDelete: OnDeleteRestrict, ByService: true},
+		{Table: "service_dress_codes", Column: "position_id", OnDelete: OnDeleteRestrict, ByService: true},
+		{Table: "service_users", Column: "position_id", OnDelete: OnDeleteClear, ByService: true},
+		{Table: "swap_requests", Column: "position_id", OnDelete: OnDeleteClear, ByService: true},
+	},
+	ResourceService: {
+		{Table: "service_songs", Column: "service_id", OnDelete: OnDeleteCascade},
+		{Table: "service_users", Column: "service_id", OnDelete: OnDeleteCascade},
+		{Table: "service_user_status_changes", Column: "service_id", OnDelete: OnDeleteCascade},
+		{Table: "service_outfits", Column: "service_id", OnDelete: OnDeleteCascade},
+		{Table: "service_positions", Column: "service_id", OnDelete: OnDeleteCascade},
+		{Table: "service_dress_codes", Column: "service_id", OnDelete: OnDeleteCascade},
+		{Table: "run_sheet_items", Column: "service_id", OnDelete: OnDeleteCascade},
+		{Table: "live_sessions", Column: "service_id", OnDelete: OnDeleteCascade},
+		{Table: "swap_requests", Column: "service_id", OnDelete: OnDeleteCascade},
+		{Table: "rehearsal_services", Column: "service_id", OnDelete: OnDeleteCascade},
+	},
+}
+
+// ResourceTables es la tabla de cada recurso.
+var ResourceTables = map[string]string{
+	ResourceSong:     "songs",
+	ResourceUser:     "users",
+	ResourceOutfit:   "outfits",
+	ResourcePosition: "positions",
+	ResourceService:  "services",
+}
+
+// ImpactEffect es cuántas filas de una tabla se verían afectadas.
+type ImpactEffect struct {
+	Reference
+	Count int64 `json:"count"`
+}
+
+type ImpactedService struct {
+	ID        uint   `json:"id"`
+	Name      string `json:"name"`
+	StartTime string `json:"start_time"`
+}
+
+// DeleteImpact es la previsualización de lo que rompería borrar un recurso.
+type DeleteImpact struct {
+	Resource string `json:"resource"`
+	ID       uint   `json:"id"`
+	// Allowed es false si alguna referencia restrict lo impide.
+	Allowed  bool              `json:"allowed"`
+	Effects  []ImpactEffect    `json:"effects"`
+	Services []ImpactedService `json:"services"`
+}
+
+// MissingIDs devuelve, sin repetir y en orden, los ids de requested que no están en found.
+func MissingIDs(requested []uint, found []uint) []uint {
+	present := make(map[uint]bool, len(found))
+	for _, id := range found {
+		present[id] = true
+	}
+	missing := []uint{}
+	for _, id := range requested {
+		if !present[id] {
+			present[id] = true
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
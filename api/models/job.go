@@ -0,0 +1,54 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Estados de un trabajo en segundo plano.
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	// JobDead es un trabajo que agotó sus intentos; solo se reintenta a mano.
+	JobDead = "dead"
+)
+
+func IsJobStatus(value string) bool {
+	switch value {
+	case JobQueued, JobRunning, JobSucceeded, JobDead:
+		return true
+	}
+	return false
+}
+
+// Job es un trabajo persistido en la cola. Payload es el JSON que recibe el
+// handler registrado para Type.
+type Job struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	Type        string          `json:"type" gorm:"index"`
+	Payload     json.RawMessage `json:"payload" gorm:"type:jsonb"`
+	Status      string          `json:"status" gorm:"index:idx_jobs_pending,priority:1;default:queued"`
+	RunAt       time.Time       `json:"run_at" gorm:"column:run_at;index:idx_jobs_pending,priority:2"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts" gorm:"column:max_attempts"`
+	LastError   string          `json:"last_error" gorm:"column:last_error"`
+	LockedBy    string          `json:"locked_by" gorm:"column:locked_by"`
+	LockedAt    *time.Time      `json:"locked_at" gorm:"column:locked_at"`
+	FinishedAt  *time.Time      `json:"finished_at" gorm:"column:finished_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// JobOptions ajusta un trabajo al encolarlo. Los valores en cero usan los del sistema.
+type JobOptions struct {
+	RunAt       time.Time
+	MaxAttempts int
+}
+
+// JobFilter filtra el listado de trabajos del panel de administración.
+type JobFilter struct {
+	Status string
+	Type   string
+	Limit  int
+}
@@ -0,0 +1,80 @@
+package models
+
+import "time"
+
+// LiveSession guarda en qué ítem del orden va el servicio mientras está en vivo.
+type LiveSession struct {
+	ServiceID     uint       `json:"service_id" gorm:"primaryKey;column:service_id"`
+	CurrentIndex  int        `json:"current_index" gorm:"column:current_index"`
+	StartedAt     time.Time  `json:"started_at" gorm:"column:started_at"`
+	ItemStartedAt time.Time  `json:"item_started_at" gorm:"column:item_started_at"`
+	OperatorID    uint       `json:"operator_id" gorm:"column:operator_id"`
+	EndedAt       *time.Time `json:"ended_at" gorm:"column:ended_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	// Version sube con cada movimiento; evita que dos operadores se pisen.
+	Version uint `json:"-" gorm:"not null;default:1"`
+}
+
+func (s *LiveSession) IsActive() bool {
+	return s.EndedAt == nil
+}
+
+// LiveState es lo que reciben los clientes conectados. DriftSeconds es
+// positivo cuando vamos atrasados respecto del horario planeado y negativo
+// cuando vamos adelantados.
+type LiveState struct {
+	ServiceID            uint               `json:"service_id"`
+	Active               bool               `json:"active"`
+	CurrentIndex         int                `json:"current_index"`
+	CurrentItem          *TimedRunSheetItem `json:"current_item"`
+	NextItem             *TimedRunSheetItem `json:"next_item"`
+	TotalItems           int                `json:"total_items"`
+	StartedAt            time.Time          `json:"started_at"`
+	ItemStartedAt        time.Time          `json:"item_started_at"`
+	ItemElapsedSeconds   int                `json:"item_elapsed_seconds"`
+	ItemRemainingSeconds int                `json:"item_remaining_seconds"`
+	DriftSeconds         int                `json:"drift_seconds"`
+	ServerTime           time.Time          `json:"server_time"`
+}
+
+// BuildLiveState calcula los tiempos del ítem actual a partir del orden y la sesión.
+// Si el servicio no tiene horario válido, el plan se cuenta desde el inicio de la sesión.
+func BuildLiveState(session *LiveSession, sheet *RunSheet, now time.Time) *LiveState {
+	state := &LiveState{
+		ServiceID:     session.ServiceID,
+		Active:        session.IsActive(),
+		CurrentIndex:  session.CurrentIndex,
+		TotalItems:    len(sheet.Items),
+		StartedAt:     session.StartedAt,
+		ItemStartedAt: session.ItemStartedAt,
+		ServerTime:    now,
+	}
+	if session.CurrentIndex < 0 || session.CurrentIndex >= len(sheet.Items) {
+		return state
+	}
+
+	current := sheet.Items[session.CurrentIndex]
+	state.CurrentItem = &current
+	if session.CurrentIndex+1 < len(sheet.Items) {
+		next := sheet.Items[session.CurrentIndex+1]
+		state.NextItem = &next
+	}
+
+	elapsed := int(now.Sub(session.ItemStartedAt).Seconds())
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	state.ItemElapsedSeconds = elapsed
+	state.ItemRemainingSeconds = current.DurationSeconds - elapsed
+
+	plannedStart := session.StartedAt.Add(time.Duration(current.StartOffsetSeconds) * time.Second)
+	if current.PlannedStart != nil {
+		plannedStart = *current.PlannedStart
+	}
+	drift := int(session.ItemStartedAt.Sub(plannedStart).Seconds())
+	if elapsed > current.DurationSeconds {
+		drift += elapsed - current.DurationSeconds
+	}
+	state.DriftSeconds = drift
+	return state
+}
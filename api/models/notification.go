@@ -0,0 +1,90 @@
+package models
+
+import "time"
+
+// Eventos que generan notificaciones.
+const (
+	NotifyAssigned         = "assigned"
+	NotifySetlistChanged   = "setlist_changed"
+	NotifyServiceUpdated   = "service_updated"
+	NotifyServiceCancelled = "service_cancelled"
+	NotifySwapRequested    = "swap_requested"
+	NotifyReminder         = "reminder"
+	NotifyPendingNudge     = "pending_nudge"
+	NotifyUnfilledDigest   = "unfilled_digest"
+)
+
+var NotificationEvents = []string{
+	NotifyAssigned,
+	NotifySetlistChanged,
+	NotifyServiceUpdated,
+	NotifyServiceCancelled,
+	NotifySwapRequested,
+	NotifyReminder,
+	NotifyPendingNudge,
+	NotifyUnfilledDigest,
+}
+
+func IsNotificationEvent(value string) bool {
+	for _, e := range NotificationEvents {
+		if e == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Canales de entrega.
+const (
+	ChannelInApp   = "in_app"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// NotificationMessage es el contenido de una notificación antes de repartirla.
+type NotificationMessage struct {
+	Title     string
+	Body      string
+	ServiceID uint
+	Link      string
+}
+
+// Notification es una entrada del inbox de un usuario.
+type Notification struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"column:user_id;index"`
+	Event     string     `json:"event"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	ServiceID uint       `json:"service_id" gorm:"column:service_id"`
+	Link      string     `json:"link"`
+	ReadAt    *time.Time `json:"read_at" gorm:"column:read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// NotificationPreference indica por qué canales quiere el usuario cada evento.
+// Sin fila guardada se usa DefaultNotificationPreference.
+type NotificationPreference struct {
+	UserID  uint   `json:"user_id" gorm:"primaryKey;column:user_id"`
+	Event   string `json:"event" gorm:"primaryKey"`
+	InApp   bool   `json:"in_app" gorm:"column:in_app"`
+	Email   bool   `json:"email"`
+	Webhook bool   `json:"webhook"`
+}
+
+func DefaultNotificationPreference(userID uint, event string) NotificationPreference {
+	return NotificationPreference{UserID: userID, Event: event, InApp: true, Email: true, Webhook: false}
+}
+
+// Allows indica si la preferencia habilita el canal.
+func (p NotificationPreference) Allows(channel string) bool {
+	switch channel {
+	case ChannelInApp:
+		return p.InApp
+	case ChannelEmail:
+		return p.Email
+	case ChannelWebhook:
+		return p.Webhook
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var hexColorRegex = regexp.MustCompile(`^#([0-9a-fA-F]{6}|[0-9a-fA-F]{3})$`)
+
+// HexColors es la paleta de un outfit; se guarda como JSON en una columna de texto.
+type HexColors []string
+
+func (h HexColors) Value() (driver.Value, error) {
+	if h == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(h)
+	return string(b), err
+}
+
+func (h *HexColors) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*h = HexColors{}
+		return nil
+	case string:
+		return h.unmarshal([]byte(v))
+	case []byte:
+		return h.unmarshal(v)
+	default:
+		return ErrInvalidColors
+	}
+}
+
+func (h *HexColors) unmarshal(data []byte) error {
+	if err := json.Unmarshal(data, h); err != nil {
+		return ErrInvalidColors
+	}
+	return nil
+}
+
+// Normalize valida cada color y lo deja en formato #rrggbb en minúsculas.
+func (h HexColors) Normalize() (HexColors, error) {
+	normalized := make(HexColors, 0, len(h))
+	for _, c := range h {
+		c = strings.TrimSpace(c)
+		if !strings.HasPrefix(c, "#") {
+			c = "#" + c
+		}
+		if !hexColorRegex.MatchString(c) {
+			return nil, ErrInvalidHexColor
+		}
+		c = strings.ToLower(c)
+		if len(c) == 4 {
+			c = "#" + string([]byte{c[1], c[1], c[2], c[2], c[3], c[3]})
+		}
+		normalized = append(normalized, c)
+	}
+	return normalized, nil
+}
+
+// Outfit es una paleta de vestimenta del catálogo.
+type Outfit struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name"`
+	Colors      HexColors `json:"colors" gorm:"type:text"`
+	Description string    `json:"description"`
+	ImageURL    string    `json:"image_url" gorm:"column:image_url"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
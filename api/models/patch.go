@@ -0,0 +1,86 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+)
+
+// ApplyMergePatch aplica un JSON Merge Patch (RFC 7396) sobre la representación
+// JSON de doc y decodifica el resultado en out, que debe estar vacío. Solo se
+// aceptan las claves de editable; null deja el campo en su valor vacío.
+// Devuelve las claves que traía el patch.
+func ApplyMergePatch(doc any, patch json.RawMessage, editable []string, out any) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(patch, &fields); err != nil || fields == nil {
+		return nil, ErrInvalidPatch
+	}
+
+	allowed := make(map[string]bool, len(editable))
+	for _, name := range editable {
+		allowed[name] = true
+	}
+	invalid := make(map[string]string)
+	for name := range fields {
+		if !allowed[name] {
+			invalid[name] = "not editable"
+		}
+	}
+	if len(invalid) > 0 {
+		return nil, Validation(invalid)
+	}
+
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var target map[string]any
+	if err := json.Unmarshal(raw, &target); err != nil {
+		return nil, err
+	}
+	for name, value := range fields {
+		var decoded any
+		if err := json.Unmarshal(value, &decoded); err != nil {
+			return nil, ErrInvalidPatch
+		}
+		if decoded == nil {
+			delete(target, name)
+			continue
+		}
+		target[name] = mergeValue(target[name], decoded)
+	}
+
+	merged, err := json.Marshal(target)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	if err := decoder.Decode(out); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, Validation(map[string]string{typeErr.Field: "invalid type"})
+		}
+		return nil, err
+	}
+	return fields, nil
+}
+
+// mergeValue mezcla objetos de forma recursiva; cualquier otro valor reemplaza al actual.
+func mergeValue(current any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	currentObject, ok := current.(map[string]any)
+	if !ok {
+		currentObject = make(map[string]any)
+	}
+	for name, value := range patchObject {
+		if value == nil {
+			delete(currentObject, name)
+			continue
+		}
+		currentObject[name] = mergeValue(currentObject[name], value)
+	}
+	return currentObject
+}
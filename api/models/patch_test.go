@@ -0,0 +1,118 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type patchDoc struct {
+	Name  string         `json:"name"`
+	Note  string         `json:"note"`
+	Count int            `json:"count"`
+	Meta  map[string]any `json:"meta"`
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	doc := patchDoc{
+		Name:  "Domingo",
+		Note:  "ensayo 9hs",
+		Count: 3,
+		Meta:  map[string]any{"a": 1.0, "b": 2.0},
+	}
+	editable := []string{"name", "note", "count", "meta"}
+
+	tests := []struct {
+		name   string
+		patch  string
+		want   patchDoc
+		fields []string
+	}{
+		{
+			name:   "replaces only the given fields",
+			patch:  `{"name":"Sábado"}`,
+			want:   patchDoc{Name: "Sábado", Note: "ensayo 9hs", Count: 3, Meta: map[string]any{"a": 1.0, "b": 2.0}},
+			fields: []string{"name"},
+		},
+		{
+			name:   "null removes the field",
+			patch:  `{"note":null,"count":null}`,
+			want:   patchDoc{Name: "Domingo", Meta: map[string]any{"a": 1.0, "b": 2.0}},
+			fields: []string{"count", "note"},
+		},
+		{
+			name:   "nested objects merge",
+			patch:  `{"meta":{"b":3,"c":4}}`,
+			want:   patchDoc{Name: "Domingo", Note: "ensayo 9hs", Count: 3, Meta: map[string]any{"a": 1.0, "b": 3.0, "c": 4.0}},
+			fields: []string{"meta"},
+		},
+		{
+			name:   "null inside a nested object removes the key",
+			patch:  `{"meta":{"a":null}}`,
+			want:   patchDoc{Name: "Domingo", Note: "ensayo 9hs", Count: 3, Meta: map[string]any{"b": 2.0}},
+			fields: []string{"meta"},
+		},
+		{
+			name:   "empty patch keeps everything",
+			patch:  `{}`,
+			want:   doc,
+			fields: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got patchDoc
+			fields, err := ApplyMergePatch(doc, json.RawMessage(tt.patch), editable, &got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if len(fields) != len(tt.fields) {
+				t.Errorf("got fields %v, want %v", fields, tt.fields)
+			}
+			for _, name := range tt.fields {
+				if _, ok := fields[name]; !ok {
+					t.Errorf("field %q missing from %v", name, fields)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyMergePatchErrors(t *testing.T) {
+	doc := patchDoc{Name: "Domingo", Count: 3}
+
+	tests := []struct {
+		name  string
+		patch string
+		kind  ErrorKind
+		field string
+	}{
+		{name: "not editable", patch: `{"note":"x"}`, kind: KindValidation, field: "note"},
+		{name: "wrong type", patch: `{"count":"tres"}`, kind: KindValidation, field: "count"},
+		{name: "not an object", patch: `["name"]`, kind: KindInvalid},
+		{name: "null document", patch: `null`, kind: KindInvalid},
+		{name: "invalid json", patch: `{"name":`, kind: KindInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got patchDoc
+			_, err := ApplyMergePatch(doc, json.RawMessage(tt.patch), []string{"name", "count"}, &got)
+			var domainErr *Error
+			if !errors.As(err, &domainErr) {
+				t.Fatalf("got %v, want a *models.Error", err)
+			}
+			if domainErr.Kind != tt.kind {
+				t.Errorf("got kind %q, want %q", domainErr.Kind, tt.kind)
+			}
+			if tt.field != "" {
+				if _, ok := domainErr.Fields[tt.field]; !ok {
+					t.Errorf("got fields %v, want %q", domainErr.Fields, tt.field)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+// Position es un puesto del catálogo (voces, teclado, batería, sonido, proyección...).
+type Position struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"uniqueIndex"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// ServicePosition es un cupo de un puesto dentro de un servicio.
+type ServicePosition struct {
+	ServiceID  uint `json:"service_id" gorm:"primaryKey;column:service_id"`
+	PositionID uint `json:"position_id" gorm:"primaryKey;column:position_id"`
+	Required   int  `json:"required"`
+}
+
+// PositionSlot resume cuántos cupos de un puesto están cubiertos en un servicio.
+type PositionSlot struct {
+	PositionID uint   `json:"position_id"`
+	Name       string `json:"name"`
+	Required   int    `json:"required"`
+	Filled     int    `json:"filled"`
+	Unfilled   int    `json:"unfilled"`
+	UserIDs    []uint `json:"user_ids"`
+}
+
+// BuildPositionSlots cruza los cupos del servicio con las asignaciones. Los
+// usuarios con un puesto que no tiene cupo también aparecen, con Required 0.
+func BuildPositionSlots(positions []Position, slots []ServicePosition, assignments []ServiceUser) []PositionSlot {
+	names := make(map[uint]string, len(positions))
+	for _, p := range positions {
+		names[p.ID] = p.Name
+	}
+
+	result := []PositionSlot{}
+	index := make(map[uint]int)
+	for _, sp := range slots {
+		index[sp.PositionID] = len(result)
+		result = append(result, PositionSlot{
+			PositionID: sp.PositionID,
+			Name:       names[sp.PositionID],
+			Required:   sp.Required,
+			UserIDs:    []uint{},
+		})
+	}
+
+	for _, a := range assignments {
+		if a.PositionID == 0 || !a.IsActive() {
+			continue
+		}
+		i, ok := index[a.PositionID]
+		if !ok {
+			i = len(result)
+			index[a.PositionID] = i
+			result = append(result, PositionSlot{
+				PositionID: a.PositionID,
+				Name:       names[a.PositionID],
+				UserIDs:    []uint{},
+			})
+		}
+		result[i].Filled++
+		result[i].UserIDs = append(result[i].UserIDs, a.UserID)
+	}
+
+	for i := range result {
+		if result[i].Required > result[i].Filled {
+			result[i].Unfilled = result[i].Required - result[i].Filled
+		}
+	}
+	return result
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// Rehearsal es un ensayo ligado a uno o más servicios que comparten repertorio.
+// Los asistentes salen del equipo de esos servicios.
+type Rehearsal struct {
+	ID        uint                `json:"id" gorm:"primaryKey"`
+	Name      string              `json:"name"`
+	StartTime time.Time           `json:"start_time" gorm:"column:start_time"`
+	EndTime   time.Time           `json:"end_time" gorm:"column:end_time"`
+	Location  string              `json:"location"`
+	Notes     string              `json:"notes"`
+	CreatedBy uint                `json:"created_by" gorm:"column:created_by"`
+	Services  []RehearsalService  `json:"services" gorm:"foreignKey:RehearsalID"`
+	Attendees []RehearsalAttendee `json:"attendees" gorm:"foreignKey:RehearsalID"`
+	CreatedAt time.Time           `json:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at"`
+}
+
+func (r *Rehearsal) Validate() error {
+	if r.StartTime.IsZero() || r.EndTime.IsZero() {
+		return ErrIncompleteFields
+	}
+	if !r.EndTime.After(r.StartTime) {
+		return Invalid("invalid_time_range", "end_time must be after start_time")
+	}
+	return nil
+}
+
+func (r *Rehearsal) ServiceIDs() []uint {
+	ids := make([]uint, 0, len(r.Services))
+	for _, s := range r.Services {
+		ids = append(ids, s.ServiceID)
+	}
+	return ids
+}
+
+type RehearsalService struct {
+	RehearsalID uint `json:"rehearsal_id" gorm:"primaryKey;column:rehearsal_id"`
+	ServiceID   uint `json:"service_id" gorm:"primaryKey;column:service_id"`
+}
+
+// RehearsalAttendee guarda la respuesta (RSVP) de cada integrante al ensayo.
+// RSVP usa los mismos valores que ServiceUser.Status: pending, accepted, declined.
+// La asistencia real se registra en Attendance.
+type RehearsalAttendee struct {
+	RehearsalID uint       `json:"rehearsal_id" gorm:"primaryKey;column:rehearsal_id"`
+	UserID      uint       `json:"user_id" gorm:"primaryKey;column:user_id"`
+	RSVP        string     `json:"rsvp" gorm:"column:rsvp;default:pending"`
+	RSVPNote    string     `json:"rsvp_note" gorm:"column:rsvp_note"`
+	RespondedAt *time.Time `json:"responded_at" gorm:"column:responded_at"`
+}
+
+// RehearsalDetail agrega al ensayo el repertorio de sus servicios.
+type RehearsalDetail struct {
+	Rehearsal
+	SongIDs []uint `json:"song_ids"`
+}
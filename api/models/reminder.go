@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Tipos de recordatorio que manda el scheduler.
+const (
+	ReminderUpcoming = "upcoming"
+	ReminderPending  = "pending_nudge"
+	ReminderDigest   = "unfilled_digest"
+)
+
+// EventDigest identifica al resumen diario de puestos sin cubrir; EventID es la fecha como YYYYMMDD.
+const EventDigest = "digest"
+
+// ReminderLog registra cada recordatorio enviado. El índice único hace que
+// reiniciar la API o correr varias instancias no repita el envío.
+type ReminderLog struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Kind      string    `json:"kind" gorm:"uniqueIndex:idx_reminder_once"`
+	EventType string    `json:"event_type" gorm:"column:event_type;uniqueIndex:idx_reminder_once"`
+	EventID   uint      `json:"event_id" gorm:"column:event_id;uniqueIndex:idx_reminder_once"`
+	UserID    uint      `json:"user_id" gorm:"column:user_id;uniqueIndex:idx_reminder_once"`
+	SentAt    time.Time `json:"sent_at" gorm:"column:sent_at"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Entidades con historial de revisiones.
+const (
+	RevisionSong    = ResourceSong
+	RevisionService = ResourceService
+)
+
+func IsRevisionEntity(value string) bool {
+	return value == RevisionSong || value == RevisionService
+}
+
+// Revision es una foto versionada de una canción (Song) o de la composición
+// completa de un servicio (ServiceComposition). Version empieza en 1 por entidad.
+type Revision struct {
+	ID         uint            `json:"id" gorm:"primaryKey"`
+	EntityType string          `json:"entity_type" gorm:"column:entity_type;uniqueIndex:idx_revision_version,priority:1"`
+	EntityID   uint            `json:"entity_id" gorm:"column:entity_id;uniqueIndex:idx_revision_version,priority:2"`
+	Version    int             `json:"version" gorm:"uniqueIndex:idx_revision_version,priority:3"`
+	Snapshot   json.RawMessage `json:"snapshot" gorm:"type:jsonb"`
+	ActorID    uint            `json:"actor_id" gorm:"column:actor_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// CompositionSong es una canción del repertorio. Order es su lugar en el orden
+// del servicio (0 si no está en el orden) y Key la tonalidad de la canción.
+type CompositionSong struct {
+	SongID uint   `json:"song_id"`
+	Name   string `json:"name"`
+	Order  int    `json:"order"`
+	Key    string `json:"key"`
+}
+
+type CompositionMember struct {
+	UserID     uint   `json:"user_id"`
+	PositionID uint   `json:"position_id"`
+	Status     string `json:"status"`
+}
+
+// ServiceComposition es lo que se versiona de un servicio.
+type ServiceComposition struct {
+	Name      string              `json:"name"`
+	StartTime string              `json:"start_time"`
+	EndTime   string              `json:"end_time"`
+	Songs     []CompositionSong   `json:"songs"`
+	Team      []CompositionMember `json:"team"`
+	OutfitIDs []uint              `json:"outfit_ids"`
+}
+
+type RevisionChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// RevisionItemsDiff compara una lista por su id: los que se agregaron, los que
+// se quitaron y los que siguen pero cambiaron.
+type RevisionItemsDiff struct {
+	Added   []any            `json:"added"`
+	Removed []any            `json:"removed"`
+	Changed []RevisionChange `json:"changed"`
+}
+
+// RevisionDiff es lo que cambió entre dos revisiones. Fields tiene los campos
+// simples; Songs, Team y Outfits solo vienen en los servicios.
+type RevisionDiff struct {
+	EntityType string                    `json:"entity_type"`
+	EntityID   uint                      `json:"entity_id"`
+	From       int                       `json:"from"`
+	To         int                       `json:"to"`
+	Fields     map[string]RevisionChange `json:"fields"`
+	Songs      *RevisionItemsDiff        `json:"songs,omitempty"`
+	Team       *RevisionItemsDiff        `json:"team,omitempty"`
+	Outfits    *RevisionItemsDiff        `json:"outfits,omitempty"`
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// RosterOptions son los parámetros del generador de turnos. Positions aplica a
+// todos los servicios del rango; ServicePositions lo reemplaza por servicio.
+// Sin ninguno de los dos se usan los cupos por puesto de cada servicio.
+type RosterOptions struct {
+	From             time.Time               `json:"from"`
+	To               time.Time               `json:"to"`
+	Positions        map[string]int          `json:"positions"`
+	ServicePositions map[uint]map[string]int `json:"service_positions"`
+}
+
+type RosterAssignment struct {
+	UserID     uint   `json:"user_id"`
+	Username   string `json:"username"`
+	Role       string `json:"role"`
+	PositionID uint   `json:"position_id,omitempty"`
+	Secondary  bool   `json:"secondary"`
+	// Reliability es el puntaje de asistencia (0 a 1) usado al elegir al candidato.
+	Reliability float64 `json:"reliability"`
+}
+
+type RosterUnfilled struct {
+	Role    string `json:"role"`
+	Missing int    `json:"missing"`
+}
+
+type RosterServiceDraft struct {
+	ServiceID   uint               `json:"service_id"`
+	ServiceName string             `json:"service_name"`
+	StartTime   string             `json:"start_time"`
+	Assignments []RosterAssignment `json:"assignments"`
+	Unfilled    []RosterUnfilled   `json:"unfilled"`
+}
+
+type RosterLoad struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Services int    `json:"services"`
+}
+
+// RosterDraft es la propuesta que revisa el admin antes de confirmarla.
+// Override guarda aunque el borrador tenga conflictos.
+type RosterDraft struct {
+	From     time.Time            `json:"from"`
+	To       time.Time            `json:"to"`
+	Services []RosterServiceDraft `json:"services"`
+	Load     []RosterLoad         `json:"load"`
+	Override bool                 `json:"override,omitempty"`
+}
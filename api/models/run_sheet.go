@@ -0,0 +1,102 @@
+package models
+
+import "time"
+
+// Tipos de ítem del orden del servicio.
+const (
+	RunItemSong         = "song"
+	RunItemPrayer       = "prayer"
+	RunItemAnnouncement = "announcement"
+	RunItemSermon       = "sermon"
+	RunItemOffering     = "offering"
+	RunItemVideo        = "video"
+	RunItemTransition   = "transition"
+)
+
+var RunItemTypes = []string{
+	RunItemSong,
+	RunItemPrayer,
+	RunItemAnnouncement,
+	RunItemSermon,
+	RunItemOffering,
+	RunItemVideo,
+	RunItemTransition,
+}
+
+func IsRunItemType(value string) bool {
+	for _, t := range RunItemTypes {
+		if t == value {
+			return true
+		}
+	}
+	return false
+}
+
+// RunSheetItem es un ítem del orden del servicio. Los ítems de tipo song
+// apuntan a una canción ya asignada al servicio (ServiceSong).
+type RunSheetItem struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	ServiceID       uint      `json:"service_id" gorm:"column:service_id;index"`
+	SortOrder       int       `json:"sort_order" gorm:"column:sort_order"`
+	Type            string    `json:"type"`
+	Title           string    `json:"title"`
+	DurationSeconds int       `json:"duration_seconds" gorm:"column:duration_seconds"`
+	ResponsibleID   uint      `json:"responsible_id" gorm:"column:responsible_id"`
+	SongID          uint      `json:"song_id" gorm:"column:song_id"`
+	Notes           string    `json:"notes"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// TimedRunSheetItem agrega al ítem cuándo empieza respecto del inicio del servicio.
+type TimedRunSheetItem struct {
+	RunSheetItem
+	StartOffsetSeconds int        `json:"start_offset_seconds"`
+	PlannedStart       *time.Time `json:"planned_start,omitempty"`
+	PlannedEnd         *time.Time `json:"planned_end,omitempty"`
+}
+
+// RunSheet es el orden completo con sus tiempos. OverrunSeconds es positivo
+// cuando los ítems no entran entre StartTime y EndTime.
+type RunSheet struct {
+	ServiceID        uint                `json:"service_id"`
+	StartTime        string              `json:"start_time"`
+	EndTime          string              `json:"end_time"`
+	Items            []TimedRunSheetItem `json:"items"`
+	TotalSeconds     int                 `json:"total_seconds"`
+	AvailableSeconds int                 `json:"available_seconds"`
+	OverrunSeconds   int                 `json:"overrun_seconds"`
+}
+
+// BuildRunSheet calcula el inicio de cada ítem y el total contra el horario del servicio.
+func BuildRunSheet(service *Service, items []RunSheetItem) *RunSheet {
+	sheet := &RunSheet{
+		ServiceID: service.ID,
+		StartTime: service.StartTime,
+		EndTime:   service.EndTime,
+		Items:     make([]TimedRunSheetItem, 0, len(items)),
+	}
+
+	start, end, err := service.Window()
+	hasWindow := err == nil
+
+	offset := 0
+	for _, item := range items {
+		timed := TimedRunSheetItem{RunSheetItem: item, StartOffsetSeconds: offset}
+		if hasWindow {
+			itemStart := start.Add(time.Duration(offset) * time.Second)
+			itemEnd := itemStart.Add(time.Duration(item.DurationSeconds) * time.Second)
+			timed.PlannedStart = &itemStart
+			timed.PlannedEnd = &itemEnd
+		}
+		sheet.Items = append(sheet.Items, timed)
+		offset += item.DurationSeconds
+	}
+
+	sheet.TotalSeconds = offset
+	if hasWindow {
+		sheet.AvailableSeconds = int(end.Sub(start).Seconds())
+		sheet.OverrunSeconds = sheet.TotalSeconds - sheet.AvailableSeconds
+	}
+	return sheet
+}
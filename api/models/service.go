@@ -1,6 +1,13 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultServiceDuration se usa cuando un servicio no tiene hora de fin válida.
+const DefaultServiceDuration = 2 * time.Hour
 
 type Service struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
@@ -10,4 +17,93 @@ type Service struct {
 	CreatedBy uint      `json:"created_by" gorm:"column:created_by"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Version sube en cada edición, también al cambiar equipo, repertorio,
+	// outfits o puestos; es el ETag del servicio.
+	Version uint `json:"version" gorm:"not null;default:1"`
+	// DeletedAt marca el servicio como enviado a la papelera.
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+var serviceTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// ParseServiceTime interpreta las horas guardadas como texto (el frontend envía RFC3339).
+func ParseServiceTime(value string) (time.Time, error) {
+	for _, layout := range serviceTimeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, Invalid("invalid_service_time", "Invalid service time")
+}
+
+// Window devuelve el inicio y el fin del servicio. Si EndTime falta o es
+// anterior al inicio, se asume DefaultServiceDuration.
+func (s *Service) Window() (time.Time, time.Time, error) {
+	start, err := ParseServiceTime(s.StartTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := ParseServiceTime(s.EndTime)
+	if err != nil || !end.After(start) {
+		end = start.Add(DefaultServiceDuration)
+	}
+	return start, end, nil
+}
+
+// Overlaps indica si dos servicios se cruzan en el tiempo.
+func (s *Service) Overlaps(other *Service) bool {
+	start, end, err := s.Window()
+	if err != nil {
+		return false
+	}
+	otherStart, otherEnd, err := other.Window()
+	if err != nil {
+		return false
+	}
+	return start.Before(otherEnd) && otherStart.Before(end)
+}
+
+// ServiceMember es un integrante del equipo tal como lo muestra el detalle del servicio.
+type ServiceMember struct {
+	ID            uint   `json:"id"`
+	Username      string `json:"username"`
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	SecondaryRole string `json:"secondary_role"`
+	Status        string `json:"status"`
+	PositionID    uint   `json:"position_id"`
+}
+
+// ServiceOutfitDetail mantiene outfit_id junto al objeto completo para los
+// clientes que ya lo usan.
+type ServiceOutfitDetail struct {
+	Outfit
+	OutfitID uint `json:"outfit_id"`
+}
+
+// ServiceDetail es el servicio con sus canciones, equipo, outfits y cupos.
+type ServiceDetail struct {
+	Service
+	Songs     []Song                `json:"songs"`
+	Users     []ServiceMember       `json:"users"`
+	Outfits   []ServiceOutfitDetail `json:"outfits"`
+	Positions []PositionSlot        `json:"positions"`
+}
+
+// ServiceBundle es un servicio junto con su repertorio, equipo y outfits para
+// guardarlo completo en una sola transacción. Una lista ausente (nil) no se
+// toca; una lista vacía deja al servicio sin elementos de ese tipo.
+type ServiceBundle struct {
+	Service
+	Songs   []uint           `json:"songs"`
+	Users   []UserAssignment `json:"users"`
+	Outfits []uint           `json:"outfits"`
+	// Override guarda el equipo aunque haya conflictos de agenda.
+	Override bool `json:"override"`
 }
@@ -2,6 +2,7 @@
 package models
 
 type ServiceOutfit struct {
-	ServiceID uint `json:"service_id" gorm:"primaryKey"`
-	OutfitID  uint `json:"outfit_id" gorm:"primaryKey"`
+	ServiceID uint    `json:"service_id" gorm:"primaryKey"`
+	OutfitID  uint    `json:"outfit_id" gorm:"primaryKey"`
+	Outfit    *Outfit `json:"outfit,omitempty" gorm:"foreignKey:OutfitID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT"`
 }
@@ -1,7 +1,60 @@
 package models
 
+import "time"
+
+const (
+	StatusPending   = "pending"
+	StatusAccepted  = "accepted"
+	StatusDeclined  = "declined"
+	StatusCancelled = "cancelled"
+)
+
 type ServiceUser struct {
 	ServiceID uint   `json:"service_id" gorm:"primaryKey;column:service_id"`
 	UserID    uint   `json:"user_id" gorm:"primaryKey;column:user_id"`
 	Status    string `json:"status"`
+	// PositionID es el puesto que el usuario cubre en este servicio (0 = sin asignar).
+	PositionID      uint       `json:"position_id" gorm:"column:position_id;default:0"`
+	DeclineReason   string     `json:"decline_reason,omitempty" gorm:"column:decline_reason"`
+	StatusChangedAt *time.Time `json:"status_changed_at" gorm:"column:status_changed_at"`
+}
+
+// IsActive indica si la asignación sigue contando para el servicio.
+func (su *ServiceUser) IsActive() bool {
+	return su.Status != StatusDeclined && su.Status != StatusCancelled
+}
+
+// ServiceUserStatusChange es el historial de respuestas de un usuario a un servicio.
+type ServiceUserStatusChange struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ServiceID  uint      `json:"service_id" gorm:"column:service_id;index"`
+	UserID     uint      `json:"user_id" gorm:"column:user_id"`
+	FromStatus string    `json:"from_status" gorm:"column:from_status"`
+	ToStatus   string    `json:"to_status" gorm:"column:to_status"`
+	Reason     string    `json:"reason,omitempty"`
+	ChangedAt  time.Time `json:"changed_at" gorm:"column:changed_at"`
+}
+
+// UserAssignment es un usuario junto con el puesto que cubrirá.
+type UserAssignment struct {
+	UserID     uint `json:"user_id"`
+	PositionID uint `json:"position_id"`
+}
+
+const (
+	ConflictOverlap      = "overlap"
+	ConflictBlackout     = "blackout"
+	ConflictMonthlyLimit = "monthly_limit"
+)
+
+// AssignmentConflict describe por qué asignar a un usuario a un servicio choca
+// con su agenda.
+type AssignmentConflict struct {
+	UserID              uint   `json:"user_id"`
+	Type                string `json:"type"`
+	Message             string `json:"message"`
+	ConflictingService  uint   `json:"conflicting_service_id,omitempty"`
+	BlackoutID          uint   `json:"blackout_id,omitempty"`
+	ServicesInMonth     int    `json:"services_in_month,omitempty"`
+	MaxServicesPerMonth int    `json:"max_services_per_month,omitempty"`
 }
@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Song struct {
 	ID            uint      `json:"id" gorm:"primaryKey"`
@@ -23,4 +27,8 @@ type Song struct {
 	WindURL       string    `json:"wind_url" gorm:"column:wind_url"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
+	// Version sube en cada edición; es el ETag de la canción.
+	Version uint `json:"version" gorm:"not null;default:1"`
+	// DeletedAt marca la canción como enviada a la papelera.
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 }
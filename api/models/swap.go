@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+const (
+	SwapOpen      = "open"
+	SwapClaimed   = "claimed"
+	SwapApproved  = "approved"
+	SwapRejected  = "rejected"
+	SwapCancelled = "cancelled"
+)
+
+const (
+	SwapScopeUsers = "users"
+	SwapScopeRole  = "role"
+)
+
+// SwapRequest es la oferta de un usuario asignado para que otro cubra su lugar
+// en un servicio. Scope indica si se ofrece a usuarios puntuales (Targets) o a
+// todos los que tienen el mismo rol.
+type SwapRequest struct {
+	ID          uint                `json:"id" gorm:"primaryKey"`
+	ServiceID   uint                `json:"service_id" gorm:"column:service_id;index"`
+	RequesterID uint                `json:"requester_id" gorm:"column:requester_id"`
+	PositionID  uint                `json:"position_id" gorm:"column:position_id"`
+	Scope       string              `json:"scope"`
+	Role        string              `json:"role"`
+	Note        string              `json:"note"`
+	Status      string              `json:"status"`
+	ClaimedBy   uint                `json:"claimed_by" gorm:"column:claimed_by"`
+	Targets     []SwapRequestTarget `json:"targets" gorm:"foreignKey:SwapRequestID"`
+	Events      []SwapEvent         `json:"events,omitempty" gorm:"foreignKey:SwapRequestID"`
+	CreatedAt   time.Time           `json:"created_at"`
+	UpdatedAt   time.Time           `json:"updated_at"`
+}
+
+type SwapRequestTarget struct {
+	SwapRequestID uint `json:"swap_request_id" gorm:"primaryKey;column:swap_request_id"`
+	UserID        uint `json:"user_id" gorm:"primaryKey;column:user_id"`
+}
+
+// SwapEvent guarda cada paso del intercambio (creado, reclamado, aprobado...).
+type SwapEvent struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	SwapRequestID uint      `json:"swap_request_id" gorm:"column:swap_request_id;index"`
+	Action        string    `json:"action"`
+	ActorID       uint      `json:"actor_id" gorm:"column:actor_id"`
+	Note          string    `json:"note"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// IsTarget indica si la oferta fue dirigida explícitamente al usuario.
+func (s *SwapRequest) IsTarget(userID uint) bool {
+	for _, t := range s.Targets {
+		if t.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
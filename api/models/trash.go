@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Recursos que van a la papelera en vez de borrarse.
+var TrashResources = []string{ResourceSong, ResourceService, ResourceUser}
+
+func IsTrashResource(value string) bool {
+	for _, r := range TrashResources {
+		if r == value {
+			return true
+		}
+	}
+	return false
+}
+
+// TrashItem es un elemento de la papelera.
+type TrashItem struct {
+	Resource  string    `json:"resource"`
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+	// PurgeAt es cuándo se borra definitivamente.
+	PurgeAt time.Time `json:"purge_at"`
+}
@@ -1,6 +1,8 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
@@ -19,6 +21,64 @@ type User struct {
 	Lastname          string    `json:"lastname"`
 	ProfilePictureUrl string    `json:"profile_picture_url"`
 	SecondaryRole     string    `json:"secondary_role" gorm:"column:secondary_role"`
+	// MaxServicesPerMonth limita cuántos servicios se le asignan al mes (0 = sin límite).
+	MaxServicesPerMonth int `json:"max_services_per_month" gorm:"column:max_services_per_month;default:0"`
+	// PreferredWeekdays son los días en que prefiere servir (vacío = cualquiera).
+	PreferredWeekdays Weekdays `json:"preferred_weekdays" gorm:"column:preferred_weekdays;type:text"`
+	// PreferredServicesPerMonth es cuántos servicios al mes prefiere (0 = sin
+	// preferencia). No es un límite: pasarse solo lo deja detrás de otros.
+	PreferredServicesPerMonth int `json:"preferred_services_per_month" gorm:"column:preferred_services_per_month;default:0"`
+	// DeletedAt marca al usuario como enviado a la papelera.
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// Weekdays es una lista de días de la semana (0 = domingo); se guarda como JSON
+// en una columna de texto.
+type Weekdays []time.Weekday
+
+func (w Weekdays) Value() (driver.Value, error) {
+	if w == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(w)
+	return string(b), err
+}
+
+func (w *Weekdays) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*w = Weekdays{}
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), w)
+	case []byte:
+		return json.Unmarshal(v, w)
+	default:
+		return ErrInvalidWeekdays
+	}
+}
+
+// Valid indica si todos los días están entre domingo (0) y sábado (6).
+func (w Weekdays) Valid() bool {
+	for _, d := range w {
+		if d < time.Sunday || d > time.Saturday {
+			return false
+		}
+	}
+	return true
+}
+
+// Includes indica si day está en la lista. Una lista vacía incluye todos los días.
+func (w Weekdays) Includes(day time.Weekday) bool {
+	if len(w) == 0 {
+		return true
+	}
+	for _, d := range w {
+		if d == day {
+			return true
+		}
+	}
+	return false
 }
 
 type UserInput struct {
@@ -43,6 +103,12 @@ func hashPassword(user *User) error {
 	return nil
 }
 
+// SetPassword cambia la contraseña guardándola ya hasheada.
+func (user *User) SetPassword(password string) error {
+	user.Password = password
+	return hashPassword(user)
+}
+
 func (user *User) BeforeCreate(*gorm.DB) error {
 	return hashPassword(user)
 }
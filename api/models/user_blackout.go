@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// UserBlackout es un rango de fechas en el que el usuario no puede servir.
+type UserBlackout struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	UserID    uint      `json:"user_id" gorm:"column:user_id;index"`
+	StartDate time.Time `json:"start_date" gorm:"column:start_date"`
+	EndDate   time.Time `json:"end_date" gorm:"column:end_date"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Covers indica si el rango bloqueado se cruza con [start, end).
+func (b *UserBlackout) Covers(start, end time.Time) bool {
+	return b.StartDate.Before(end) && start.Before(b.EndDate)
+}
@@ -0,0 +1,114 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"melodiapp/models"
+)
+
+// UserLoader busca al dueño de una sesión. Lo provee la composición de la app
+// para que los handlers no consulten la base directamente.
+type UserLoader func(id uint) (*models.User, error)
+
+const (
+	userLoaderKey  = "userLoader"
+	currentUserKey = "authorizedUser"
+)
+
+// WithUserLoader deja el loader disponible para CurrentUser en cada request.
+func WithUserLoader(load UserLoader) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(userLoaderKey, load)
+		c.Next()
+	}
+}
+
+// CurrentUser devuelve el usuario del token Bearer de la request.
+func CurrentUser(c *gin.Context) (*models.User, error) {
+	return UserFromToken(c, GetTokenFromRequest(c))
+}
+
+// CurrentUserOrQueryToken es CurrentUser para los clientes que no pueden mandar
+// headers (EventSource): sin Bearer usa ?token=.
+func CurrentUserOrQueryToken(c *gin.Context) (*models.User, error) {
+	tokenStr := GetTokenFromRequest(c)
+	if tokenStr == "" {
+		tokenStr = c.Query("token")
+	}
+	return UserFromToken(c, tokenStr)
+}
+
+// RequireAdmin devuelve el usuario actual si es admin. Si no hay sesión o no es
+// admin deja el error para el middleware Errors y devuelve false.
+func RequireAdmin(c *gin.Context) (*models.User, bool) {
+	user, err := CurrentUser(c)
+	if err != nil {
+		Fail(c, err)
+		return nil, false
+	}
+	if user.Role != "admin" {
+		Fail(c, models.ErrForbidden)
+		return nil, false
+	}
+	return user, true
+}
+
+// UserFromToken valida el token, su sesión y carga al usuario. El resultado
+// queda guardado en el contexto para no repetir la búsqueda en la misma request.
+func UserFromToken(c *gin.Context, tokenStr string) (*models.User, error) {
+	if cached, ok := c.Get(currentUserKey); ok {
+		if user, ok := cached.(*models.User); ok {
+			return user, nil
+		}
+	}
+	if tokenStr == "" {
+		return nil, models.ErrInvalidToken
+	}
+
+	token, err := jwt.ParseWithClaims(tokenStr, &Payload{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("invalid token")
+		}
+		return []byte(os.Getenv("JWT_SECRET_KEY")), nil
+	})
+	if err != nil {
+		return nil, models.ErrInvalidToken
+	}
+
+	claims, _ := token.Claims.(*Payload)
+	session, exists := Sessions[claims.Session]
+	if !exists || session.ExpiryTime.Before(time.Now()) {
+		return nil, models.ErrUnauthorized
+	}
+
+	value, ok := c.Get(userLoaderKey)
+	if !ok {
+		return nil, fmt.Errorf("user loader not configured")
+	}
+	user, err := value.(UserLoader)(session.Uid)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, models.ErrUnauthorized
+	}
+
+	c.Set(currentUserKey, user)
+	return user, nil
+}
+
+// AuditContext devuelve el contexto de la petición con el usuario actual y su
+// IP, para que los casos de uso registren quién hizo cada cambio.
+func AuditContext(c *gin.Context) context.Context {
+	actor := models.AuditActor{IP: c.ClientIP()}
+	if user, err := CurrentUser(c); err == nil {
+		actor.UserID = user.ID
+	}
+	return models.WithAuditActor(c.Request.Context(), actor)
+}
@@ -0,0 +1,91 @@
+package shared
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"melodiapp/models"
+)
+
+// ProblemContentType es el tipo de las respuestas de error (RFC 7807).
+const ProblemContentType = "application/problem+json"
+
+// kindStatus traduce cada tipo de error del dominio a su status HTTP.
+var kindStatus = map[models.ErrorKind]int{
+	models.KindInvalid:      http.StatusBadRequest,
+	models.KindValidation:   http.StatusUnprocessableEntity,
+	models.KindUnauthorized: http.StatusUnauthorized,
+	models.KindForbidden:    http.StatusForbidden,
+	models.KindNotFound:     http.StatusNotFound,
+	models.KindConflict:     http.StatusConflict,
+	models.KindStale:        http.StatusPreconditionFailed,
+}
+
+// Errors responde el error que dejó el handler con Fail. Los *models.Error se
+// responden con su status y código; cualquier otro es un 500 que se registra
+// en el log sin mostrar el detalle al cliente.
+func Errors() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		err := c.Errors.Last().Err
+
+		var domain *models.Error
+		if !errors.As(err, &domain) {
+			log.Printf("[Error] %s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+			writeProblem(c, http.StatusInternalServerError, "internal_error", "Internal server error", nil)
+			return
+		}
+		status, ok := kindStatus[domain.Kind]
+		if !ok {
+			status = http.StatusInternalServerError
+		}
+		extra := gin.H{}
+		for key, value := range domain.Details {
+			extra[key] = value
+		}
+		if domain.Fields != nil {
+			extra["fields"] = domain.Fields
+		}
+		writeProblem(c, status, domain.Code, domain.Message, extra)
+	}
+}
+
+// Fail corta la request y deja err para que lo responda el middleware Errors.
+func Fail(c *gin.Context, err error) {
+	_ = c.Error(err)
+	c.Abort()
+}
+
+// ErrInvalidData es un cuerpo que no se pudo leer.
+var ErrInvalidData = models.Invalid("invalid_data", "Invalid data")
+
+// InvalidID es el error de un id de resource que no es un número.
+func InvalidID(resource string) error {
+	return models.Invalid("invalid_"+resource+"_id", "Invalid "+resource+" id")
+}
+
+// writeProblem arma el cuerpo problem+json. code es estable y es lo que deben
+// mirar los clientes; "error" repite el detalle para los que leen el formato
+// anterior. extra agrega miembros propios del error (fields, conflicts, etc.).
+func writeProblem(c *gin.Context, status int, code string, detail string, extra gin.H) {
+	body := gin.H{}
+	for key, value := range extra {
+		body[key] = value
+	}
+	body["type"] = "urn:melodiapp:problem:" + code
+	body["title"] = http.StatusText(status)
+	body["status"] = status
+	body["detail"] = detail
+	body["code"] = code
+	body["instance"] = c.Request.URL.Path
+	body["error"] = detail
+
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(status, body)
+}
@@ -0,0 +1,36 @@
+package shared
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetETag responde el header ETag a partir de la versión del registro.
+func SetETag(c *gin.Context, version uint) {
+	c.Header("ETag", `"`+strconv.FormatUint(uint64(version), 10)+`"`)
+}
+
+// IfMatch lee la versión que el cliente espera editar del header If-Match.
+// "*" acepta cualquier versión y devuelve 0. Si el header falta responde 428,
+// y si no es un ETag nuestro responde 412; en ambos casos devuelve false.
+func IfMatch(c *gin.Context) (uint, bool) {
+	raw := strings.TrimSpace(c.GetHeader("If-Match"))
+	if raw == "" {
+		writeProblem(c, http.StatusPreconditionRequired, "if_match_required", "If-Match header required", nil)
+		return 0, false
+	}
+	if raw == "*" {
+		return 0, true
+	}
+
+	raw = strings.Trim(strings.TrimPrefix(raw, "W/"), `"`)
+	version, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || version == 0 {
+		writeProblem(c, http.StatusPreconditionFailed, "invalid_if_match", "Invalid If-Match header", nil)
+		return 0, false
+	}
+	return uint(version), true
+}
@@ -1,17 +1,6 @@
 package shared
 
-import (
-	"fmt"
-	"net/http"
-	"os"
-	"time"
-
-	"melodiapp/database"
-	"melodiapp/models"
-
-	"github.com/gin-gonic/gin"
-	"github.com/golang-jwt/jwt/v5"
-)
+import "github.com/gin-gonic/gin"
 
 func Cors() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -21,7 +10,8 @@ func Cors() gin.HandlerFunc {
 			c.Header("Access-Control-Allow-Origin", origin)
 		}
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS, PATCH")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match")
+		c.Header("Access-Control-Expose-Headers", "ETag")
 		c.Header("Access-Control-Allow-Private-Network", "true")
 
 		if c.Request.Method == "OPTIONS" {
@@ -33,38 +23,13 @@ func Cors() gin.HandlerFunc {
 	}
 }
 
+// AuthenticateSession corta la request con 401 si no hay un usuario válido.
 func AuthenticateSession() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenStr := GetTokenFromRequest(c)
-		token, err := jwt.ParseWithClaims(tokenStr, &Payload{}, func(token *jwt.Token) (interface{}, error) {
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("invalid token")
-			}
-			return []byte(os.Getenv("JWT_SECRET_KEY")), nil
-		})
-
-		if err != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			return
-		}
-
-		claims, _ := token.Claims.(*Payload)
-
-		userData := Sessions[claims.Session]
-		if userData.ExpiryTime.Before(time.Now()) {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "expired session"})
-			return
-		}
-
-		var user models.User
-		tx := database.DBConn.Where("id=?", userData.Uid).Find(&user)
-		if tx.Error != nil {
-			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tx.Error.Error()})
+		if _, err := CurrentUser(c); err != nil {
+			Fail(c, err)
 			return
 		}
-
-		c.Set("authorizedUser", user)
-
 		c.Next()
 	}
 }
@@ -0,0 +1,30 @@
+package shared
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"melodiapp/models"
+)
+
+// MergePatchContentType es el tipo de un JSON Merge Patch (RFC 7396).
+const MergePatchContentType = "application/merge-patch+json"
+
+// MergePatchBody lee el cuerpo de un PATCH. Acepta application/merge-patch+json
+// y, por compatibilidad, application/json; con otro tipo responde 415.
+func MergePatchBody(c *gin.Context) (json.RawMessage, bool) {
+	mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
+	if mediaType != MergePatchContentType && mediaType != "application/json" {
+		writeProblem(c, http.StatusUnsupportedMediaType, "unsupported_media_type", "Content-Type must be "+MergePatchContentType, nil)
+		return nil, false
+	}
+	body, err := c.GetRawData()
+	if err != nil || !json.Valid(body) {
+		Fail(c, models.ErrInvalidPatch)
+		return nil, false
+	}
+	return body, true
+}